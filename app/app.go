@@ -4,16 +4,37 @@
 package app
 
 import (
+	"encoding/json"
+	"net/http"
 	"net/url"
+	"reflect"
+	"sync"
 	"syscall/js"
+	"unsafe"
 
 	"github.com/Nigel2392/jsext"
+	"github.com/Nigel2392/jsext-framework/app/shortcuts"
+	"github.com/Nigel2392/jsext-framework/client"
 	"github.com/Nigel2392/jsext-framework/components"
 	"github.com/Nigel2392/jsext-framework/components/loaders"
+	"github.com/Nigel2392/jsext-framework/components/misc"
+	"github.com/Nigel2392/jsext-framework/components/toasts"
+	"github.com/Nigel2392/jsext-framework/flags"
+	"github.com/Nigel2392/jsext-framework/i18n"
+	"github.com/Nigel2392/jsext-framework/offline"
 	"github.com/Nigel2392/jsext-framework/router"
 	"github.com/Nigel2392/jsext-framework/router/routes"
+	"github.com/Nigel2392/jsext-framework/router/rterr"
 	"github.com/Nigel2392/jsext-framework/router/vars"
+	"github.com/Nigel2392/jsext-framework/tabs"
+	"github.com/Nigel2392/jsext-framework/telemetry"
+	"github.com/Nigel2392/jsext-framework/theme"
+	"github.com/Nigel2392/jsext-framework/tokens"
+	"github.com/Nigel2392/jsext-framework/tokens/guard"
+	"github.com/Nigel2392/jsext-framework/tokens/idle"
+	"github.com/Nigel2392/jsext/console"
 	"github.com/Nigel2392/jsext/elements"
+	"github.com/Nigel2392/jsext/messages"
 	"github.com/Nigel2392/jsext/requester"
 )
 
@@ -40,31 +61,115 @@ var WAITER = make(chan struct{})
 type Application struct {
 	BaseElemSelector string
 	Router           components.Router
-	client           *requester.APIClient
+	HTTP             *client.Client
+	Cache            *client.Cache
 	Navbar           components.Component
 	Footer           components.Component
 	Loader           components.Loader
+	Toasts           *toasts.Container
+	I18n             *i18n.Translator
+	Shortcuts        *shortcuts.Manager
+	Network          *offline.Status
+	Queue            *offline.Queue
+	Tabs             *tabs.Tabs
+	Flags            *flags.Flags
+	Telemetry        *telemetry.Telemetry
+	Guard            *guard.Guard
+	Idle             *idle.Tracker
 	Base             jsext.Element
 	clientFunc       func() *requester.APIClient
-	onErr            func(err error)
-	onLoad           func()
-	beforeLoad       func()
+	onErr            []func(err error)
+	errObservers     []func(err error)
+	onLoad           []func()
+	beforeLoad       []func()
+	onPageChange     []func(vars.Vars, *url.URL)
+	afterPageChange  []func(vars.Vars, *url.URL)
+	onClient         []func(*requester.APIClient)
+	plugins          []Plugin
+	syncKeys         map[string]func(json.RawMessage) (any, error)
+	onSync           []func(*Application, string)
+	stopTracking     func()
 	Data             DataMap
 }
 
 // Initialize a http client with a loader for a new request.
+// Requests are made through the application's HTTP client, so they pass its interceptors
+// and the loader stays visible until all requests in flight have finished.
 func (a *Application) Client() *requester.APIClient {
+	var c *requester.APIClient
 	if a.clientFunc != nil {
-		a.client = a.clientFunc()
+		c = a.clientFunc()
 	} else {
-		a.client = requester.NewAPIClient()
+		c = requester.NewAPIClient()
 	}
-	a.client.Before(a.Loader.Show)
-	a.client.After(func() {
+	if !setHTTPClient(c, a.HTTP.HTTPClient()) {
+		// Only track the requests, each request tracks its own call.
+		var mu sync.Mutex
+		var tracked []func()
+		c.Before(func() {
+			mu.Lock()
+			tracked = append(tracked, a.HTTP.Track())
+			mu.Unlock()
+		})
+		c.After(func() {
+			mu.Lock()
+			var done = tracked[0]
+			tracked = tracked[1:]
+			mu.Unlock()
+			done()
+		})
+	}
+	for _, f := range a.onClient {
+		f(c)
+	}
+	return c
+}
+
+// Set the http.Client of the requester client.
+// The requester package does not export it, so it is set through reflection.
+func setHTTPClient(c *requester.APIClient, hc *http.Client) bool {
+	var field = reflect.ValueOf(c).Elem().FieldByName("client")
+	if !field.IsValid() || field.Type() != reflect.TypeOf(hc) {
+		return false
+	}
+	reflect.NewAt(field.Type(), unsafe.Pointer(field.UnsafeAddr())).Elem().Set(reflect.ValueOf(hc))
+	return true
+}
+
+// Add interceptors to the application's HTTP client.
+func (a *Application) Intercept(interceptors ...client.Interceptor) *Application {
+	a.HTTP.Use(interceptors...)
+	return a
+}
+
+// Cache GET responses of the application's HTTP client.
+// The cache interceptor is added to the end of the interceptor chain,
+// call this before adding interceptors which should only run on cache misses, such as retries.
+func (a *Application) EnableCache(opts *client.CacheOptions) *client.Cache {
+	a.Cache = client.NewCache(opts)
+	a.HTTP.Use(a.Cache.Interceptor())
+	return a.Cache
+}
+
+// Show the loader while the application's HTTP client is busy.
+func (a *Application) showLoader() {
+	if a.Loader != nil {
+		a.Loader.Show()
+	}
+}
+
+// Finalize the loader when the application's HTTP client is idle.
+func (a *Application) finalizeLoader() {
+	if a.Loader != nil {
 		a.Loader.Finalize()
-		a.client = nil
-	})
-	return a.client
+	}
+}
+
+// Function to be ran on every client created by Application.Client().
+// Useful for adding headers, error handlers, etc.
+func (a *Application) OnClient(f func(*requester.APIClient)) *Application {
+	a.onClient = append(a.onClient, f)
+	return a
 }
 
 // Set the client function.
@@ -103,16 +208,29 @@ func App(querySelector string, rt ...components.Router) *Application {
 		Base:             elem,
 		Loader:           loaders.NewLoader(querySelector, loaders.ID_LOADER, true, loaders.LoaderRing),
 		Data:             make(map[string]interface{}),
+		I18n:             i18n.Default,
+		Shortcuts:        shortcuts.New(),
+		Network:          offline.NewStatus(true),
 	}
+	// Activate the shortcuts of the current route.
+	r.Use(func(v vars.Vars, u *url.URL, rt *routes.Route, t rterr.ErrorThrower) bool {
+		a.Shortcuts.SetRoute(rt.Name)
+		return true
+	})
+	a.HTTP = client.New()
+	a.HTTP.OnBusy(a.showLoader)
+	a.HTTP.OnIdle(a.finalizeLoader)
 	return a
 }
 
 // Decide what happens on errors.
+// Multiple functions can be added, they are called in the order they were added.
+// The default error display is only used while no functions are added.
 func (a *Application) OnError(f func(*Application, error)) {
 	var newF = func(err error) {
 		f(a, err)
 	}
-	a.onErr = newF
+	a.onErr = append(a.onErr, newF)
 }
 
 // Set the base navbar.
@@ -133,6 +251,121 @@ func (a *Application) SetLoader(loader components.Loader) *Application {
 	return a
 }
 
+// Set the toast container used by Notify.
+func (a *Application) SetToasts(container *toasts.Container) *Application {
+	a.Toasts = container
+	return a
+}
+
+// Show a toast notification.
+// The toast container is created with default options if none was set.
+func (a *Application) Notify(level toasts.Level, title, body string, opts ...*toasts.Options) *toasts.Toast {
+	if a.Toasts == nil {
+		a.Toasts = toasts.NewContainer(nil)
+	}
+	return a.Toasts.Push(level, title, body, opts...)
+}
+
+// Change the locale of the application's translator.
+// The current page is rendered again once the application is running.
+func (a *Application) SetLocale(locale string) *Application {
+	a.I18n.SetLocale(locale)
+	return a
+}
+
+// Translate a message with the application's translator.
+func (a *Application) T(key string, args ...i18n.Args) string {
+	return a.I18n.T(key, args...)
+}
+
+// Register a global keyboard shortcut, such as "ctrl+k", or "g i" for a sequence.
+// Panics if the shortcut conflicts with an existing global shortcut.
+func (a *Application) Shortcut(keys, description string, handler func()) *shortcuts.Shortcut {
+	return a.ScopedShortcut(shortcuts.Global, keys, description, handler)
+}
+
+// Register a keyboard shortcut which is only active in the scope.
+// Use shortcuts.RouteScope for shortcuts which are only active on a route.
+// Panics if the shortcut conflicts with an existing shortcut in the same scope.
+func (a *Application) ScopedShortcut(scope, keys, description string, handler func()) *shortcuts.Shortcut {
+	return a.Shortcuts.MustRegister(&shortcuts.Shortcut{
+		Keys:        keys,
+		Description: description,
+		Scope:       scope,
+		Handler:     handler,
+	})
+}
+
+// Options for offline support.
+type OfflineOptions struct {
+	// Url of the service worker script to register, such as "/sw.js".
+	// The script can be generated with offline.ServiceWorkerScript.
+	// No service worker is registered if empty.
+	ServiceWorker string
+	// Options for the queue of mutating requests made while offline.
+	Queue *offline.QueueOptions
+	// Token which authorizes replayed requests, defaults to tokens.Current.
+	// Not used when the queue options set Authorize.
+	Token func() *tokens.Token
+}
+
+// Enable offline support.
+// Mutating requests made with the application's HTTP client while offline are queued,
+// and replayed when the connection returns.
+// The queue is added to the end of the interceptor chain, replays go through interceptors added after it.
+// Credentials are removed from queued requests, replays are authorized with the token of the options.
+func (a *Application) EnableOffline(opts *OfflineOptions) *offline.Queue {
+	if opts == nil {
+		opts = &OfflineOptions{}
+	}
+	var qOpts offline.QueueOptions
+	if opts.Queue != nil {
+		qOpts = *opts.Queue
+	}
+	qOpts.Status = a.Network
+	if qOpts.Authorize == nil {
+		var token = opts.Token
+		if token == nil {
+			token = tokens.Current
+		}
+		qOpts.Authorize = tokens.Authorize(token)
+	}
+	var q, err = offline.NewQueue(&qOpts)
+	if err != nil {
+		panic("could not load offline queue: " + err.Error())
+	}
+	a.Queue = q
+	a.HTTP.Use(q.Interceptor())
+	if opts.ServiceWorker != "" {
+		go func() {
+			if err := offline.RegisterServiceWorker(opts.ServiceWorker); err != nil {
+				console.Error(err.Error())
+			}
+		}()
+	}
+	return q
+}
+
+// Check if the network is online.
+func (a *Application) Online() bool {
+	return a.Network.Online()
+}
+
+// Function to be ran when the network goes online or offline.
+func (a *Application) OnNetworkChange(f func(a *Application, online bool)) *Application {
+	a.Network.OnChange(func(online bool) {
+		f(a, online)
+	})
+	return a
+}
+
+// Apply a theme to the framework components.
+// If no theme is set, the application follows the light, dark and high contrast settings of the browser.
+func (a *Application) SetTheme(t *theme.Theme) *Application {
+	theme.Apply(t)
+	return a
+}
+
 // Set the base style.
 func (a *Application) SetStyle(style string) *Application {
 	a.Base.SetAttribute("style", style)
@@ -157,14 +390,16 @@ func (a *Application) Run() int {
 }
 
 // Function to be while the application is loading.
+// Multiple functions can be added, they are called in the order they were added.
 func (a *Application) OnLoad(f func()) *Application {
-	a.onLoad = f
+	a.onLoad = append(a.onLoad, f)
 	return a
 }
 
 // Function to be ran before the application is loaded.
+// Multiple functions can be added, they are called in the order they were added.
 func (a *Application) BeforeLoad(f func()) *Application {
-	a.beforeLoad = f
+	a.beforeLoad = append(a.beforeLoad, f)
 	return a
 }
 
@@ -175,39 +410,105 @@ func (a *Application) OnRouterLoad(f func()) *Application {
 }
 
 // Function to be ran before the page is rendered.
+// Multiple functions can be added, they are called in the order they were added.
 func (a *Application) OnPageChange(f func(*Application, vars.Vars, *url.URL)) *Application {
 	var newF = func(v vars.Vars, u *url.URL) {
 		f(a, v, u)
 	}
-	a.Router.OnPageChange(newF)
+	a.onPageChange = append(a.onPageChange, newF)
 	return a
 }
 
 // Function to be ran after the page is rendered.
+// Multiple functions can be added, they are called in the order they were added.
 func (a *Application) AfterPageChange(f func(*Application, vars.Vars, *url.URL)) *Application {
 	var newF = func(v vars.Vars, u *url.URL) {
 		f(a, v, u)
 	}
-	a.Router.AfterPageChange(newF)
+	a.afterPageChange = append(a.afterPageChange, newF)
+	return a
+}
+
+// Add middleware to the application's router.
+func (a *Application) Middleware(m ...func(vars.Vars, *url.URL, *routes.Route, rterr.ErrorThrower) bool) *Application {
+	for _, mw := range m {
+		a.Router.Use(mw)
+	}
 	return a
 }
 
 // Setup application to be ran.
 // Return 0 on exit.
 func (a *Application) run() int {
-	if a.onErr == nil {
-		a.onErr = func(err error) {
+	a.installDevtools()
+	a.installTelemetry()
+	if !theme.Applied() {
+		theme.Auto(theme.Light, theme.Dark, theme.HighContrast)
+	}
+	for _, f := range a.beforeLoad {
+		f()
+	}
+	a.Router.OnError(func(err error) {
+		for _, f := range a.errObservers {
+			f(err)
+		}
+		if len(a.onErr) == 0 {
 			router.DefaultRouterErrorDisplay(err)
 			a.renderBases()
+			return
+		}
+		for _, f := range a.onErr {
+			f(err)
 		}
+	})
+	if len(a.onPageChange) > 0 {
+		a.Router.OnPageChange(func(v vars.Vars, u *url.URL) {
+			for _, f := range a.onPageChange {
+				f(v, u)
+			}
+		})
 	}
-	if a.beforeLoad != nil {
-		a.beforeLoad()
+	if len(a.afterPageChange) > 0 {
+		a.Router.AfterPageChange(func(v vars.Vars, u *url.URL) {
+			for _, f := range a.afterPageChange {
+				f(v, u)
+			}
+		})
+	}
+	// Show messages sent through the jsext message events as toasts.
+	messages.Listen(func(typ string, message string) {
+		a.Notify(toasts.Level(typ), "", message)
+	})
+	// Show the shortcut help overlay with "?", unless the key is already in use.
+	a.Shortcuts.Register(&shortcuts.Shortcut{
+		Keys:        "?",
+		Description: i18n.T("shortcuts.help"),
+		Handler:     a.Shortcuts.ToggleHelp,
+	})
+	a.Shortcuts.Listen()
+	// Suspend the other shortcuts while a modal is open.
+	misc.Shortcuts = a.Shortcuts
+	offline.WatchBrowser(a.Network)
+	// Simulate offline mode from the javascript console with jsext.App.SimulateOffline(true).
+	AppExport.SetFuncWithArgs("SimulateOffline", func(this jsext.Value, args jsext.Args) interface{} {
+		a.Network.Simulate(len(args) == 0 || args[0].Bool())
+		return nil
+	})
+	a.loadFlags()
+	if Prerendering() {
+		a.prerender()
+		return 0
 	}
-	a.Router.OnError(a.onErr)
 	a.Router.Run()
-	if a.onLoad != nil {
-		a.onLoad()
+	// Render the current page again with the new locale.
+	a.I18n.OnChange(func(locale string) {
+		jsext.Document.Get("documentElement").Set("lang", locale)
+		if r, ok := a.Router.(interface{ Reload() }); ok {
+			r.Reload()
+		}
+	})
+	for _, f := range a.onLoad {
+		f()
 	}
 	// Get the preloader, remove it if it exists
 	if preloader, err := jsext.QuerySelector("#" + JSEXT_PRELOADER_ID); err == nil {
@@ -258,9 +559,18 @@ func (a *Application) WrapURL(f func(a *Application, v vars.Vars, u *url.URL)) f
 //   - components.Component
 //   - js.Value
 //   - string
+//
+// Content which was pre-rendered for the current page is replaced: it is kept until the new content was added,
+// and then removed. The pre-rendered nodes are not reused.
 func (a *Application) Render(e ...any) {
-	a.Base.InnerHTML("")
+	var prerendered = a.prerendered()
+	if prerendered == nil {
+		a.Base.InnerHTML("")
+	}
 	a.appendAny(e...)
+	for _, node := range prerendered {
+		node.Call("remove")
+	}
 	a.renderBases()
 }
 
@@ -283,8 +593,7 @@ func (a *Application) appendAny(e ...any) {
 		case js.Value:
 			a.Base.AppendChild(jsext.Element(el))
 		case string:
-			var oldHTML = a.Base.Get("innerHTML")
-			a.Base.Set("innerHTML", oldHTML.String()+el)
+			js.Value(a.Base).Call("insertAdjacentHTML", "beforeend", el)
 		}
 	}
 }
@@ -348,7 +657,11 @@ func (a *Application) AppendChild(e ...any) *Application {
 }
 
 // Render application header and footer if defined.
+// They are not rendered when the application is pre-rendered.
 func (a *Application) renderBases() {
+	if Prerendering() {
+		return
+	}
 	if a.Navbar != nil {
 		a.Base.Prepend(a.Navbar.Render())
 	}
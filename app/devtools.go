@@ -0,0 +1,122 @@
+//go:build js && wasm && devtools
+// +build js,wasm,devtools
+
+package app
+
+import (
+	"encoding/json"
+	"net/url"
+	"time"
+
+	"github.com/Nigel2392/jsext"
+	"github.com/Nigel2392/jsext-framework/app/shortcuts"
+	"github.com/Nigel2392/jsext-framework/devtools"
+	"github.com/Nigel2392/jsext-framework/router"
+	"github.com/Nigel2392/jsext-framework/router/routes"
+	"github.com/Nigel2392/jsext-framework/router/vars"
+)
+
+// Install the developer tools.
+// Only included in builds with the devtools tag:
+//
+//	go build -tags devtools
+func (a *Application) installDevtools() {
+	var recorder = devtools.NewRecorder(100)
+	var rt, _ = a.Router.(*router.Router)
+
+	a.HTTP.Use(recorder.Interceptor())
+	a.errObservers = append(a.errObservers, recorder.Error)
+
+	// Record navigations, finished when the route handler returns or a middleware stops it.
+	var done = func() {}
+	a.onPageChange = append(a.onPageChange, func(v vars.Vars, u *url.URL) {
+		var name string
+		if rt != nil {
+			var route, _, _ = rt.Match(u.Path)
+			name = route.Name
+		}
+		done = recorder.Navigate(u.String(), name, v)
+	})
+	a.afterPageChange = append(a.afterPageChange, func(v vars.Vars, u *url.URL) {
+		done()
+	})
+	var routeTree = func() []*devtools.Route { return nil }
+	if rt != nil {
+		rt.TraceMiddleware(func(index int, route *routes.Route, took time.Duration, next bool) {
+			recorder.Middleware(route.Name, index, took, next)
+			if !next {
+				done()
+			}
+		})
+		routeTree = func() []*devtools.Route { return devtools.RouteTree(rt.Routes()) }
+	}
+
+	var panel = devtools.NewPanel(devtools.Source{
+		Recorder: recorder,
+		Routes:   routeTree,
+		Data:     func() map[string]any { return a.Data },
+		SetData:  a.SetData,
+	})
+	a.Shortcuts.Register(&shortcuts.Shortcut{
+		Keys:        "ctrl+shift+d",
+		Description: "Toggle developer tools",
+		Handler:     panel.Toggle,
+	})
+
+	// Inspect the application from the javascript console, for example jsext.App.Requests().
+	AppExport.SetFuncWithArgs("Devtools", func(this jsext.Value, args jsext.Args) interface{} {
+		if len(args) > 0 {
+			panel.Open(args[0].String())
+		} else {
+			panel.Toggle()
+		}
+		return nil
+	})
+	exportJSON("Routes", routeTree)
+	exportJSON("Vars", func() any {
+		var navigations = recorder.Navigations()
+		if len(navigations) == 0 {
+			return map[string]string{}
+		}
+		return navigations[len(navigations)-1].Vars
+	})
+	exportJSON("Navigations", recorder.Navigations)
+	exportJSON("Middlewares", recorder.Middlewares)
+	exportJSON("Requests", recorder.Requests)
+	exportJSON("InFlight", recorder.InFlight)
+	exportJSON("Errors", recorder.Errors)
+	exportJSON("Data", func() any {
+		var data = make(map[string]json.RawMessage, len(a.Data))
+		for k, v := range a.Data {
+			if b, err := json.Marshal(v); err == nil {
+				data[k] = b
+			}
+		}
+		return data
+	})
+	// Set a data key from a JSON string, jsext.App.SetData("key", '{"a": 1}').
+	AppExport.SetFuncWithArgs("SetData", func(this jsext.Value, args jsext.Args) interface{} {
+		if len(args) < 2 {
+			return "SetData requires a key and a JSON value"
+		}
+		var v any
+		if err := json.Unmarshal([]byte(args[1].String()), &v); err != nil {
+			return err.Error()
+		}
+		if err := a.SetData(args[0].String(), v); err != nil {
+			return err.Error()
+		}
+		return nil
+	})
+}
+
+// Export a function returning the value as a javascript object.
+func exportJSON[T any](name string, f func() T) {
+	AppExport.SetFuncWithArgs(name, func(this jsext.Value, args jsext.Args) interface{} {
+		var b, err = json.Marshal(f())
+		if err != nil {
+			return err.Error()
+		}
+		return jsext.Global.Get("JSON").Call("parse", string(b))
+	})
+}
@@ -0,0 +1,7 @@
+//go:build js && wasm && !devtools
+// +build js,wasm,!devtools
+
+package app
+
+// Developer tools are only included in builds with the devtools tag.
+func (a *Application) installDevtools() {}
@@ -0,0 +1,56 @@
+//go:build js && wasm
+// +build js,wasm
+
+package app
+
+import (
+	"context"
+	"time"
+
+	"github.com/Nigel2392/jsext-framework/flags"
+	"github.com/Nigel2392/jsext/console"
+)
+
+// Time to wait for the flags to load before the first page is rendered.
+var FLAGS_TIMEOUT = 3 * time.Second
+
+// Enable feature flags loaded from the providers.
+// The flags are loaded before the first page is rendered, gated routes respond with a 404 while their flag is disabled,
+// and gated links of url maps are hidden.
+//
+//	a.EnableFlags(&flags.Options{UserID: flags.TokenUserID(token, "id")},
+//		flags.Static{"search": {Enabled: true}},
+//		flags.HTTP(a.HTTP, "/api/flags"),
+//	).Gate("beta", "beta")
+func (a *Application) EnableFlags(opts *flags.Options, providers ...flags.Provider) *flags.Flags {
+	var o flags.Options
+	if opts != nil {
+		o = *opts
+	}
+	if o.OnError == nil {
+		o.OnError = func(err error) {
+			console.Error("could not load flags: " + err.Error())
+		}
+	}
+	if a.Flags != nil {
+		a.Flags.Close()
+	}
+	a.Flags = flags.New(&o, providers...)
+	a.Router.Use(a.Flags.Middleware())
+	return a.Flags
+}
+
+// Check if a feature flag is enabled, false if flags are not enabled.
+func (a *Application) Flag(name string) bool {
+	return a.Flags != nil && a.Flags.Enabled(name)
+}
+
+// Load the flags before the router runs.
+func (a *Application) loadFlags() {
+	if a.Flags == nil {
+		return
+	}
+	var ctx, cancel = context.WithTimeout(context.Background(), FLAGS_TIMEOUT)
+	defer cancel()
+	a.Flags.Load(ctx)
+}
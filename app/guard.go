@@ -0,0 +1,32 @@
+//go:build js && wasm
+// +build js,wasm
+
+package app
+
+import (
+	"github.com/Nigel2392/jsext-framework/tokens"
+	"github.com/Nigel2392/jsext-framework/tokens/guard"
+)
+
+// Protect routes with rules on the claims of the token.
+// Unauthenticated users are redirected to the login URL, users without permission get a 403.
+//
+// The token is looked up on every check, so switching sessions is picked up.
+//
+//	a.EnableGuard(tokens.Current, &guard.Options{LoginURL: "/login"})
+//	guard.Protect(a.Register("admin", "/admin", AdminView), guard.RequireRole("admin"))
+func (a *Application) EnableGuard(token func() *tokens.Token, opts *guard.Options) *guard.Guard {
+	var o guard.Options
+	if opts != nil {
+		o = *opts
+	}
+	if o.Claims == nil {
+		o.Claims = guard.TokenClaims(token)
+	}
+	if o.Redirect == nil {
+		o.Redirect = a.Redirect
+	}
+	a.Guard = guard.New(&o)
+	a.Router.Use(a.Guard.Middleware())
+	return a.Guard
+}
@@ -0,0 +1,57 @@
+//go:build js && wasm
+// +build js,wasm
+
+package app
+
+import (
+	"time"
+
+	"github.com/Nigel2392/jsext-framework/tokens"
+	"github.com/Nigel2392/jsext-framework/tokens/idle"
+)
+
+// Log out after a period of inactivity, 15 minutes by default.
+// Activity is shared between the open tabs, and only the leader tab sends the logout request.
+// Without an OnWarning function, a modal with a countdown and a button to stay signed in is shown.
+// Users who are not logged in are not warned. The token is looked up when it is used, so switching sessions is picked up.
+//
+//	a.EnableIdleTimeout(tokens.Current, &idle.Options{Timeout: 15 * time.Minute})
+func (a *Application) EnableIdleTimeout(token func() *tokens.Token, opts *idle.Options) *idle.Tracker {
+	var o idle.Options
+	if opts != nil {
+		o = *opts
+	}
+	o.Tabs = a.tabs()
+	var onWarning, onActive, onTimeout = o.OnWarning, o.OnActive, o.OnTimeout
+	var logout = idle.Logout(token, o.Tabs)
+	var hide = func() {}
+	if onWarning == nil {
+		var modal = idle.NewModal(func() { a.Idle.StaySignedIn() }, idle.Logout(token, nil))
+		onWarning, hide = modal.Show, modal.Hide
+	}
+	o.OnWarning = func(remaining time.Duration) {
+		if t := token(); t != nil && t.AccessToken != "" {
+			onWarning(remaining)
+		}
+	}
+	o.OnActive = func() {
+		hide()
+		if onActive != nil {
+			onActive()
+		}
+	}
+	o.OnTimeout = func() {
+		hide()
+		logout()
+		if onTimeout != nil {
+			onTimeout()
+		}
+	}
+	if a.Idle != nil {
+		a.Idle.Stop()
+		a.stopTracking()
+	}
+	a.Idle = idle.New(&o)
+	a.stopTracking = idle.Track(a.Idle)
+	return a.Idle
+}
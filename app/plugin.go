@@ -0,0 +1,49 @@
+//go:build js && wasm
+// +build js,wasm
+
+package app
+
+// Plugin to extend the application.
+//
+// Plugins are installed in the order they are passed to Application.Use.
+// Inside of Install, the plugin can register hooks, routes, middleware,
+// client hooks and request interceptors on the application.
+// Hooks are called in the order they were registered,
+// so hooks of earlier plugins run before hooks of later plugins.
+type Plugin interface {
+	// Name of the plugin, a plugin can only be installed once.
+	Name() string
+	// Install the plugin on the application.
+	Install(a *Application) error
+}
+
+// Install plugins on the application.
+// Panics if a plugin with the same name is already installed, or if installing fails.
+func (a *Application) Use(plugins ...Plugin) *Application {
+	for _, p := range plugins {
+		if a.Plugin(p.Name()) != nil {
+			panic("plugin already installed: " + p.Name())
+		}
+		if err := p.Install(a); err != nil {
+			panic("could not install plugin " + p.Name() + ": " + err.Error())
+		}
+		a.plugins = append(a.plugins, p)
+	}
+	return a
+}
+
+// Get an installed plugin by name.
+// Returns nil if the plugin is not installed.
+func (a *Application) Plugin(name string) Plugin {
+	for _, p := range a.plugins {
+		if p.Name() == name {
+			return p
+		}
+	}
+	return nil
+}
+
+// Get all installed plugins, in the order they were installed.
+func (a *Application) Plugins() []Plugin {
+	return a.plugins
+}
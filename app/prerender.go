@@ -0,0 +1,119 @@
+//go:build js && wasm
+// +build js,wasm
+
+package app
+
+import (
+	"net/url"
+	"os"
+	"strings"
+	"syscall/js"
+	"time"
+
+	"github.com/Nigel2392/jsext"
+	"github.com/Nigel2392/jsext-framework/prerender"
+	"github.com/Nigel2392/jsext-framework/router"
+	"github.com/Nigel2392/jsext-framework/router/routes"
+)
+
+// Metadata key of routes which should not be pre-rendered, set it to false.
+// Routes with variables in the path are never pre-rendered.
+const PrerenderMeta = "prerender"
+
+// Time to wait for a page to be rendered, before it is skipped.
+var PRERENDER_TIMEOUT = 10 * time.Second
+
+// Time the content of a page must be unchanged, without requests in flight, before it is written.
+var PRERENDER_SETTLE = 50 * time.Millisecond
+
+// Reports if the application is being pre-rendered by the prerender command.
+func Prerendering() bool {
+	return jsext.Global.Get(prerender.Global).Truthy()
+}
+
+// Render the static routes of the router, and write the pages for the prerender command.
+// The navbar and footer are not rendered, place static headers in the template instead.
+func (a *Application) prerender() {
+	var r, ok = a.Router.(*router.Router)
+	if !ok {
+		os.Stderr.WriteString("prerender: only routers from the router package can be pre-rendered\n")
+		return
+	}
+	var failed bool
+	r.OnError(func(err error) {
+		failed = true
+		os.Stderr.WriteString("prerender: " + err.Error() + "\n")
+	})
+	var history = jsext.Window.Get("history")
+	for _, rt := range static(r.Routes()) {
+		var u, err = url.Parse(rt.Path)
+		if err != nil {
+			continue
+		}
+		failed = false
+		a.Base.InnerHTML("")
+		jsext.Document.Set("title", "")
+		history.Call("replaceState", nil, "", rt.Path)
+		if !r.Serve(u) || !a.settle() || failed {
+			continue
+		}
+		prerender.Emit(os.Stdout, prerender.Page{
+			Name:  rt.Name,
+			Path:  rt.Path,
+			Title: jsext.Document.Get("title").String(),
+			HTML:  a.Base.Get("innerHTML").String(),
+		})
+	}
+}
+
+// Static routes, including child routes.
+func static(rts []*routes.Route) []*routes.Route {
+	var pages []*routes.Route
+	for _, rt := range rts {
+		var v, ok = rt.GetMeta(PrerenderMeta)
+		if rt.Callable != nil && !strings.Contains(rt.Path, routes.RT_PATH_VAR_PREFIX) && (!ok || v != false) {
+			pages = append(pages, rt)
+		}
+		pages = append(pages, static(rt.Children)...)
+	}
+	return pages
+}
+
+// Wait until no requests are in flight and the content of the page stopped changing.
+// Reports false if the page did not settle before the timeout.
+func (a *Application) settle() bool {
+	var deadline = time.Now().Add(PRERENDER_TIMEOUT)
+	var last = a.Base.Get("innerHTML").String()
+	for time.Now().Before(deadline) {
+		time.Sleep(PRERENDER_SETTLE)
+		var html = a.Base.Get("innerHTML").String()
+		if html == last && a.HTTP.InFlight() == 0 {
+			return true
+		}
+		last = html
+	}
+	return false
+}
+
+// Nodes of the content which was pre-rendered for the current page, nil if there is none.
+// The marker of the content is removed, so only the first render replaces it.
+func (a *Application) prerendered() []js.Value {
+	var base = js.Value(a.Base)
+	var marker = base.Get("firstChild")
+	if marker.IsNull() || marker.Get("nodeType").Int() != 8 || !strings.HasPrefix(marker.Get("data").String(), prerender.Marker) {
+		return nil
+	}
+	marker.Call("remove")
+	// Static file servers redirect /path to /path/ for the pre-rendered path/index.html.
+	var path = strings.TrimPrefix(marker.Get("data").String(), prerender.Marker)
+	var current = jsext.Window.Get("location").Get("pathname").String()
+	if strings.TrimSuffix(path, "/") != strings.TrimSuffix(current, "/") {
+		return nil
+	}
+	var children = base.Get("childNodes")
+	var nodes = make([]js.Value, children.Length())
+	for i := range nodes {
+		nodes[i] = children.Index(i)
+	}
+	return nodes
+}
@@ -0,0 +1,430 @@
+package shortcuts
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Scope of shortcuts which are always active, unless an exclusive scope is entered.
+const Global = ""
+
+// Scope for shortcuts which are only active on the route with the given name.
+func RouteScope(name string) string {
+	return "route:" + name
+}
+
+// Default time to wait for the next key of a sequence.
+const DefaultSequenceTimeout = time.Second
+
+// A single key press, with modifiers.
+type Key struct {
+	Key   string
+	Ctrl  bool
+	Alt   bool
+	Shift bool
+	Meta  bool
+}
+
+// Aliases for key names, mapped to the names used by KeyboardEvent.key, lowercased.
+var keyAliases = map[string]string{
+	"esc":    "escape",
+	"space":  " ",
+	"up":     "arrowup",
+	"down":   "arrowdown",
+	"left":   "arrowleft",
+	"right":  "arrowright",
+	"del":    "delete",
+	"ins":    "insert",
+	"return": "enter",
+	"plus":   "+",
+}
+
+// Parse a single key, such as "ctrl+k", "shift+a" or "esc".
+// Shift is ignored for printable characters other than letters, use "?" instead of "shift+/".
+func ParseKey(s string) (Key, error) {
+	var k Key
+	var parts = strings.Split(strings.ToLower(strings.TrimSpace(s)), "+")
+	// Support "ctrl++" for the plus key.
+	if len(parts) > 1 && parts[len(parts)-1] == "" {
+		parts = append(parts[:len(parts)-2], "+")
+	}
+	for i, part := range parts {
+		if i == len(parts)-1 {
+			if part == "" {
+				return Key{}, errors.New("missing key in shortcut: " + s)
+			}
+			k.Key = part
+			break
+		}
+		switch part {
+		case "ctrl", "control":
+			k.Ctrl = true
+		case "alt", "option":
+			k.Alt = true
+		case "shift":
+			k.Shift = true
+		case "meta", "cmd", "command", "super":
+			k.Meta = true
+		default:
+			return Key{}, errors.New("unknown modifier in shortcut: " + part)
+		}
+	}
+	return k.normalize(), nil
+}
+
+// Parse a sequence of keys separated by spaces, such as "g i" or "ctrl+k ctrl+s".
+func Parse(s string) ([]Key, error) {
+	var fields = strings.Fields(s)
+	if len(fields) == 0 {
+		return nil, errors.New("empty shortcut")
+	}
+	var keys = make([]Key, len(fields))
+	for i, field := range fields {
+		var k, err = ParseKey(field)
+		if err != nil {
+			return nil, err
+		}
+		keys[i] = k
+	}
+	return keys, nil
+}
+
+// Normalize the key, so that keys from parsed shortcuts and events can be compared.
+func (k Key) normalize() Key {
+	k.Key = strings.ToLower(k.Key)
+	if alias, ok := keyAliases[k.Key]; ok {
+		k.Key = alias
+	}
+	// Shift is already applied to printable characters, "shift+/" is reported as "?".
+	if len([]rune(k.Key)) == 1 && !isLetter(k.Key) {
+		k.Shift = false
+	}
+	return k
+}
+
+// String representation of the key.
+//
+//	Key{Key: "k", Ctrl: true} -> "ctrl+k"
+func (k Key) String() string {
+	var sb strings.Builder
+	if k.Ctrl {
+		sb.WriteString("ctrl+")
+	}
+	if k.Alt {
+		sb.WriteString("alt+")
+	}
+	if k.Shift {
+		sb.WriteString("shift+")
+	}
+	if k.Meta {
+		sb.WriteString("meta+")
+	}
+	switch k.Key {
+	case " ":
+		sb.WriteString("space")
+	case "+":
+		sb.WriteString("plus")
+	default:
+		sb.WriteString(k.Key)
+	}
+	return sb.String()
+}
+
+func isLetter(s string) bool {
+	return s >= "a" && s <= "z"
+}
+
+// A keyboard shortcut.
+type Shortcut struct {
+	// Keys to press, such as "ctrl+k", or "g i" for a sequence.
+	Keys string
+	// Description to display in the help overlay.
+	Description string
+	// Scope in which the shortcut is active.
+	// Defaults to the global scope.
+	Scope string
+	// Function to call when the shortcut is pressed.
+	Handler func()
+	// Also trigger the shortcut when an input, textarea or select is focussed.
+	AllowInInput bool
+	// Hide the shortcut from the help overlay.
+	Hidden bool
+
+	keys []Key
+}
+
+// Parsed keys of the shortcut.
+func (s *Shortcut) Sequence() []Key {
+	return s.keys
+}
+
+// Error returned when a shortcut conflicts with an already registered shortcut in the same scope.
+type ConflictError struct {
+	Existing *Shortcut
+	New      *Shortcut
+}
+
+func (e *ConflictError) Error() string {
+	var scope = e.New.Scope
+	if scope == Global {
+		scope = "global"
+	}
+	return "shortcut " + formatSequence(e.New.keys) + " conflicts with " + formatSequence(e.Existing.keys) + " in scope " + scope
+}
+
+func formatSequence(keys []Key) string {
+	var parts = make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = k.String()
+	}
+	return strings.Join(parts, " ")
+}
+
+// An entered scope.
+type activeScope struct {
+	id        int
+	name      string
+	exclusive bool
+}
+
+// Manager holds the registered shortcuts and the active scopes,
+// and matches key presses against the active shortcuts.
+type Manager struct {
+	// Time to wait for the next key of a sequence.
+	SequenceTimeout time.Duration
+
+	shortcuts []*Shortcut
+	scopes    []activeScope
+	route     string
+	nextID    int
+	pending   []Key
+	lastPress time.Time
+	now       func() time.Time
+	stop      func()
+	closeHelp func()
+	mu        *sync.Mutex
+}
+
+// Initialize a new shortcut manager.
+func New() *Manager {
+	return &Manager{
+		SequenceTimeout: DefaultSequenceTimeout,
+		shortcuts:       make([]*Shortcut, 0),
+		scopes:          make([]activeScope, 0),
+		now:             time.Now,
+		mu:              &sync.Mutex{},
+	}
+}
+
+// Register a shortcut.
+// Returns a *ConflictError if a shortcut in the same scope has the same keys,
+// or if one of the sequences starts with the other.
+func (m *Manager) Register(s *Shortcut) error {
+	var keys, err = Parse(s.Keys)
+	if err != nil {
+		return err
+	}
+	s.keys = keys
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, existing := range m.shortcuts {
+		if existing.Scope != s.Scope {
+			continue
+		}
+		if hasPrefix(existing.keys, keys) || hasPrefix(keys, existing.keys) {
+			return &ConflictError{Existing: existing, New: s}
+		}
+	}
+	m.shortcuts = append(m.shortcuts, s)
+	return nil
+}
+
+// Register a shortcut, panics if the shortcut is invalid or conflicts.
+func (m *Manager) MustRegister(s *Shortcut) *Shortcut {
+	if err := m.Register(s); err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// Remove a shortcut.
+func (m *Manager) Unregister(s *Shortcut) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, v := range m.shortcuts {
+		if v == s {
+			m.shortcuts = append(m.shortcuts[:i], m.shortcuts[i+1:]...)
+			return
+		}
+	}
+}
+
+// Enter a scope, shortcuts in this scope become active.
+// Shortcuts of later entered scopes take precedence.
+// An exclusive scope, such as an open modal, disables the shortcuts of all scopes entered before it.
+// The returned function leaves the scope.
+func (m *Manager) Enter(scope string, exclusive bool) (leave func()) {
+	m.mu.Lock()
+	var id = m.nextID
+	m.nextID++
+	m.scopes = append(m.scopes, activeScope{id: id, name: scope, exclusive: exclusive})
+	m.pending = nil
+	m.mu.Unlock()
+	return func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		for i, s := range m.scopes {
+			if s.id == id {
+				m.scopes = append(m.scopes[:i], m.scopes[i+1:]...)
+				m.pending = nil
+				return
+			}
+		}
+	}
+}
+
+// Set the name of the current route, activating its route scope.
+func (m *Manager) SetRoute(name string) {
+	m.mu.Lock()
+	if m.route != name {
+		m.route = name
+		m.pending = nil
+	}
+	m.mu.Unlock()
+}
+
+// Names of the active scopes, from highest to lowest precedence.
+func (m *Manager) Scopes() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.activeScopes()
+}
+
+func (m *Manager) activeScopes() []string {
+	var scopes = make([]string, 0, len(m.scopes)+2)
+	for i := len(m.scopes) - 1; i >= 0; i-- {
+		scopes = append(scopes, m.scopes[i].name)
+		if m.scopes[i].exclusive {
+			return scopes
+		}
+	}
+	if m.route != "" {
+		scopes = append(scopes, RouteScope(m.route))
+	}
+	return append(scopes, Global)
+}
+
+// Active shortcuts, from highest to lowest precedence.
+// Shortcuts which are shadowed by a shortcut in a scope with a higher precedence are left out.
+func (m *Manager) Active() []*Shortcut {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.active()
+}
+
+func (m *Manager) active() []*Shortcut {
+	var active = make([]*Shortcut, 0, len(m.shortcuts))
+	for _, scope := range m.activeScopes() {
+	shortcuts:
+		for _, s := range m.shortcuts {
+			if s.Scope != scope {
+				continue
+			}
+			for _, a := range active {
+				if hasPrefix(a.keys, s.keys) || hasPrefix(s.keys, a.keys) {
+					continue shortcuts
+				}
+			}
+			active = append(active, s)
+		}
+	}
+	return active
+}
+
+// Handle a key press.
+// Returns true if the key was used by a shortcut,
+// either because a shortcut was triggered or because a sequence was started.
+// If inInput is true, only shortcuts with AllowInInput are considered.
+// The handler of a triggered shortcut is called in a new goroutine.
+func (m *Manager) Press(k Key, inInput bool) bool {
+	k = k.normalize()
+	if isModifier(k.Key) {
+		return false
+	}
+	m.mu.Lock()
+	var now = m.now()
+	if len(m.pending) > 0 && now.Sub(m.lastPress) > m.SequenceTimeout {
+		m.pending = nil
+	}
+	m.lastPress = now
+
+	var active = m.active()
+	if inInput {
+		var filtered = active[:0:0]
+		for _, s := range active {
+			if s.AllowInInput {
+				filtered = append(filtered, s)
+			}
+		}
+		active = filtered
+	}
+
+	var s, partial = match(active, append(m.pending, k))
+	if s == nil && !partial && len(m.pending) > 0 {
+		// The sequence was broken, try to start a new one with this key.
+		s, partial = match(active, []Key{k})
+		m.pending = nil
+	}
+	switch {
+	case s != nil:
+		m.pending = nil
+	case partial:
+		m.pending = append(m.pending, k)
+	default:
+		m.pending = nil
+	}
+	m.mu.Unlock()
+
+	if s != nil && s.Handler != nil {
+		go s.Handler()
+	}
+	return s != nil || partial
+}
+
+// Find the shortcut matching the keys,
+// or report if the keys are the start of a sequence.
+func match(shortcuts []*Shortcut, keys []Key) (s *Shortcut, partial bool) {
+	for _, s := range shortcuts {
+		if !hasPrefix(s.keys, keys) {
+			continue
+		}
+		if len(s.keys) == len(keys) {
+			return s, false
+		}
+		partial = true
+	}
+	return nil, partial
+}
+
+// Check if the sequence starts with the prefix.
+func hasPrefix(sequence, prefix []Key) bool {
+	if len(prefix) > len(sequence) {
+		return false
+	}
+	for i := range prefix {
+		if sequence[i] != prefix[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func isModifier(key string) bool {
+	switch key {
+	case "control", "shift", "alt", "meta", "altgraph", "capslock", "os":
+		return true
+	}
+	return false
+}
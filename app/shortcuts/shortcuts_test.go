@@ -0,0 +1,150 @@
+package shortcuts_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Nigel2392/jsext-framework/app/shortcuts"
+)
+
+func press(m *shortcuts.Manager, keys ...string) bool {
+	var handled bool
+	for _, k := range keys {
+		var key, err = shortcuts.ParseKey(k)
+		if err != nil {
+			panic(err)
+		}
+		handled = m.Press(key, false)
+	}
+	return handled
+}
+
+func register(t *testing.T, m *shortcuts.Manager, scope, keys string) chan string {
+	var called = make(chan string, 1)
+	var err = m.Register(&shortcuts.Shortcut{Keys: keys, Scope: scope, Handler: func() { called <- scope + ":" + keys }})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return called
+}
+
+func expectCall(t *testing.T, called chan string, want string) {
+	t.Helper()
+	select {
+	case got := <-called:
+		if got != want {
+			t.Errorf("expected %q to be called, got %q", want, got)
+		}
+	case <-time.After(time.Second):
+		t.Errorf("expected %q to be called", want)
+	}
+}
+
+func TestParseKey(t *testing.T) {
+	var tests = map[string]string{
+		"ctrl+k":       "ctrl+k",
+		"Ctrl+Shift+K": "ctrl+shift+k",
+		"cmd+alt+s":    "alt+meta+s",
+		"esc":          "escape",
+		"shift+?":      "?",
+		"ctrl++":       "ctrl+plus",
+		"space":        "space",
+	}
+	for in, want := range tests {
+		var k, err = shortcuts.ParseKey(in)
+		if err != nil {
+			t.Errorf("%q: %s", in, err)
+			continue
+		}
+		if k.String() != want {
+			t.Errorf("%q: expected %q, got %q", in, want, k.String())
+		}
+	}
+	if _, err := shortcuts.ParseKey("hyper+k"); err == nil {
+		t.Error("expected an error for an unknown modifier")
+	}
+}
+
+func TestSequence(t *testing.T) {
+	var m = shortcuts.New()
+	var inbox = register(t, m, shortcuts.Global, "g i")
+	var chord = register(t, m, shortcuts.Global, "ctrl+k")
+
+	if !press(m, "g") {
+		t.Error("expected the first key of a sequence to be handled")
+	}
+	press(m, "i")
+	expectCall(t, inbox, ":g i")
+
+	press(m, "ctrl+k")
+	expectCall(t, chord, ":ctrl+k")
+
+	// A broken sequence starts over with the last key.
+	press(m, "g", "x", "g", "i")
+	expectCall(t, inbox, ":g i")
+
+	if press(m, "x") {
+		t.Error("expected an unbound key not to be handled")
+	}
+}
+
+func TestSequenceTimeout(t *testing.T) {
+	var m = shortcuts.New()
+	m.SequenceTimeout = 10 * time.Millisecond
+	var inbox = register(t, m, shortcuts.Global, "g i")
+	press(m, "g")
+	time.Sleep(20 * time.Millisecond)
+	press(m, "i")
+	select {
+	case <-inbox:
+		t.Error("expected the sequence to time out")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestConflicts(t *testing.T) {
+	var m = shortcuts.New()
+	register(t, m, shortcuts.Global, "g i")
+	var conflict *shortcuts.ConflictError
+	for _, keys := range []string{"g i", "g", "g i x", "G I"} {
+		var err = m.Register(&shortcuts.Shortcut{Keys: keys})
+		if !errors.As(err, &conflict) {
+			t.Errorf("%q: expected a conflict, got %v", keys, err)
+		}
+	}
+	if err := m.Register(&shortcuts.Shortcut{Keys: "g", Scope: shortcuts.RouteScope("home")}); err != nil {
+		t.Errorf("expected no conflict in another scope, got %s", err)
+	}
+}
+
+func TestScopes(t *testing.T) {
+	var m = shortcuts.New()
+	var global = register(t, m, shortcuts.Global, "n")
+	var route = register(t, m, shortcuts.RouteScope("inbox"), "n")
+	var modal = register(t, m, "modal", "escape")
+
+	press(m, "n")
+	expectCall(t, global, ":n")
+
+	m.SetRoute("inbox")
+	press(m, "n")
+	expectCall(t, route, "route:inbox:n")
+	if len(m.Active()) != 1 {
+		t.Errorf("expected the global shortcut to be shadowed, got %d active shortcuts", len(m.Active()))
+	}
+
+	if press(m, "escape") {
+		t.Error("expected the modal shortcut to be inactive")
+	}
+	var leave = m.Enter("modal", true)
+	if press(m, "n") {
+		t.Error("expected shortcuts below an exclusive scope to be inactive")
+	}
+	press(m, "escape")
+	expectCall(t, modal, "modal:escape")
+	leave()
+
+	press(m, "n")
+	expectCall(t, route, "route:inbox:n")
+}
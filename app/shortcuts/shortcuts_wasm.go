@@ -0,0 +1,237 @@
+//go:build js && wasm
+// +build js,wasm
+
+package shortcuts
+
+import (
+	"strings"
+	"syscall/js"
+
+	"github.com/Nigel2392/jsext"
+	"github.com/Nigel2392/jsext-framework/i18n"
+	"github.com/Nigel2392/jsext-framework/theme"
+	"github.com/Nigel2392/jsext/elements"
+)
+
+// Scope entered while the help overlay is open.
+const HelpScope = "shortcuts:help"
+
+// Prefix for the classes of the help overlay.
+const HelpPrefix = "jsext-shortcuts-"
+
+// Get the key from a keyboard event.
+func KeyFromEvent(event jsext.Event) Key {
+	return Key{
+		Key:   event.Get("key").String(),
+		Ctrl:  event.Get("ctrlKey").Bool(),
+		Alt:   event.Get("altKey").Bool(),
+		Shift: event.Get("shiftKey").Bool(),
+		Meta:  event.Get("metaKey").Bool(),
+	}.normalize()
+}
+
+// Check if the event target is an element which accepts text input.
+func isInput(event jsext.Event) bool {
+	var target = event.Target()
+	if target.IsUndefined() || target.IsNull() {
+		return false
+	}
+	switch strings.ToLower(target.Get("tagName").String()) {
+	case "input", "textarea", "select":
+		return true
+	}
+	var editable = target.Get("isContentEditable")
+	return !editable.IsUndefined() && editable.Bool()
+}
+
+// Listen for keydown events on the document.
+// Calling Listen again replaces the previous listener.
+// The returned function stops listening.
+func (m *Manager) Listen() (stop func()) {
+	m.Stop()
+	var fn = js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		var event = jsext.Event(args[0])
+		if event.Get("repeat").Bool() {
+			return nil
+		}
+		if m.Press(KeyFromEvent(event), isInput(event)) {
+			event.PreventDefault()
+		}
+		return nil
+	})
+	var document = js.Value(jsext.Document)
+	document.Call("addEventListener", "keydown", fn)
+	stop = func() {
+		document.Call("removeEventListener", "keydown", fn)
+		fn.Release()
+	}
+	m.mu.Lock()
+	m.stop = stop
+	m.mu.Unlock()
+	return stop
+}
+
+// Stop listening for keydown events.
+func (m *Manager) Stop() {
+	m.mu.Lock()
+	var stop = m.stop
+	m.stop = nil
+	m.mu.Unlock()
+	if stop != nil {
+		stop()
+	}
+}
+
+// Show or hide the help overlay.
+func (m *Manager) ToggleHelp() {
+	m.mu.Lock()
+	var open = m.closeHelp != nil
+	m.mu.Unlock()
+	if open {
+		m.HideHelp()
+	} else {
+		m.ShowHelp()
+	}
+}
+
+// Hide the help overlay.
+func (m *Manager) HideHelp() {
+	m.mu.Lock()
+	var closeHelp = m.closeHelp
+	m.closeHelp = nil
+	m.mu.Unlock()
+	if closeHelp != nil {
+		closeHelp()
+	}
+}
+
+// Show an overlay listing the active shortcuts, grouped by scope.
+// The overlay is closed with escape, or by clicking outside of it.
+func (m *Manager) ShowHelp() {
+	m.HideHelp()
+	var active = m.Active()
+
+	var overlay = elements.Div().AttrClass(HelpPrefix + "overlay")
+	overlay.StyleBlock(helpCSS)
+	var modal = overlay.Div().AttrClass(HelpPrefix + "modal")
+	modal.H2(i18n.T("shortcuts.title")).AttrClass(HelpPrefix + "title")
+
+	var lastScope = "\x00"
+	var list *elements.Element
+	for _, s := range active {
+		if s.Hidden {
+			continue
+		}
+		if s.Scope != lastScope {
+			lastScope = s.Scope
+			modal.H3(scopeTitle(s.Scope)).AttrClass(HelpPrefix + "scope")
+			list = modal.Div().AttrClass(HelpPrefix + "list")
+		}
+		var row = list.Div().AttrClass(HelpPrefix + "row")
+		var keys = row.Span().AttrClass(HelpPrefix + "keys")
+		for i, k := range s.keys {
+			if i > 0 {
+				keys.Span(i18n.T("shortcuts.then")).AttrClass(HelpPrefix + "then")
+			}
+			keys.Kbd(escape(k.String()))
+		}
+		row.Span(escape(s.Description)).AttrClass(HelpPrefix + "description")
+	}
+	var button = modal.Button(i18n.T("shortcuts.close")).AttrClass(HelpPrefix + "close")
+
+	var leave = m.Enter(HelpScope, true)
+	var escShortcut = &Shortcut{Keys: "esc", Scope: HelpScope, Hidden: true, AllowInInput: true, Handler: m.HideHelp}
+	m.Register(escShortcut)
+
+	var rendered = overlay.Render()
+	jsext.Body.AppendChild(rendered)
+	var closeHelp = func() {
+		m.Unregister(escShortcut)
+		leave()
+		rendered.Remove()
+	}
+	overlay.AddEventListener("click", func(this jsext.Value, event jsext.Event) {
+		if event.Target().Equal(this.Value()) {
+			m.HideHelp()
+		}
+	})
+	button.AddEventListener("click", func(this jsext.Value, event jsext.Event) {
+		event.PreventDefault()
+		m.HideHelp()
+	})
+	m.mu.Lock()
+	m.closeHelp = closeHelp
+	m.mu.Unlock()
+}
+
+func scopeTitle(scope string) string {
+	switch {
+	case scope == Global:
+		return i18n.T("shortcuts.scope.global")
+	case strings.HasPrefix(scope, "route:"):
+		return i18n.T("shortcuts.scope.route", i18n.Args{"name": strings.TrimPrefix(scope, "route:")})
+	}
+	return scope
+}
+
+var escape = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace
+
+var helpCSS = `.` + HelpPrefix + `overlay {
+	position: fixed;
+	top: 0;
+	left: 0;
+	width: 100%;
+	height: 100%;
+	background-color: ` + theme.Overlay + `;
+	display: flex;
+	justify-content: center;
+	align-items: center;
+	z-index: 10001;
+}
+.` + HelpPrefix + `modal {
+	background-color: ` + theme.Surface + `;
+	color: ` + theme.Text + `;
+	padding: 20px;
+	border-radius: 5px;
+	box-shadow: 0 0 10px ` + theme.Shadow + `;
+	min-width: 300px;
+	max-width: 90%;
+	max-height: 80%;
+	overflow-y: auto;
+}
+.` + HelpPrefix + `title {
+	margin: 0 0 10px 0;
+	font-size: 22px;
+}
+.` + HelpPrefix + `scope {
+	margin: 15px 0 5px 0;
+	font-size: 16px;
+	opacity: 0.7;
+}
+.` + HelpPrefix + `row {
+	display: flex;
+	justify-content: space-between;
+	gap: 20px;
+	padding: 4px 0;
+}
+.` + HelpPrefix + `keys kbd {
+	display: inline-block;
+	padding: 2px 6px;
+	border: 1px solid ` + theme.Border + `;
+	border-radius: 3px;
+	font-family: monospace;
+	font-size: 13px;
+}
+.` + HelpPrefix + `then {
+	margin: 0 5px;
+	opacity: 0.7;
+}
+.` + HelpPrefix + `close {
+	margin-top: 15px;
+	padding: 8px 16px;
+	border: none;
+	border-radius: 5px;
+	background-color: ` + theme.Primary + `;
+	color: ` + theme.OnPrimary + `;
+	cursor: pointer;
+}`
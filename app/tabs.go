@@ -0,0 +1,139 @@
+//go:build js && wasm
+// +build js,wasm
+
+package app
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/Nigel2392/jsext-framework/tabs"
+	"github.com/Nigel2392/jsext-framework/tokens"
+	"github.com/Nigel2392/jsext/console"
+)
+
+// Default channel name used for synchronizing tabs.
+const TABS_CHANNEL = "jsext-tabs"
+
+// Enable synchronization between the open tabs of the application over the named channel.
+// Called automatically with TABS_CHANNEL when a sync method is used before it was enabled.
+func (a *Application) EnableTabSync(channel string, opts ...*tabs.Options) *tabs.Tabs {
+	if a.Tabs != nil {
+		return a.Tabs
+	}
+	var o *tabs.Options
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+	a.Tabs = tabs.New(tabs.NewTransport(channel), o)
+	tabs.CloseOnUnload(a.Tabs)
+	a.Tabs.OnData(func(key string, value json.RawMessage) {
+		var decode, ok = a.syncKeys[key]
+		if !ok {
+			return
+		}
+		var v, err = decode(value)
+		if err != nil {
+			console.Error("could not decode synced data " + key + ": " + err.Error())
+			return
+		}
+		a.Data.Set(key, v)
+		for _, f := range a.onSync {
+			f(a, key)
+		}
+	})
+	return a.Tabs
+}
+
+func (a *Application) tabs() *tabs.Tabs {
+	if a.Tabs == nil {
+		a.EnableTabSync(TABS_CHANNEL)
+	}
+	return a.Tabs
+}
+
+// Synchronize the data keys with the other tabs.
+// Values received from other tabs are decoded as generic JSON values,
+// use SyncType to keep the type of the value.
+func (a *Application) SyncData(keys ...string) *Application {
+	for _, key := range keys {
+		a.syncKey(key, func(value json.RawMessage) (any, error) {
+			var v any
+			var err = json.Unmarshal(value, &v)
+			return v, err
+		})
+	}
+	return a
+}
+
+// Synchronize the data key with the other tabs,
+// values received from other tabs are decoded into T.
+func SyncType[T any](a *Application, key string) *Application {
+	a.syncKey(key, func(value json.RawMessage) (any, error) {
+		var v T
+		var err = json.Unmarshal(value, &v)
+		return v, err
+	})
+	return a
+}
+
+func (a *Application) syncKey(key string, decode func(json.RawMessage) (any, error)) {
+	a.tabs()
+	if a.syncKeys == nil {
+		a.syncKeys = make(map[string]func(json.RawMessage) (any, error))
+	}
+	a.syncKeys[key] = decode
+}
+
+// Set data on the application data map.
+// If the key is synchronized, the value is sent to the other tabs.
+func (a *Application) SetData(key string, value any) error {
+	a.Data.Set(key, value)
+	if _, ok := a.syncKeys[key]; !ok {
+		return nil
+	}
+	return a.tabs().Set(key, value)
+}
+
+// Function to be ran when a synchronized data key was changed by another tab.
+func (a *Application) OnSync(f func(a *Application, key string)) *Application {
+	a.onSync = append(a.onSync, f)
+	return a
+}
+
+// Send an event to the other tabs, such as a logout.
+func (a *Application) Broadcast(event string, payload any) error {
+	return a.tabs().Emit(event, payload)
+}
+
+// Function to be ran when another tab broadcasts the event.
+func (a *Application) OnBroadcast(event string, f func(a *Application, payload json.RawMessage)) *Application {
+	a.tabs().On(event, func(payload json.RawMessage) {
+		f(a, payload)
+	})
+	return a
+}
+
+// Check if this tab is the leader among the open tabs.
+func (a *Application) IsLeader() bool {
+	return a.tabs().IsLeader()
+}
+
+// Run a background job, such as token refresh or polling, in only one of the open tabs.
+// The context is cancelled when this tab stops being the leader.
+func (a *Application) RunAsLeader(job func(ctx context.Context)) *Application {
+	a.tabs().RunAsLeader(job)
+	return a
+}
+
+// Share the token with the other open tabs, and refresh it only in the leader tab.
+// Tokens obtained or refreshed in the leader are sent to the other tabs, logging out in one tab logs out all tabs.
+// The key defaults to tokens.JSEXT_token, use a key per token when sharing several tokens.
+// The returned function stops sharing the token.
+func (a *Application) EnableTokenRefresh(t *tokens.Token, key ...string) (stop func()) {
+	var k = tokens.JSEXT_token
+	if len(key) > 0 {
+		k = key[0]
+	}
+	return t.ShareTabs(a.tabs(), k)
+}
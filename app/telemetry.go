@@ -0,0 +1,51 @@
+//go:build js && wasm
+// +build js,wasm
+
+package app
+
+import (
+	"net/url"
+	"time"
+
+	"github.com/Nigel2392/jsext-framework/router"
+	"github.com/Nigel2392/jsext-framework/router/routes"
+	"github.com/Nigel2392/jsext-framework/router/vars"
+	"github.com/Nigel2392/jsext-framework/telemetry"
+)
+
+// Record navigations, route handler durations, request latencies, errors and panics to the sink.
+// The hooks are installed when the application runs.
+//
+//	a.EnableTelemetry(telemetry.NewBeacon("/api/telemetry"), &telemetry.Options{SampleRate: telemetry.Rate(0.1)})
+func (a *Application) EnableTelemetry(sink telemetry.Sink, opts *telemetry.Options) *telemetry.Telemetry {
+	a.Telemetry = telemetry.New(sink, opts)
+	return a.Telemetry
+}
+
+// Install the telemetry hooks.
+func (a *Application) installTelemetry() {
+	var t = a.Telemetry
+	if t == nil || !t.Sampled() {
+		return
+	}
+	var rt, _ = a.Router.(*router.Router)
+	a.HTTP.Use(t.Interceptor())
+	a.errObservers = append(a.errObservers, t.Error)
+	a.onPageChange = append(a.onPageChange, func(v vars.Vars, u *url.URL) {
+		var name string
+		if rt != nil {
+			var route, _, _ = rt.Match(u.Path)
+			name = route.Name
+		}
+		t.Navigation(name, v, u)
+	})
+	if rt != nil {
+		rt.TraceHandler(func(route *routes.Route, took time.Duration) {
+			t.Handler(route.Name, took)
+		})
+		rt.OnPanic(func(route *routes.Route, recovered any, stack []byte) {
+			t.Panic("route "+route.Name, recovered, stack)
+		})
+	}
+	telemetry.FlushOnHide(t)
+}
@@ -0,0 +1,195 @@
+// Package bind contains the helpers used by struct bindings generated with the jsext command.
+//
+// Bindings convert structs to and from form data and decoded JSON maps without reflection,
+// so that forms and paginators can be used in TinyGo builds.
+//
+//	//go:generate jsext bind $GOFILE
+package bind
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Delimiter between the names of nested fields in form data, the same as the forms package.
+const Delimiter = "___"
+
+// Layout of times in form data, the format of datetime-local inputs.
+const FormTimeLayout = "2006-01-02T15:04"
+
+// Form input types.
+const (
+	TypeText     = "text"
+	TypeCheckbox = "checkbox"
+	TypeNumber   = "number"
+	TypeDateTime = "datetime-local"
+)
+
+// Error for a field which could not be converted.
+type Error struct {
+	Field string
+	Err   error
+}
+
+func (e *Error) Error() string {
+	return "bind: field " + e.Field + ": " + e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func typeError(field, want string, v any) error {
+	return &Error{Field: field, Err: fmt.Errorf("expected %s, got %T", want, v)}
+}
+
+// Decode a list of JSON objects, such as the results of a paginator.
+//
+//	paginator.New[User](token, url, func(items []any) ([]User, error) {
+//		return bind.List(items, (*User).FromMap)
+//	})
+func List[T any](items []any, decode func(*T, map[string]any) error) ([]T, error) {
+	var list = make([]T, len(items))
+	for i, item := range items {
+		var m, ok = item.(map[string]any)
+		if !ok {
+			return nil, typeError(strconv.Itoa(i), "object", item)
+		}
+		if err := decode(&list[i], m); err != nil {
+			return nil, err
+		}
+	}
+	return list, nil
+}
+
+// String from a decoded JSON value.
+func String(field string, v any) (string, error) {
+	var s, ok = v.(string)
+	if !ok {
+		return "", typeError(field, "string", v)
+	}
+	return s, nil
+}
+
+// Bool from a decoded JSON value.
+func Bool(field string, v any) (bool, error) {
+	var b, ok = v.(bool)
+	if !ok {
+		return false, typeError(field, "bool", v)
+	}
+	return b, nil
+}
+
+// Float from a decoded JSON value.
+// Decoders which produce integers or json.Number-like strings are also supported.
+func Float(field string, v any) (float64, error) {
+	switch n := v.(type) {
+	case float64:
+		return n, nil
+	case float32:
+		return float64(n), nil
+	case int:
+		return float64(n), nil
+	case int64:
+		return float64(n), nil
+	case string:
+		var f, err = strconv.ParseFloat(n, 64)
+		if err != nil {
+			return 0, &Error{Field: field, Err: err}
+		}
+		return f, nil
+	}
+	return 0, typeError(field, "number", v)
+}
+
+// Int from a decoded JSON value.
+func Int(field string, v any) (int64, error) {
+	var f, err = Float(field, v)
+	if err != nil {
+		return 0, err
+	}
+	if f != float64(int64(f)) {
+		return 0, typeError(field, "integer", v)
+	}
+	return int64(f), nil
+}
+
+// Uint from a decoded JSON value.
+func Uint(field string, v any) (uint64, error) {
+	var n, err = Int(field, v)
+	if err != nil {
+		return 0, err
+	}
+	if n < 0 {
+		return 0, typeError(field, "unsigned integer", v)
+	}
+	return uint64(n), nil
+}
+
+// Time from a decoded JSON value, formatted as RFC3339.
+func Time(field string, v any) (time.Time, error) {
+	var s, err = String(field, v)
+	if err != nil {
+		return time.Time{}, err
+	}
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return time.Time{}, &Error{Field: field, Err: err}
+	}
+	return t, nil
+}
+
+// Object from a decoded JSON value.
+func Map(field string, v any) (map[string]any, error) {
+	var m, ok = v.(map[string]any)
+	if !ok {
+		return nil, typeError(field, "object", v)
+	}
+	return m, nil
+}
+
+// Bool from form data, checked checkboxes send "on".
+func FormBool(value string) bool {
+	switch value {
+	case "on", "true", "1", "yes":
+		return true
+	}
+	return false
+}
+
+// Int from form data.
+func FormInt(field, value string, bits int) (int64, error) {
+	var n, err = strconv.ParseInt(value, 10, bits)
+	if err != nil {
+		return 0, &Error{Field: field, Err: err}
+	}
+	return n, nil
+}
+
+// Uint from form data.
+func FormUint(field, value string, bits int) (uint64, error) {
+	var n, err = strconv.ParseUint(value, 10, bits)
+	if err != nil {
+		return 0, &Error{Field: field, Err: err}
+	}
+	return n, nil
+}
+
+// Float from form data.
+func FormFloat(field, value string, bits int) (float64, error) {
+	var f, err = strconv.ParseFloat(value, bits)
+	if err != nil {
+		return 0, &Error{Field: field, Err: err}
+	}
+	return f, nil
+}
+
+// Time from form data.
+func FormTime(field, value string) (time.Time, error) {
+	var t, err = time.Parse(FormTimeLayout, value)
+	if err != nil {
+		return time.Time{}, &Error{Field: field, Err: err}
+	}
+	return t, nil
+}
@@ -0,0 +1,25 @@
+// Package bindtest contains structs with bindings generated by the jsext bind command.
+package bindtest
+
+import "time"
+
+//go:generate go run ../../cmd/jsext bind user.go
+
+type User struct {
+	Name     string    `json:"name"`
+	Email    string    `json:"email" type:"email"`
+	Age      int       `json:"age"`
+	Score    float32   `json:"score"`
+	Visits   uint16    `json:"visits"`
+	Active   bool      `json:"active"`
+	Joined   time.Time `json:"joined"`
+	Address  Address   `json:"address"`
+	Password string    `json:"-" type:"password"`
+	Tags     []string  `json:"tags"`
+	internal string
+}
+
+type Address struct {
+	Street string `json:"street"`
+	Number int64  `json:"number"`
+}
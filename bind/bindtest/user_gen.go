@@ -0,0 +1,216 @@
+// Code generated by jsext bind DO NOT EDIT.
+
+package bindtest
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/Nigel2392/jsext-framework/bind"
+)
+
+// User: skipped field Tags ([]string), the type is not supported.
+
+// FormFields returns the name, value and input type of the fields, implementing forms.Binding.
+func (z *User) FormFields() [][3]string {
+	return z.formFields("")
+}
+
+func (z *User) formFields(prefix string) [][3]string {
+	var fields = make([][3]string, 0, 9)
+	fields = append(fields, [3]string{prefix + "Name", z.Name, "text"})
+	fields = append(fields, [3]string{prefix + "Email", z.Email, "email"})
+	fields = append(fields, [3]string{prefix + "Age", strconv.FormatInt(int64(z.Age), 10), "number"})
+	fields = append(fields, [3]string{prefix + "Score", strconv.FormatFloat(float64(z.Score), 'f', -1, 32), "number"})
+	fields = append(fields, [3]string{prefix + "Visits", strconv.FormatUint(uint64(z.Visits), 10), "number"})
+	fields = append(fields, [3]string{prefix + "Active", strconv.FormatBool(z.Active), "checkbox"})
+	fields = append(fields, [3]string{prefix + "Joined", z.Joined.Format(bind.FormTimeLayout), "datetime-local"})
+	fields = append(fields, z.Address.formFields(prefix+"Address"+bind.Delimiter)...)
+	fields = append(fields, [3]string{prefix + "Password", z.Password, "password"})
+	return fields
+}
+
+// SetFormData sets the fields from submitted form data, implementing forms.Binding.
+func (z *User) SetFormData(data map[string]string) error {
+	return z.setFormData(data, "")
+}
+
+func (z *User) setFormData(data map[string]string, prefix string) error {
+	if v, ok := data[prefix+"Name"]; ok {
+		z.Name = v
+	}
+	if v, ok := data[prefix+"Email"]; ok {
+		z.Email = v
+	}
+	if v := data[prefix+"Age"]; v != "" {
+		var n, err = bind.FormInt(prefix+"Age", v, 0)
+		if err != nil {
+			return err
+		}
+		z.Age = int(n)
+	}
+	if v := data[prefix+"Score"]; v != "" {
+		var n, err = bind.FormFloat(prefix+"Score", v, 32)
+		if err != nil {
+			return err
+		}
+		z.Score = float32(n)
+	}
+	if v := data[prefix+"Visits"]; v != "" {
+		var n, err = bind.FormUint(prefix+"Visits", v, 16)
+		if err != nil {
+			return err
+		}
+		z.Visits = uint16(n)
+	}
+	z.Active = bind.FormBool(data[prefix+"Active"])
+	if v := data[prefix+"Joined"]; v != "" {
+		var t, err = bind.FormTime(prefix+"Joined", v)
+		if err != nil {
+			return err
+		}
+		z.Joined = t
+	}
+	if err := z.Address.setFormData(data, prefix+"Address"+bind.Delimiter); err != nil {
+		return err
+	}
+	if v, ok := data[prefix+"Password"]; ok {
+		z.Password = v
+	}
+	return nil
+}
+
+// FromMap sets the fields from a decoded JSON object.
+func (z *User) FromMap(m map[string]any) error {
+	if v, ok := m["name"]; ok && v != nil {
+		var x, err = bind.String("name", v)
+		if err != nil {
+			return err
+		}
+		z.Name = x
+	}
+	if v, ok := m["email"]; ok && v != nil {
+		var x, err = bind.String("email", v)
+		if err != nil {
+			return err
+		}
+		z.Email = x
+	}
+	if v, ok := m["age"]; ok && v != nil {
+		var x, err = bind.Int("age", v)
+		if err != nil {
+			return err
+		}
+		z.Age = int(x)
+	}
+	if v, ok := m["score"]; ok && v != nil {
+		var x, err = bind.Float("score", v)
+		if err != nil {
+			return err
+		}
+		z.Score = float32(x)
+	}
+	if v, ok := m["visits"]; ok && v != nil {
+		var x, err = bind.Uint("visits", v)
+		if err != nil {
+			return err
+		}
+		z.Visits = uint16(x)
+	}
+	if v, ok := m["active"]; ok && v != nil {
+		var x, err = bind.Bool("active", v)
+		if err != nil {
+			return err
+		}
+		z.Active = x
+	}
+	if v, ok := m["joined"]; ok && v != nil {
+		var x, err = bind.Time("joined", v)
+		if err != nil {
+			return err
+		}
+		z.Joined = x
+	}
+	if v, ok := m["address"]; ok && v != nil {
+		var x, err = bind.Map("address", v)
+		if err != nil {
+			return err
+		}
+		if err = z.Address.FromMap(x); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ToMap returns the fields as a JSON object.
+func (z *User) ToMap() map[string]any {
+	return map[string]any{
+		"name":    z.Name,
+		"email":   z.Email,
+		"age":     z.Age,
+		"score":   z.Score,
+		"visits":  z.Visits,
+		"active":  z.Active,
+		"joined":  z.Joined.Format(time.RFC3339Nano),
+		"address": z.Address.ToMap(),
+	}
+}
+
+// FormFields returns the name, value and input type of the fields, implementing forms.Binding.
+func (z *Address) FormFields() [][3]string {
+	return z.formFields("")
+}
+
+func (z *Address) formFields(prefix string) [][3]string {
+	var fields = make([][3]string, 0, 2)
+	fields = append(fields, [3]string{prefix + "Street", z.Street, "text"})
+	fields = append(fields, [3]string{prefix + "Number", strconv.FormatInt(int64(z.Number), 10), "number"})
+	return fields
+}
+
+// SetFormData sets the fields from submitted form data, implementing forms.Binding.
+func (z *Address) SetFormData(data map[string]string) error {
+	return z.setFormData(data, "")
+}
+
+func (z *Address) setFormData(data map[string]string, prefix string) error {
+	if v, ok := data[prefix+"Street"]; ok {
+		z.Street = v
+	}
+	if v := data[prefix+"Number"]; v != "" {
+		var n, err = bind.FormInt(prefix+"Number", v, 64)
+		if err != nil {
+			return err
+		}
+		z.Number = int64(n)
+	}
+	return nil
+}
+
+// FromMap sets the fields from a decoded JSON object.
+func (z *Address) FromMap(m map[string]any) error {
+	if v, ok := m["street"]; ok && v != nil {
+		var x, err = bind.String("street", v)
+		if err != nil {
+			return err
+		}
+		z.Street = x
+	}
+	if v, ok := m["number"]; ok && v != nil {
+		var x, err = bind.Int("number", v)
+		if err != nil {
+			return err
+		}
+		z.Number = int64(x)
+	}
+	return nil
+}
+
+// ToMap returns the fields as a JSON object.
+func (z *Address) ToMap() map[string]any {
+	return map[string]any{
+		"street": z.Street,
+		"number": z.Number,
+	}
+}
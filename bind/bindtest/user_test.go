@@ -0,0 +1,71 @@
+package bindtest_test
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Nigel2392/jsext-framework/bind"
+	"github.com/Nigel2392/jsext-framework/bind/bindtest"
+)
+
+func TestFormData(t *testing.T) {
+	var u = bindtest.User{
+		Name:    "John",
+		Age:     42,
+		Score:   1.5,
+		Active:  true,
+		Joined:  time.Date(2023, 1, 2, 3, 4, 0, 0, time.UTC),
+		Address: bindtest.Address{Street: "Main", Number: 7},
+	}
+	var data = make(map[string]string)
+	for _, field := range u.FormFields() {
+		data[field[0]] = field[1]
+	}
+	if data["Address___Number"] != "7" || data["Joined"] != "2023-01-02T03:04" {
+		t.Fatalf("unexpected form data %v", data)
+	}
+	var got bindtest.User
+	if err := got.SetFormData(data); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, u) {
+		t.Fatalf("expected %+v, got %+v", u, got)
+	}
+	var err = got.SetFormData(map[string]string{"Age": "old"})
+	var bindErr *bind.Error
+	if !errors.As(err, &bindErr) || bindErr.Field != "Age" {
+		t.Fatalf("expected an error for Age, got %v", err)
+	}
+}
+
+func TestMap(t *testing.T) {
+	var u = bindtest.User{
+		Name:     "Jane",
+		Visits:   3,
+		Joined:   time.Date(2023, 1, 2, 3, 4, 5, 6, time.UTC),
+		Address:  bindtest.Address{Street: "Side", Number: 12},
+		Password: "secret",
+	}
+	var b, err = json.Marshal(u.ToMap())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var items []any
+	if err = json.Unmarshal([]byte("["+string(b)+"]"), &items); err != nil {
+		t.Fatal(err)
+	}
+	users, err := bind.List(items, (*bindtest.User).FromMap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	u.Password = ""
+	if len(users) != 1 || !reflect.DeepEqual(users[0], u) {
+		t.Fatalf("expected %+v, got %+v", u, users)
+	}
+	if err = users[0].FromMap(map[string]any{"visits": -1.0}); err == nil {
+		t.Fatal("expected an error for a negative uint")
+	}
+}
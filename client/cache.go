@@ -0,0 +1,282 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Options for the response cache.
+type CacheOptions struct {
+	// Time a response is considered fresh.
+	TTL time.Duration
+	// Time after the TTL in which a stale response is still served,
+	// while the response is revalidated in the background.
+	StaleWhileRevalidate time.Duration
+	// Generate the cache key for a request.
+	// Defaults to the full URL of the request.
+	// Make sure to include anything which changes the response, such as the user.
+	Key func(r *http.Request) string
+	// Invalidate cached responses of which the URL has the same path prefix
+	// when a POST, PUT, PATCH or DELETE request succeeds, regardless of their key.
+	InvalidateOnMutate bool
+}
+
+func (o *CacheOptions) SetDefaults() {
+	if o.TTL == 0 {
+		o.TTL = time.Minute
+	}
+	if o.Key == nil {
+		o.Key = func(r *http.Request) string {
+			return r.URL.String()
+		}
+	}
+}
+
+// A cached response.
+type cacheEntry struct {
+	url          string
+	statusCode   int
+	status       string
+	header       http.Header
+	body         []byte
+	storedAt     time.Time
+	etag         string
+	lastModified string
+}
+
+// Create a new response from the cache entry.
+func (e *cacheEntry) response(r *http.Request) *http.Response {
+	return &http.Response{
+		Status:        e.status,
+		StatusCode:    e.statusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        e.header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(e.body)),
+		ContentLength: int64(len(e.body)),
+		Request:       r,
+	}
+}
+
+// Context with the values of the parent, which is never cancelled and has no deadline.
+// Use it for work which is shared by several callers, and should not stop when the first caller goes away.
+func Detach(parent context.Context) context.Context {
+	return detached{parent}
+}
+
+type detached struct {
+	context.Context
+}
+
+func (detached) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+func (detached) Done() <-chan struct{} {
+	return nil
+}
+
+func (detached) Err() error {
+	return nil
+}
+
+// A request in flight, shared by all callers for the same key.
+type cacheCall struct {
+	done  chan struct{}
+	entry *cacheEntry
+	err   error
+}
+
+// Cache for GET responses.
+//
+// The cache de-duplicates identical GET requests in flight,
+// serves responses from the cache while they are fresh,
+// and revalidates stale responses with conditional requests
+// when the server sent an ETag or Last-Modified header.
+type Cache struct {
+	opts     CacheOptions
+	entries  map[string]*cacheEntry
+	inFlight map[string]*cacheCall
+	mu       *sync.Mutex
+}
+
+// Initialize a new response cache.
+func NewCache(opts *CacheOptions) *Cache {
+	var c = &Cache{
+		entries:  make(map[string]*cacheEntry),
+		inFlight: make(map[string]*cacheCall),
+		mu:       &sync.Mutex{},
+	}
+	if opts != nil {
+		c.opts = *opts
+	}
+	c.opts.SetDefaults()
+	return c
+}
+
+// Remove all cached responses of which the key starts with the prefix.
+func (c *Cache) Invalidate(prefix string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key := range c.entries {
+		if strings.HasPrefix(key, prefix) {
+			delete(c.entries, key)
+		}
+	}
+}
+
+// Remove all cached responses of which the URL starts with the prefix.
+func (c *Cache) invalidateURL(prefix string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key, entry := range c.entries {
+		if strings.HasPrefix(entry.url, prefix) {
+			delete(c.entries, key)
+		}
+	}
+}
+
+// Remove all cached responses.
+func (c *Cache) Clear() {
+	c.mu.Lock()
+	c.entries = make(map[string]*cacheEntry)
+	c.mu.Unlock()
+}
+
+// Amount of cached responses.
+func (c *Cache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
+
+// Interceptor to use the cache in a client.
+func (c *Cache) Interceptor() Interceptor {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			switch r.Method {
+			case http.MethodGet:
+				return c.get(next, r)
+			case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+				var resp, err = next.RoundTrip(r)
+				if c.opts.InvalidateOnMutate && err == nil && resp.StatusCode < 400 {
+					var u = *r.URL
+					u.RawQuery = ""
+					u.Fragment = ""
+					c.invalidateURL(u.String())
+				}
+				return resp, err
+			}
+			return next.RoundTrip(r)
+		})
+	}
+}
+
+func (c *Cache) get(next http.RoundTripper, r *http.Request) (*http.Response, error) {
+	var key = c.opts.Key(r)
+	var noCache = strings.Contains(r.Header.Get("Cache-Control"), "no-cache")
+
+	c.mu.Lock()
+	var entry = c.entries[key]
+	c.mu.Unlock()
+
+	if entry != nil && !noCache {
+		var age = time.Since(entry.storedAt)
+		if age < c.opts.TTL {
+			return entry.response(r), nil
+		}
+		if age < c.opts.TTL+c.opts.StaleWhileRevalidate {
+			// Revalidate in the background, detached from the caller's context.
+			go c.fetch(next, r.Clone(Detach(r.Context())), key, entry)
+			return entry.response(r), nil
+		}
+	}
+
+	var e, err = c.fetch(next, r, key, entry)
+	if err != nil {
+		return nil, err
+	}
+	return e.response(r), nil
+}
+
+// Fetch the response, or wait for the same request in flight.
+// The request is shared by all callers, so it is made detached from the context of the first caller;
+// every caller stops waiting when its own context is done.
+func (c *Cache) fetch(next http.RoundTripper, r *http.Request, key string, stale *cacheEntry) (*cacheEntry, error) {
+	c.mu.Lock()
+	var call, ok = c.inFlight[key]
+	if !ok {
+		call = &cacheCall{done: make(chan struct{})}
+		c.inFlight[key] = call
+		go func(r *http.Request) {
+			call.entry, call.err = c.roundTrip(next, r, key, stale)
+			c.mu.Lock()
+			delete(c.inFlight, key)
+			c.mu.Unlock()
+			close(call.done)
+		}(r.Clone(Detach(r.Context())))
+	}
+	c.mu.Unlock()
+	select {
+	case <-call.done:
+		return call.entry, call.err
+	case <-r.Context().Done():
+		return nil, r.Context().Err()
+	}
+}
+
+// Make the request, using a conditional request if the stale entry allows it.
+func (c *Cache) roundTrip(next http.RoundTripper, r *http.Request, key string, stale *cacheEntry) (*cacheEntry, error) {
+	if stale != nil && (stale.etag != "" || stale.lastModified != "") {
+		r = r.Clone(r.Context())
+		if stale.etag != "" {
+			r.Header.Set("If-None-Match", stale.etag)
+		}
+		if stale.lastModified != "" {
+			r.Header.Set("If-Modified-Since", stale.lastModified)
+		}
+	}
+	var resp, err = next.RoundTrip(r)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified && stale != nil {
+		var e = *stale
+		e.storedAt = time.Now()
+		c.store(key, &e)
+		return &e, nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	var e = &cacheEntry{
+		url:          r.URL.String(),
+		statusCode:   resp.StatusCode,
+		status:       resp.Status,
+		header:       resp.Header,
+		body:         body,
+		storedAt:     time.Now(),
+		etag:         resp.Header.Get("ETag"),
+		lastModified: resp.Header.Get("Last-Modified"),
+	}
+	if resp.StatusCode == http.StatusOK && !strings.Contains(resp.Header.Get("Cache-Control"), "no-store") {
+		c.store(key, e)
+	}
+	return e, nil
+}
+
+func (c *Cache) store(key string, e *cacheEntry) {
+	c.mu.Lock()
+	c.entries[key] = e
+	c.mu.Unlock()
+}
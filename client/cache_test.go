@@ -0,0 +1,203 @@
+package client_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Nigel2392/jsext-framework/client"
+)
+
+func TestCacheDeduplicate(t *testing.T) {
+	var calls int32
+	var release = make(chan struct{})
+	var server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		w.Write([]byte(`{"name": "jsext"}`))
+	}))
+	defer server.Close()
+
+	var cache = client.NewCache(&client.CacheOptions{TTL: time.Minute})
+	var c = client.New(client.BaseURL(server.URL), cache.Interceptor())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var resp map[string]string
+			if err := c.Get(context.Background(), "/posts", &resp); err != nil {
+				t.Error(err)
+			}
+			if resp["name"] != "jsext" {
+				t.Error("Wrong response: ", resp)
+			}
+		}()
+	}
+	for c.InFlight() < 10 {
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+	wg.Wait()
+
+	if err := c.Get(context.Background(), "/posts", nil); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 1 {
+		t.Errorf("Expected 1 call, got %d", calls)
+	}
+}
+
+func TestCacheRevalidate(t *testing.T) {
+	var calls, notModified int32
+	var server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			atomic.AddInt32(&notModified, 1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte(`{"name": "jsext"}`))
+	}))
+	defer server.Close()
+
+	var cache = client.NewCache(&client.CacheOptions{TTL: 10 * time.Millisecond})
+	var c = client.New(client.BaseURL(server.URL), cache.Interceptor())
+
+	var resp map[string]string
+	if err := c.Get(context.Background(), "/posts", &resp); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(20 * time.Millisecond)
+	resp = nil
+	if err := c.Get(context.Background(), "/posts", &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp["name"] != "jsext" {
+		t.Error("Wrong response after revalidation: ", resp)
+	}
+	if calls != 2 || notModified != 1 {
+		t.Errorf("Expected 2 calls of which 1 not modified, got %d and %d", calls, notModified)
+	}
+}
+
+func TestCacheStaleWhileRevalidate(t *testing.T) {
+	var calls int32
+	var server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	var cache = client.NewCache(&client.CacheOptions{
+		TTL:                  10 * time.Millisecond,
+		StaleWhileRevalidate: time.Minute,
+	})
+	var c = client.New(client.BaseURL(server.URL), cache.Interceptor())
+	if err := c.Get(context.Background(), "/posts", nil); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(20 * time.Millisecond)
+	if err := c.Get(context.Background(), "/posts", nil); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 100 && atomic.LoadInt32(&calls) < 2; i++ {
+		time.Sleep(time.Millisecond)
+	}
+	if atomic.LoadInt32(&calls) != 2 {
+		t.Errorf("Expected a background revalidation, got %d calls", calls)
+	}
+}
+
+func TestCacheInvalidate(t *testing.T) {
+	var calls int32
+	var server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	var cache = client.NewCache(&client.CacheOptions{InvalidateOnMutate: true})
+	var c = client.New(client.BaseURL(server.URL), cache.Interceptor())
+	c.Get(context.Background(), "/posts?page=1", nil)
+	c.Get(context.Background(), "/posts?page=2", nil)
+	c.Get(context.Background(), "/users", nil)
+	if cache.Len() != 3 {
+		t.Fatalf("Expected 3 cached responses, got %d", cache.Len())
+	}
+	cache.Invalidate(server.URL + "/users")
+	if cache.Len() != 2 {
+		t.Fatalf("Expected 2 cached responses, got %d", cache.Len())
+	}
+	c.Post(context.Background(), "/posts", map[string]string{"title": "new"}, nil)
+	if cache.Len() != 0 {
+		t.Fatalf("Expected no cached responses after mutation, got %d", cache.Len())
+	}
+}
+
+func TestCacheDeduplicateCancel(t *testing.T) {
+	var release = make(chan struct{})
+	var server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		w.Write([]byte(`{"name": "jsext"}`))
+	}))
+	defer server.Close()
+
+	var cache = client.NewCache(nil)
+	var c = client.New(client.BaseURL(server.URL), cache.Interceptor())
+
+	var ctx, cancel = context.WithCancel(context.Background())
+	var first = make(chan error, 1)
+	go func() {
+		first <- c.Get(ctx, "/posts", nil)
+	}()
+	for c.InFlight() < 1 {
+		time.Sleep(time.Millisecond)
+	}
+	var second = make(chan error, 1)
+	var resp map[string]string
+	go func() {
+		second <- c.Get(context.Background(), "/posts", &resp)
+	}()
+	for c.InFlight() < 2 {
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	if err := <-first; err == nil {
+		t.Error("Expected the cancelled request to fail")
+	}
+	close(release)
+	if err := <-second; err != nil {
+		t.Fatalf("Cancelling the first caller failed the second: %v", err)
+	}
+	if resp["name"] != "jsext" {
+		t.Error("Wrong response: ", resp)
+	}
+}
+
+func TestCacheInvalidateCustomKey(t *testing.T) {
+	var server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	var cache = client.NewCache(&client.CacheOptions{
+		InvalidateOnMutate: true,
+		Key: func(r *http.Request) string {
+			return "user:1:" + r.URL.Path
+		},
+	})
+	var c = client.New(client.BaseURL(server.URL), cache.Interceptor())
+	c.Get(context.Background(), "/posts", nil)
+	c.Get(context.Background(), "/users", nil)
+	c.Post(context.Background(), "/posts", map[string]string{"title": "new"}, nil)
+	if cache.Len() != 1 {
+		t.Fatalf("Expected 1 cached response after mutation, got %d", cache.Len())
+	}
+}
@@ -0,0 +1,236 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// Interceptor wraps the transport of the client.
+// It can change the request before it is sent, and the response after it is received.
+type Interceptor func(next http.RoundTripper) http.RoundTripper
+
+// RoundTripperFunc is an adapter to use a function as a http.RoundTripper.
+type RoundTripperFunc func(r *http.Request) (*http.Response, error)
+
+// RoundTrip calls f(r).
+func (f RoundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+// Client to make requests through a chain of interceptors.
+//
+// The client is safe for concurrent use.
+// Interceptors are called in the order they were added,
+// the first interceptor added is the first to see the request
+// and the last to see the response.
+type Client struct {
+	// Transport used to make the actual request.
+	// Defaults to http.DefaultTransport, which uses the fetch API when compiled to WebAssembly.
+	Transport    http.RoundTripper
+	interceptors []Interceptor
+	inFlight     int
+	onBusy       []func()
+	onIdle       []func()
+	mu           *sync.Mutex
+}
+
+// Initialize a new client with the given interceptors.
+func New(interceptors ...Interceptor) *Client {
+	return &Client{
+		interceptors: interceptors,
+		mu:           &sync.Mutex{},
+	}
+}
+
+// Add interceptors to the client.
+func (c *Client) Use(interceptors ...Interceptor) *Client {
+	c.mu.Lock()
+	c.interceptors = append(c.interceptors, interceptors...)
+	c.mu.Unlock()
+	return c
+}
+
+// Function to call when the client starts a request while no other requests are in flight.
+func (c *Client) OnBusy(f func()) *Client {
+	c.mu.Lock()
+	c.onBusy = append(c.onBusy, f)
+	c.mu.Unlock()
+	return c
+}
+
+// Function to call when all requests in flight have finished.
+func (c *Client) OnIdle(f func()) *Client {
+	c.mu.Lock()
+	c.onIdle = append(c.onIdle, f)
+	c.mu.Unlock()
+	return c
+}
+
+// Amount of requests currently in flight.
+func (c *Client) InFlight() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.inFlight
+}
+
+// Track a request which is not made through this client.
+// The returned function must be called when the request has finished.
+func (c *Client) Track() (done func()) {
+	c.mu.Lock()
+	c.inFlight++
+	var hooks []func()
+	if c.inFlight == 1 {
+		hooks = c.onBusy
+	}
+	c.mu.Unlock()
+	for _, f := range hooks {
+		f()
+	}
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			c.mu.Lock()
+			c.inFlight--
+			var hooks []func()
+			if c.inFlight == 0 {
+				hooks = c.onIdle
+			}
+			c.mu.Unlock()
+			for _, f := range hooks {
+				f()
+			}
+		})
+	}
+}
+
+// Build the transport chain.
+func (c *Client) transport() http.RoundTripper {
+	var rt = c.Transport
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+	c.mu.Lock()
+	var interceptors = c.interceptors
+	c.mu.Unlock()
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		rt = interceptors[i](rt)
+	}
+	return rt
+}
+
+// Execute a request through the interceptor chain.
+func (c *Client) Do(r *http.Request) (*http.Response, error) {
+	return c.HTTPClient().Do(r)
+}
+
+// Standard library client which makes its requests through the interceptor chain.
+// Requests are tracked like requests made with Do.
+func (c *Client) HTTPClient() *http.Client {
+	return &http.Client{Transport: RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		var done = c.Track()
+		defer done()
+		return c.transport().RoundTrip(r)
+	})}
+}
+
+// Create a new request.
+//
+// The body can be one of the following types:
+//
+//   - nil
+//   - io.Reader
+//   - []byte
+//   - string
+//   - any other type will be encoded to JSON.
+func (c *Client) NewRequest(ctx context.Context, method, url string, body any) (*http.Request, error) {
+	var reader io.Reader
+	var isJSON bool
+	switch body := body.(type) {
+	case nil:
+	case io.Reader:
+		reader = body
+	case []byte:
+		reader = bytes.NewReader(body)
+	case string:
+		reader = strings.NewReader(body)
+	default:
+		var b, err = json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewReader(b)
+		isJSON = true
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	var r, err = http.NewRequestWithContext(ctx, method, url, reader)
+	if err != nil {
+		return nil, err
+	}
+	if isJSON {
+		r.Header.Set("Content-Type", "application/json")
+	}
+	return r, nil
+}
+
+// Make a request, and decode the JSON response into v.
+// If v is nil, the response body is discarded.
+func (c *Client) Send(ctx context.Context, method, url string, body any, v any) error {
+	// Keep tracking the request until the body has been decoded.
+	var done = c.Track()
+	defer done()
+	var r, err = c.NewRequest(ctx, method, url, body)
+	if err != nil {
+		return err
+	}
+	r.Header.Set("Accept", "application/json")
+	resp, err := c.Do(r)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		return NewError(resp)
+	}
+	if v == nil {
+		_, err = io.Copy(io.Discard, resp.Body)
+		return err
+	}
+	err = json.NewDecoder(resp.Body).Decode(v)
+	if errors.Is(err, io.EOF) {
+		return nil
+	}
+	return err
+}
+
+// Make a GET request, and decode the JSON response into v.
+func (c *Client) Get(ctx context.Context, url string, v any) error {
+	return c.Send(ctx, http.MethodGet, url, nil, v)
+}
+
+// Make a POST request, and decode the JSON response into v.
+func (c *Client) Post(ctx context.Context, url string, body any, v any) error {
+	return c.Send(ctx, http.MethodPost, url, body, v)
+}
+
+// Make a PUT request, and decode the JSON response into v.
+func (c *Client) Put(ctx context.Context, url string, body any, v any) error {
+	return c.Send(ctx, http.MethodPut, url, body, v)
+}
+
+// Make a PATCH request, and decode the JSON response into v.
+func (c *Client) Patch(ctx context.Context, url string, body any, v any) error {
+	return c.Send(ctx, http.MethodPatch, url, body, v)
+}
+
+// Make a DELETE request, and decode the JSON response into v.
+func (c *Client) Delete(ctx context.Context, url string, v any) error {
+	return c.Send(ctx, http.MethodDelete, url, nil, v)
+}
@@ -0,0 +1,156 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Nigel2392/jsext-framework/client"
+)
+
+func TestClient(t *testing.T) {
+	var server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"detail": "not authorized"}`))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"path": "` + r.URL.Path + `"}`))
+	}))
+	defer server.Close()
+
+	var token = "token"
+	var c = client.New(
+		client.BaseURL(server.URL),
+		client.BearerAuth(func() string { return token }),
+		client.DecodeErrors(),
+	)
+
+	var resp map[string]string
+	if err := c.Get(context.Background(), "/api/posts", &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp["path"] != "/api/posts" {
+		t.Error("Wrong path! " + resp["path"])
+	}
+
+	token = "invalid"
+	var err = c.Get(context.Background(), "/api/posts", &resp)
+	if !client.IsStatus(err, http.StatusUnauthorized) {
+		t.Fatal("Expected 401 error, got: ", err)
+	}
+	var e *client.Error
+	if !errors.As(err, &e) {
+		t.Fatal("Expected a client error")
+	}
+	if e.Message != "not authorized" {
+		t.Error("Wrong message! " + e.Message)
+	}
+}
+
+func TestRetry(t *testing.T) {
+	var calls int32
+	var server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	var c = client.New(
+		client.BaseURL(server.URL),
+		client.Retry(&client.RetryOptions{Backoff: time.Millisecond}),
+		client.DecodeErrors(),
+	)
+	if err := c.Get(context.Background(), "/", nil); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 3 {
+		t.Errorf("Expected 3 calls, got %d", calls)
+	}
+
+	atomic.StoreInt32(&calls, 0)
+	if err := c.Post(context.Background(), "/", map[string]string{"a": "b"}, nil); !client.IsStatus(err, http.StatusServiceUnavailable) {
+		t.Fatal("POST should not be retried, got: ", err)
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	var server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+	defer server.Close()
+
+	var c = client.New(client.BaseURL(server.URL), client.Timeout(10*time.Millisecond))
+	if err := c.Get(context.Background(), "/", nil); err == nil {
+		t.Fatal("Expected timeout error")
+	}
+}
+
+func TestInFlight(t *testing.T) {
+	var release = make(chan struct{})
+	var server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+
+	var busy, idle int32
+	var c = client.New(client.BaseURL(server.URL))
+	c.OnBusy(func() { atomic.AddInt32(&busy, 1) })
+	c.OnIdle(func() { atomic.AddInt32(&idle, 1) })
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.Get(context.Background(), "/", nil)
+		}()
+	}
+	for c.InFlight() < 5 {
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+	wg.Wait()
+
+	if busy != 1 || idle != 1 {
+		t.Errorf("Expected busy and idle to be called once, got %d and %d", busy, idle)
+	}
+	if c.InFlight() != 0 {
+		t.Errorf("Expected no requests in flight, got %d", c.InFlight())
+	}
+}
+
+func TestHTTPClient(t *testing.T) {
+	var server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Authorization", r.Header.Get("Authorization"))
+	}))
+	defer server.Close()
+
+	var c = client.New(client.BearerAuth(func() string { return "token" }))
+	var busy, idle int32
+	c.OnBusy(func() { atomic.AddInt32(&busy, 1) })
+	c.OnIdle(func() { atomic.AddInt32(&idle, 1) })
+	var resp, err = c.HTTPClient().Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if got := resp.Header.Get("X-Authorization"); got != "Bearer token" {
+		t.Errorf("Expected the interceptors to run, got %q", got)
+	}
+	if busy != 1 || idle != 1 || c.InFlight() != 0 {
+		t.Errorf("Expected the request to be tracked, got %d busy, %d idle, %d in flight", busy, idle, c.InFlight())
+	}
+}
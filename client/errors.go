@@ -0,0 +1,77 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+)
+
+// Maximum size of an error body which will be read.
+var MaxErrorBodySize int64 = 1 << 20
+
+// Error returned for responses with a status code of 400 or higher.
+type Error struct {
+	// Status code of the response.
+	StatusCode int
+	// Status of the response.
+	Status string
+	// Raw body of the response.
+	Body []byte
+	// Message found in the JSON body, if any.
+	// Looks for the "detail", "message", "error" and "title" fields.
+	Message string
+	// The JSON body decoded as an object, if possible.
+	Fields map[string]any
+	// Response the error was created from, the body has already been read.
+	Response *http.Response
+}
+
+func (e *Error) Error() string {
+	if e.Message != "" {
+		return "http error " + strconv.Itoa(e.StatusCode) + ": " + e.Message
+	}
+	return "http error " + strconv.Itoa(e.StatusCode) + ": " + e.Status
+}
+
+// Create a new error from a response.
+// The body of the response is read and closed.
+func NewError(resp *http.Response) *Error {
+	var e = &Error{
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		Response:   resp,
+	}
+	if resp.Body != nil {
+		e.Body, _ = io.ReadAll(io.LimitReader(resp.Body, MaxErrorBodySize))
+		resp.Body.Close()
+	}
+	if len(e.Body) > 0 && json.Unmarshal(e.Body, &e.Fields) == nil {
+		for _, key := range []string{"detail", "message", "error", "title"} {
+			if msg, ok := e.Fields[key].(string); ok && msg != "" {
+				e.Message = msg
+				break
+			}
+		}
+	}
+	return e
+}
+
+// Check if the error is a client error, and if the status code matches any of the given codes.
+// If no codes are given, returns true for any client error.
+func IsStatus(err error, code ...int) bool {
+	var e *Error
+	if !errors.As(err, &e) {
+		return false
+	}
+	if len(code) == 0 {
+		return true
+	}
+	for _, c := range code {
+		if e.StatusCode == c {
+			return true
+		}
+	}
+	return false
+}
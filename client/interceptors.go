@@ -0,0 +1,210 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Resolve relative request URLs against the base URL.
+func BaseURL(base string) Interceptor {
+	var baseURL, err = url.Parse(base)
+	if err != nil {
+		panic("invalid base url: " + err.Error())
+	}
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			if r.URL.IsAbs() {
+				return next.RoundTrip(r)
+			}
+			r = r.Clone(r.Context())
+			r.URL = baseURL.ResolveReference(r.URL)
+			r.Host = r.URL.Host
+			return next.RoundTrip(r)
+		})
+	}
+}
+
+// Set a header on every request, if it has not been set yet.
+func Header(key, value string) Interceptor {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			if r.Header.Get(key) != "" {
+				return next.RoundTrip(r)
+			}
+			r = r.Clone(r.Context())
+			r.Header.Set(key, value)
+			return next.RoundTrip(r)
+		})
+	}
+}
+
+// Set the Authorization header on every request.
+// The token function is called for every request,
+// if it returns an empty string, no header is set.
+func Auth(scheme string, token func() string) Interceptor {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			var t = token()
+			if t == "" || r.Header.Get("Authorization") != "" {
+				return next.RoundTrip(r)
+			}
+			r = r.Clone(r.Context())
+			r.Header.Set("Authorization", scheme+" "+t)
+			return next.RoundTrip(r)
+		})
+	}
+}
+
+// Set the Authorization header to "Bearer <token>" on every request.
+func BearerAuth(token func() string) Interceptor {
+	return Auth("Bearer", token)
+}
+
+// Turn responses with a status code of 400 or higher into an *Error.
+func DecodeErrors() Interceptor {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			var resp, err = next.RoundTrip(r)
+			if err != nil {
+				return nil, err
+			}
+			if resp.StatusCode >= 400 {
+				return nil, NewError(resp)
+			}
+			return resp, nil
+		})
+	}
+}
+
+// Cancel the request if it takes longer than the timeout.
+// The timeout includes reading the response body.
+func Timeout(d time.Duration) Interceptor {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			var ctx, cancel = context.WithTimeout(r.Context(), d)
+			var resp, err = next.RoundTrip(r.WithContext(ctx))
+			if err != nil {
+				cancel()
+				return nil, err
+			}
+			resp.Body = &cancelBody{ReadCloser: resp.Body, cancel: cancel}
+			return resp, nil
+		})
+	}
+}
+
+// Cancel the context when the body is closed.
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	var err = b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+// Options for the Retry interceptor.
+type RetryOptions struct {
+	// Maximum amount of retries.
+	MaxRetries int
+	// Time to wait before the first retry.
+	// This is doubled after every retry.
+	Backoff time.Duration
+	// Maximum time to wait between retries.
+	MaxBackoff time.Duration
+	// Decide if a request should be retried.
+	// Defaults to retrying idempotent requests on network errors,
+	// 429 and 5xx responses.
+	ShouldRetry func(r *http.Request, resp *http.Response, err error) bool
+}
+
+func (o *RetryOptions) SetDefaults() {
+	if o.MaxRetries == 0 {
+		o.MaxRetries = 3
+	}
+	if o.Backoff == 0 {
+		o.Backoff = 250 * time.Millisecond
+	}
+	if o.MaxBackoff == 0 {
+		o.MaxBackoff = 10 * time.Second
+	}
+	if o.ShouldRetry == nil {
+		o.ShouldRetry = DefaultShouldRetry
+	}
+}
+
+// Retry idempotent requests on network errors, 429 and 5xx responses.
+func DefaultShouldRetry(r *http.Request, resp *http.Response, err error) bool {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+	default:
+		return false
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var code int
+	var e *Error
+	if errors.As(err, &e) {
+		code = e.StatusCode
+	} else if err != nil {
+		return true
+	} else if resp != nil {
+		code = resp.StatusCode
+	}
+	return code == http.StatusTooManyRequests || code >= 500
+}
+
+// Retry failed requests with exponential backoff.
+// Waiting between retries is cancelled when the request context is done.
+func Retry(opts *RetryOptions) Interceptor {
+	var o RetryOptions
+	if opts != nil {
+		o = *opts
+	}
+	o.SetDefaults()
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			var backoff = o.Backoff
+			for attempt := 0; ; attempt++ {
+				var rq = r
+				if attempt > 0 && r.Body != nil && r.Body != http.NoBody {
+					if r.GetBody == nil {
+						return nil, errors.New("cannot retry request, body cannot be replayed")
+					}
+					var body, err = r.GetBody()
+					if err != nil {
+						return nil, err
+					}
+					rq = r.Clone(r.Context())
+					rq.Body = body
+				}
+				var resp, err = next.RoundTrip(rq)
+				if attempt >= o.MaxRetries || !o.ShouldRetry(r, resp, err) {
+					return resp, err
+				}
+				if resp != nil {
+					io.Copy(io.Discard, resp.Body)
+					resp.Body.Close()
+				}
+				var timer = time.NewTimer(backoff)
+				select {
+				case <-r.Context().Done():
+					timer.Stop()
+					return nil, r.Context().Err()
+				case <-timer.C:
+				}
+				backoff *= 2
+				if backoff > o.MaxBackoff {
+					backoff = o.MaxBackoff
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,367 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Field of a struct which can be bound.
+type bindField struct {
+	Name  string
+	JSON  string
+	Kind  string
+	Type  string
+	Bits  int
+	Input string
+}
+
+// Struct to generate a binding for.
+type bindStruct struct {
+	Name    string
+	Fields  []bindField
+	Skipped []string
+}
+
+var bitSizes = map[string]int{
+	"int": 0, "int8": 8, "int16": 16, "int32": 32, "int64": 64,
+	"uint": 0, "uint8": 8, "uint16": 16, "uint32": 32, "uint64": 64,
+	"float32": 32, "float64": 64,
+}
+
+func runBind(args []string) error {
+	var flags = flag.NewFlagSet("bind", flag.ExitOnError)
+	var types = flags.String("type", "", "comma separated struct types to generate bindings for, defaults to all structs in the file")
+	var out = flags.String("o", "", "output file, defaults to <file>_gen.go")
+	var file = parseArgs(flags, args, "bind [-type A,B] [-o file_gen.go] <file.go>")
+	var src, err = os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	var names []string
+	if *types != "" {
+		names = strings.Split(*types, ",")
+	}
+	code, err := bindFile(file, src, names)
+	if err != nil {
+		return err
+	}
+	if *out == "" {
+		*out = strings.TrimSuffix(file, ".go") + "_gen.go"
+	}
+	fmt.Println("writing", *out)
+	return os.WriteFile(*out, code, 0644)
+}
+
+// Generate the bindings for the structs in the source file.
+func bindFile(filename string, src []byte, names []string) ([]byte, error) {
+	var fset = token.NewFileSet()
+	var file, err = parser.ParseFile(fset, filename, src, 0)
+	if err != nil {
+		return nil, err
+	}
+	var declared = make(map[string]*ast.StructType)
+	var order []string
+	ast.Inspect(file, func(n ast.Node) bool {
+		if spec, ok := n.(*ast.TypeSpec); ok {
+			if st, ok := spec.Type.(*ast.StructType); ok && spec.TypeParams == nil {
+				declared[spec.Name.Name] = st
+				order = append(order, spec.Name.Name)
+			}
+		}
+		return true
+	})
+	if len(names) == 0 {
+		names = order
+	}
+	if len(names) == 0 {
+		return nil, errors.New("no struct types found in " + filename)
+	}
+
+	var structs []*bindStruct
+	var seen = make(map[string]bool)
+	var add func(name string) error
+	add = func(name string) error {
+		if seen[name] {
+			return nil
+		}
+		seen[name] = true
+		var st, ok = declared[name]
+		if !ok {
+			return fmt.Errorf("struct type %s not found in %s", name, filename)
+		}
+		var s = &bindStruct{Name: name}
+		structs = append(structs, s)
+		for _, f := range st.Fields.List {
+			if len(f.Names) == 0 {
+				s.Skipped = append(s.Skipped, exprString(f.Type)+" (embedded)")
+				continue
+			}
+			var kind, typ = fieldKind(f.Type)
+			if kind == "struct" && declared[typ] == nil {
+				kind = ""
+			}
+			for _, n := range f.Names {
+				if !n.IsExported() {
+					continue
+				}
+				if kind == "" {
+					s.Skipped = append(s.Skipped, n.Name+" ("+exprString(f.Type)+")")
+					continue
+				}
+				var field = bindField{Name: n.Name, JSON: n.Name, Kind: kind, Type: typ, Bits: bitSizes[typ], Input: inputType(kind)}
+				if f.Tag != nil {
+					var tag, _ = strconv.Unquote(f.Tag.Value)
+					var st = reflect.StructTag(tag)
+					if name := strings.Split(st.Get("json"), ",")[0]; name != "" {
+						field.JSON = name
+					}
+					if input := st.Get("type"); input != "" {
+						field.Input = input
+					}
+				}
+				s.Fields = append(s.Fields, field)
+			}
+			if kind == "struct" {
+				if err := add(typ); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+	for _, name := range names {
+		if err := add(strings.TrimSpace(name)); err != nil {
+			return nil, err
+		}
+	}
+	return generateBindings(file.Name.Name, structs)
+}
+
+// Kind and type name of a field type, the kind is empty if the type is not supported.
+func fieldKind(expr ast.Expr) (kind, typ string) {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		switch {
+		case t.Name == "string":
+			return "string", t.Name
+		case t.Name == "bool":
+			return "bool", t.Name
+		case strings.HasPrefix(t.Name, "int"):
+			if _, ok := bitSizes[t.Name]; ok {
+				return "int", t.Name
+			}
+		case strings.HasPrefix(t.Name, "uint"):
+			if _, ok := bitSizes[t.Name]; ok {
+				return "uint", t.Name
+			}
+		case t.Name == "float32" || t.Name == "float64":
+			return "float", t.Name
+		default:
+			return "struct", t.Name
+		}
+	case *ast.SelectorExpr:
+		if x, ok := t.X.(*ast.Ident); ok && x.Name == "time" && t.Sel.Name == "Time" {
+			return "time", "time.Time"
+		}
+	}
+	return "", ""
+}
+
+// Kind and type name of a type written as Go source, such as "int32" or "time.Time".
+func fieldKindOf(typ string) (kind, name string) {
+	var expr, err = parser.ParseExpr(typ)
+	if err != nil {
+		return "", ""
+	}
+	return fieldKind(expr)
+}
+
+func inputType(kind string) string {
+	switch kind {
+	case "bool":
+		return "checkbox"
+	case "int", "uint", "float":
+		return "number"
+	case "time":
+		return "datetime-local"
+	}
+	return "text"
+}
+
+func exprString(expr ast.Expr) string {
+	var b bytes.Buffer
+	format.Node(&b, token.NewFileSet(), expr)
+	return b.String()
+}
+
+func generateBindings(pkg string, structs []*bindStruct) ([]byte, error) {
+	var usesStrconv, usesTime bool
+	for _, s := range structs {
+		for _, f := range s.Fields {
+			switch f.Kind {
+			case "bool", "int", "uint", "float":
+				usesStrconv = true
+			case "time":
+				usesTime = true
+			}
+		}
+	}
+	var b bytes.Buffer
+	b.WriteString("// Code generated by jsext bind DO NOT EDIT.\n\n")
+	b.WriteString("package " + pkg + "\n\nimport (\n")
+	if usesStrconv {
+		b.WriteString("\t\"strconv\"\n")
+	}
+	if usesTime {
+		b.WriteString("\t\"time\"\n")
+	}
+	b.WriteString("\n\t\"github.com/Nigel2392/jsext-framework/bind\"\n)\n")
+	for _, s := range structs {
+		writeBinding(&b, s)
+	}
+	var code, err = format.Source(b.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting generated code: %w\n%s", err, b.String())
+	}
+	return code, nil
+}
+
+func writeBinding(b *bytes.Buffer, s *bindStruct) {
+	var p = func(format string, args ...any) {
+		fmt.Fprintf(b, format+"\n", args...)
+	}
+	p("")
+	for _, skipped := range s.Skipped {
+		fmt.Fprintf(os.Stderr, "jsext: %s: skipped field %s, the type is not supported\n", s.Name, skipped)
+		p("// %s: skipped field %s, the type is not supported.", s.Name, skipped)
+	}
+	if len(s.Skipped) > 0 {
+		p("")
+	}
+
+	p("// FormFields returns the name, value and input type of the fields, implementing forms.Binding.")
+	p("func (z *%s) FormFields() [][3]string {\n\treturn z.formFields(\"\")\n}\n", s.Name)
+	p("func (z *%s) formFields(prefix string) [][3]string {", s.Name)
+	p("\tvar fields = make([][3]string, 0, %d)", len(s.Fields))
+	for _, f := range s.Fields {
+		if f.Kind == "struct" {
+			p("\tfields = append(fields, z.%s.formFields(prefix+%q+bind.Delimiter)...)", f.Name, f.Name)
+			continue
+		}
+		p("\tfields = append(fields, [3]string{prefix + %q, %s, %q})", f.Name, formValue(f), f.Input)
+	}
+	p("\treturn fields\n}\n")
+
+	p("// SetFormData sets the fields from submitted form data, implementing forms.Binding.")
+	p("func (z *%s) SetFormData(data map[string]string) error {\n\treturn z.setFormData(data, \"\")\n}\n", s.Name)
+	p("func (z *%s) setFormData(data map[string]string, prefix string) error {", s.Name)
+	for _, f := range s.Fields {
+		var key = "prefix+" + strconv.Quote(f.Name)
+		switch f.Kind {
+		case "string":
+			p("\tif v, ok := data[%s]; ok {\n\t\tz.%s = v\n\t}", key, f.Name)
+		case "bool":
+			p("\tz.%s = bind.FormBool(data[%s])", f.Name, key)
+		case "int", "uint", "float":
+			var parse = map[string]string{"int": "FormInt", "uint": "FormUint", "float": "FormFloat"}[f.Kind]
+			var bits = f.Bits
+			if f.Kind == "float" && bits == 0 {
+				bits = 64
+			}
+			p("\tif v := data[%s]; v != \"\" {", key)
+			p("\t\tvar n, err = bind.%s(%s, v, %d)\n\t\tif err != nil {\n\t\t\treturn err\n\t\t}", parse, key, bits)
+			p("\t\tz.%s = %s(n)\n\t}", f.Name, f.Type)
+		case "time":
+			p("\tif v := data[%s]; v != \"\" {", key)
+			p("\t\tvar t, err = bind.FormTime(%s, v)\n\t\tif err != nil {\n\t\t\treturn err\n\t\t}\n\t\tz.%s = t\n\t}", key, f.Name)
+		case "struct":
+			p("\tif err := z.%s.setFormData(data, prefix+%q+bind.Delimiter); err != nil {\n\t\treturn err\n\t}", f.Name, f.Name)
+		}
+	}
+	p("\treturn nil\n}\n")
+
+	p("// FromMap sets the fields from a decoded JSON object.")
+	p("func (z *%s) FromMap(m map[string]any) error {", s.Name)
+	for _, f := range s.Fields {
+		if f.JSON == "-" {
+			continue
+		}
+		var decode, conv string
+		switch f.Kind {
+		case "string":
+			decode, conv = "String", ""
+		case "bool":
+			decode, conv = "Bool", ""
+		case "int":
+			decode, conv = "Int", f.Type
+		case "uint":
+			decode, conv = "Uint", f.Type
+		case "float":
+			decode, conv = "Float", f.Type
+		case "time":
+			decode, conv = "Time", ""
+		case "struct":
+			decode = "Map"
+		}
+		p("\tif v, ok := m[%q]; ok && v != nil {", f.JSON)
+		p("\t\tvar x, err = bind.%s(%q, v)\n\t\tif err != nil {\n\t\t\treturn err\n\t\t}", decode, f.JSON)
+		switch {
+		case f.Kind == "struct":
+			p("\t\tif err = z.%s.FromMap(x); err != nil {\n\t\t\treturn err\n\t\t}", f.Name)
+		case conv != "":
+			p("\t\tz.%s = %s(x)", f.Name, conv)
+		default:
+			p("\t\tz.%s = x", f.Name)
+		}
+		p("\t}")
+	}
+	p("\treturn nil\n}\n")
+
+	p("// ToMap returns the fields as a JSON object.")
+	p("func (z *%s) ToMap() map[string]any {\n\treturn map[string]any{", s.Name)
+	for _, f := range s.Fields {
+		if f.JSON == "-" {
+			continue
+		}
+		switch f.Kind {
+		case "time":
+			p("\t\t%q: z.%s.Format(time.RFC3339Nano),", f.JSON, f.Name)
+		case "struct":
+			p("\t\t%q: z.%s.ToMap(),", f.JSON, f.Name)
+		default:
+			p("\t\t%q: z.%s,", f.JSON, f.Name)
+		}
+	}
+	p("\t}\n}")
+}
+
+// Expression formatting the field as a form value.
+func formValue(f bindField) string {
+	switch f.Kind {
+	case "bool":
+		return "strconv.FormatBool(z." + f.Name + ")"
+	case "int":
+		return "strconv.FormatInt(int64(z." + f.Name + "), 10)"
+	case "uint":
+		return "strconv.FormatUint(uint64(z." + f.Name + "), 10)"
+	case "float":
+		var bits = f.Bits
+		if bits == 0 {
+			bits = 64
+		}
+		return "strconv.FormatFloat(float64(z." + f.Name + "), 'f', -1, " + strconv.Itoa(bits) + ")"
+	case "time":
+		return "z." + f.Name + ".Format(bind.FormTimeLayout)"
+	}
+	return "z." + f.Name
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"testing"
+)
+
+// The generated bindings in bind/bindtest must be up to date with the generator.
+func TestBindGolden(t *testing.T) {
+	var src, err = os.ReadFile("../../bind/bindtest/user.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := os.ReadFile("../../bind/bindtest/user_gen.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := bindFile("user.go", src, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Fatalf("bind/bindtest/user_gen.go is outdated, run go generate ./bind/bindtest\n%s", got)
+	}
+}
+
+func TestBindUnknownType(t *testing.T) {
+	var _, err = bindFile("x.go", []byte("package x\n\ntype A struct{ B string }\n"), []string{"C"})
+	if err == nil {
+		t.Fatal("expected an error for an unknown type")
+	}
+}
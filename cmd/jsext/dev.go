@@ -0,0 +1,280 @@
+package main
+
+import (
+	_ "embed"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Paths served by the dev server itself.
+const (
+	devScriptPath    = "/__jsext/reload.js"
+	devWebsocketPath = "/__jsext/ws"
+)
+
+//go:embed reload.js
+var reloadScript []byte
+
+// Message sent to the browsers.
+type devMessage struct {
+	Type   string `json:"type"`
+	Output string `json:"output,omitempty"`
+}
+
+// Development server, serves the static directory and notifies the connected browsers of builds.
+type devServer struct {
+	Dir     string
+	mu      sync.Mutex
+	clients map[*wsConn]bool
+	// Output of the last failed build, empty if the build succeeded.
+	buildErr string
+}
+
+func newDevServer(dir string) *devServer {
+	return &devServer{Dir: dir, clients: make(map[*wsConn]bool)}
+}
+
+func (s *devServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case devScriptPath:
+		w.Header().Set("Content-Type", "text/javascript; charset=utf-8")
+		w.Write(reloadScript)
+	case devWebsocketPath:
+		s.serveWebsocket(w, r)
+	default:
+		w.Header().Set("Cache-Control", "no-store")
+		s.serveFile(w, r)
+	}
+}
+
+// Serve a file of the static directory.
+// Paths without a file extension which do not exist fall back to the index.html,
+// so that the router of the application handles deep links.
+func (s *devServer) serveFile(w http.ResponseWriter, r *http.Request) {
+	var name = path.Clean("/" + r.URL.Path)
+	var file = filepath.Join(s.Dir, filepath.FromSlash(name))
+	var info, err = os.Stat(file)
+	if err == nil && info.IsDir() {
+		file = filepath.Join(file, "index.html")
+		_, err = os.Stat(file)
+	}
+	if err != nil {
+		if path.Ext(name) != "" {
+			http.NotFound(w, r)
+			return
+		}
+		file = filepath.Join(s.Dir, "index.html")
+	}
+	if strings.HasSuffix(file, ".html") {
+		var b, err = os.ReadFile(file)
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write(injectReloadScript(b))
+		return
+	}
+	if strings.HasSuffix(file, ".wasm") {
+		w.Header().Set("Content-Type", "application/wasm")
+	}
+	http.ServeFile(w, r, file)
+}
+
+// Add the live reload script to a page, before the end of the body.
+func injectReloadScript(page []byte) []byte {
+	var script = `<script src="` + devScriptPath + `"></script>`
+	var html = string(page)
+	if i := strings.LastIndex(html, "</body>"); i >= 0 {
+		return []byte(html[:i] + script + html[i:])
+	}
+	return []byte(html + script)
+}
+
+func (s *devServer) serveWebsocket(w http.ResponseWriter, r *http.Request) {
+	var conn, err = upgradeWebsocket(w, r)
+	if err != nil {
+		return
+	}
+	s.mu.Lock()
+	s.clients[conn] = true
+	var buildErr = s.buildErr
+	s.mu.Unlock()
+	if buildErr != "" {
+		send(conn, devMessage{Type: "error", Output: buildErr})
+	}
+	conn.Wait()
+	s.mu.Lock()
+	delete(s.clients, conn)
+	s.mu.Unlock()
+	conn.Close()
+}
+
+func send(conn *wsConn, m devMessage) error {
+	var b, _ = json.Marshal(m)
+	return conn.Send(b)
+}
+
+// Record the result of a build, and reload the connected browsers or show the compiler output.
+func (s *devServer) Built(output string, ok bool) {
+	s.mu.Lock()
+	s.buildErr = output
+	s.mu.Unlock()
+	if ok {
+		s.Broadcast(devMessage{Type: "reload"})
+	} else {
+		s.Broadcast(devMessage{Type: "error", Output: output})
+	}
+}
+
+// Send a message to all connected browsers.
+func (s *devServer) Broadcast(m devMessage) {
+	s.mu.Lock()
+	var clients = make([]*wsConn, 0, len(s.clients))
+	for conn := range s.clients {
+		clients = append(clients, conn)
+	}
+	s.mu.Unlock()
+	for _, conn := range clients {
+		if err := send(conn, m); err != nil {
+			conn.Close()
+		}
+	}
+}
+
+// Builds the application for js/wasm.
+type builder struct {
+	TinyGo bool
+	Tags   string
+	Pkg    string
+	Out    string
+}
+
+// Build the application, returns the compiler output if the build failed.
+func (b *builder) Build() (string, bool) {
+	var cmd *exec.Cmd
+	if b.TinyGo {
+		var tags = "tinygo"
+		if b.Tags != "" {
+			tags += "," + b.Tags
+		}
+		cmd = exec.Command("tinygo", "build", "-target", "wasm", "-tags", tags, "-o", b.Out, b.Pkg)
+	} else {
+		cmd = exec.Command("go", "build", "-tags", b.Tags, "-o", b.Out, b.Pkg)
+		cmd.Env = append(os.Environ(), "GOOS=js", "GOARCH=wasm")
+	}
+	var output, err = cmd.CombinedOutput()
+	if err != nil {
+		if len(output) == 0 {
+			output = []byte(err.Error())
+		}
+		return string(output), false
+	}
+	return "", true
+}
+
+// Modification times of the files matching the filter, to detect changes by polling.
+func snapshot(root string, match func(path string, d fs.DirEntry) bool) map[string]time.Time {
+	var files = make(map[string]time.Time)
+	filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			if p != root && (strings.HasPrefix(d.Name(), ".") || d.Name() == "node_modules") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !match(p, d) {
+			return nil
+		}
+		if info, err := d.Info(); err == nil {
+			files[p] = info.ModTime()
+		}
+		return nil
+	})
+	return files
+}
+
+func changed(a, b map[string]time.Time) bool {
+	if len(a) != len(b) {
+		return true
+	}
+	for k, v := range a {
+		if w, ok := b[k]; !ok || !w.Equal(v) {
+			return true
+		}
+	}
+	return false
+}
+
+func runDev(args []string) error {
+	var flags = flag.NewFlagSet("dev", flag.ExitOnError)
+	var addr = flags.String("addr", "localhost:8080", "address to serve on")
+	var dir = flags.String("dir", "static", "directory to serve, containing index.html and wasm_exec.js")
+	var out = flags.String("o", "main.wasm", "name of the built binary in the served directory")
+	var tinygo = flags.Bool("tinygo", false, "build with TinyGo")
+	var tags = flags.String("tags", "", "comma separated build tags, such as devtools")
+	var interval = flags.Duration("interval", 500*time.Millisecond, "interval to check for changes")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: jsext dev [flags] [package]")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+	var b = &builder{TinyGo: *tinygo, Tags: *tags, Pkg: ".", Out: filepath.Join(*dir, *out)}
+	if flags.NArg() > 0 {
+		b.Pkg = flags.Arg(0)
+	}
+	var server = newDevServer(*dir)
+	var staticDir = filepath.Clean(*dir)
+
+	var isSource = func(p string, d fs.DirEntry) bool {
+		if strings.HasPrefix(filepath.Clean(p), staticDir+string(filepath.Separator)) {
+			return false
+		}
+		return strings.HasSuffix(p, ".go") || d.Name() == "go.mod" || d.Name() == "go.sum"
+	}
+	var isAsset = func(p string, d fs.DirEntry) bool {
+		return filepath.Clean(p) != filepath.Clean(b.Out)
+	}
+	var rebuild = func() {
+		var start = time.Now()
+		var output, ok = b.Build()
+		if ok {
+			fmt.Printf("built %s in %s\n", b.Out, time.Since(start).Round(time.Millisecond))
+		} else {
+			fmt.Fprint(os.Stderr, output)
+		}
+		server.Built(output, ok)
+	}
+
+	rebuild()
+	go func() {
+		var sources = snapshot(".", isSource)
+		var assets = snapshot(staticDir, isAsset)
+		for range time.Tick(*interval) {
+			var newSources = snapshot(".", isSource)
+			var newAssets = snapshot(staticDir, isAsset)
+			switch {
+			case changed(sources, newSources):
+				rebuild()
+			case changed(assets, newAssets):
+				server.Broadcast(devMessage{Type: "reload"})
+			}
+			sources, assets = newSources, newAssets
+		}
+	}()
+	fmt.Printf("serving %s on http://%s\n", *dir, *addr)
+	return http.ListenAndServe(*addr, server)
+}
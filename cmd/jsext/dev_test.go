@@ -0,0 +1,76 @@
+package main
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func get(t *testing.T, url string) (int, string) {
+	t.Helper()
+	var resp, err = http.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var b, _ = io.ReadAll(resp.Body)
+	return resp.StatusCode, string(b)
+}
+
+func TestDevServerFiles(t *testing.T) {
+	var dir = t.TempDir()
+	os.WriteFile(filepath.Join(dir, "index.html"), []byte("<html><body>app</body></html>"), 0644)
+	os.WriteFile(filepath.Join(dir, "main.wasm"), []byte("wasm"), 0644)
+	var server = httptest.NewServer(newDevServer(dir))
+	defer server.Close()
+
+	for _, path := range []string{"/", "/users/1/edit"} {
+		var status, body = get(t, server.URL+path)
+		if status != http.StatusOK || body != `<html><body>app<script src="/__jsext/reload.js"></script></body></html>` {
+			t.Fatalf("%s: unexpected response %d %q", path, status, body)
+		}
+	}
+	if status, body := get(t, server.URL+"/main.wasm"); status != http.StatusOK || body != "wasm" {
+		t.Fatalf("unexpected response %d %q", status, body)
+	}
+	if status, _ := get(t, server.URL+"/missing.js"); status != http.StatusNotFound {
+		t.Fatalf("expected 404 for a missing asset, got %d", status)
+	}
+}
+
+func TestDevServerWebsocket(t *testing.T) {
+	var dev = newDevServer(t.TempDir())
+	dev.Built("main.go:1: syntax error", false)
+	var server = httptest.NewServer(dev)
+	defer server.Close()
+
+	var conn, err = net.Dial("tcp", strings.TrimPrefix(server.URL, "http://"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	io.WriteString(conn, "GET /__jsext/ws HTTP/1.1\r\nHost: localhost\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n"+
+		"Sec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\nSec-WebSocket-Version: 13\r\n\r\n")
+	var r = bufio.NewReader(conn)
+	resp, err := http.ReadResponse(r, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols || resp.Header.Get("Sec-WebSocket-Accept") != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+		t.Fatalf("unexpected handshake %d %v", resp.StatusCode, resp.Header)
+	}
+	var ws = &wsConn{conn: conn, rw: bufio.NewReadWriter(r, bufio.NewWriter(conn))}
+	op, payload, err := ws.readFrame()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if op != opText || string(payload) != `{"type":"error","output":"main.go:1: syntax error"}` {
+		t.Fatalf("unexpected message %d %q", op, payload)
+	}
+}
@@ -0,0 +1,61 @@
+// Command jsext scaffolds projects and generates code for the jsext framework.
+//
+//	jsext new [-module name] [-tinygo] <dir>
+//	jsext route [-path /path] [-dir routes] <name>
+//	jsext component [-dir components] <Name>
+//	jsext form [-fields Name:string,Age:int] [-dir forms] <Name>
+//	jsext bind [-type A,B] [-o file_gen.go] <file.go>
+//	jsext dev [-addr localhost:8080] [-dir static] [-tinygo] [-tags devtools] [package]
+//
+// The dev command rebuilds the application when a Go file changes, and serves the static directory.
+// Connected browsers reload after a build, compile errors are shown in the page.
+//
+// The bind command generates struct bindings which convert structs to and from form data and JSON maps
+// without reflection, for TinyGo builds where the reflection based forms and paginators are unavailable.
+// Use it with go generate:
+//
+//	//go:generate go run github.com/Nigel2392/jsext-framework/cmd/jsext bind $GOFILE
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+var commands = map[string]func(args []string) error{
+	"new":       runNew,
+	"route":     runRoute,
+	"component": runComponent,
+	"form":      runForm,
+	"bind":      runBind,
+	"dev":       runDev,
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, `usage: jsext <command> [arguments]
+
+commands:
+	new        create a new project
+	route      generate a route handler
+	component  generate a component
+	form       generate a form struct, its binding and form constructor
+	bind       generate struct bindings for TinyGo builds
+	dev        serve the application and rebuild it on changes
+
+run "jsext <command> -h" for the arguments of a command.`)
+	os.Exit(2)
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+	var run, ok = commands[os.Args[1]]
+	if !ok {
+		usage()
+	}
+	if err := run(os.Args[2:]); err != nil {
+		fmt.Fprintln(os.Stderr, "jsext:", err)
+		os.Exit(1)
+	}
+}
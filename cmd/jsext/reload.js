@@ -0,0 +1,51 @@
+// Live reload client of the jsext dev server.
+(function () {
+	const overlayID = "jsext-dev-overlay";
+	let connected = false;
+
+	function showError(output) {
+		let overlay = document.getElementById(overlayID);
+		if (!overlay) {
+			overlay = document.createElement("div");
+			overlay.id = overlayID;
+			overlay.style.cssText = "position:fixed;inset:0;z-index:2147483647;overflow:auto;padding:2rem;" +
+				"background:rgba(20,20,20,0.95);color:#ff6b6b;font:14px/1.5 monospace;";
+			const title = document.createElement("h2");
+			title.textContent = "Build failed";
+			title.style.cssText = "margin:0 0 1rem;color:#fff;font-family:sans-serif;";
+			const pre = document.createElement("pre");
+			pre.style.cssText = "margin:0;white-space:pre-wrap;";
+			overlay.append(title, pre);
+			document.body.appendChild(overlay);
+		}
+		overlay.querySelector("pre").textContent = output;
+	}
+
+	function connect() {
+		const protocol = location.protocol === "https:" ? "wss://" : "ws://";
+		const ws = new WebSocket(protocol + location.host + "/__jsext/ws");
+		ws.onopen = function () {
+			// The server restarted while we were disconnected, the build may have changed.
+			if (connected) {
+				location.reload();
+			}
+			connected = true;
+		};
+		ws.onmessage = function (event) {
+			const message = JSON.parse(event.data);
+			switch (message.type) {
+			case "reload":
+				location.reload();
+				break;
+			case "error":
+				showError(message.output);
+				break;
+			}
+		};
+		ws.onclose = function () {
+			setTimeout(connect, 1000);
+		};
+	}
+
+	connect();
+})();
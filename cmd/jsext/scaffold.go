@@ -0,0 +1,237 @@
+package main
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"flag"
+	"fmt"
+	"go/format"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+//go:embed templates/*.tmpl
+var templateFS embed.FS
+
+var templates = template.Must(template.ParseFS(templateFS, "templates/*.tmpl"))
+
+// Execute a template and write it to the file, Go files are formatted.
+// Existing files are not overwritten.
+func writeTemplate(file, name string, data any) error {
+	if _, err := os.Stat(file); err == nil {
+		return fmt.Errorf("%s already exists", file)
+	}
+	var b bytes.Buffer
+	if err := templates.ExecuteTemplate(&b, name, data); err != nil {
+		return err
+	}
+	var content = b.Bytes()
+	if strings.HasSuffix(file, ".go") {
+		var err error
+		if content, err = format.Source(content); err != nil {
+			return fmt.Errorf("formatting %s: %w", file, err)
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return err
+	}
+	fmt.Println("writing", file)
+	return os.WriteFile(file, content, 0644)
+}
+
+func parseArgs(flags *flag.FlagSet, args []string, usage string) string {
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: jsext "+usage)
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		flags.Usage()
+		os.Exit(2)
+	}
+	return flags.Arg(0)
+}
+
+// Exported Go name of a name like "user-profile".
+func exportedName(name string) string {
+	var b strings.Builder
+	var upper = true
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// File name of a Go name, "UserProfile" becomes "user_profile".
+func fileName(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		if unicode.IsUpper(r) && i > 0 {
+			b.WriteByte('_')
+		}
+		if r == '-' {
+			r = '_'
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
+
+func runNew(args []string) error {
+	var flags = flag.NewFlagSet("new", flag.ExitOnError)
+	var module = flags.String("module", "", "module path, defaults to the name of the directory")
+	var tinygo = flags.Bool("tinygo", false, "build with TinyGo, copies the wasm_exec.js of TinyGo")
+	var dir = parseArgs(flags, args, "new [-module name] [-tinygo] <dir>")
+	if *module == "" {
+		*module = filepath.Base(dir)
+	}
+	var data = struct {
+		Module, Name string
+		TinyGo       bool
+	}{*module, filepath.Base(dir), *tinygo}
+	var files = [][2]string{
+		{"go.mod", "go.mod.tmpl"},
+		{"main.go", "main.go.tmpl"},
+		{"README.md", "readme.tmpl"},
+		{"routes/routes.go", "routes.go.tmpl"},
+		{"static/index.html", "index.html.tmpl"},
+	}
+	for _, f := range files {
+		if err := writeTemplate(filepath.Join(dir, f[0]), f[1], data); err != nil {
+			return err
+		}
+	}
+	var home = routeData{Package: "routes", Name: "home", Func: "Home", Title: "Home", Path: "/"}
+	if err := writeTemplate(filepath.Join(dir, "routes", "home.go"), "route.go.tmpl", home); err != nil {
+		return err
+	}
+	if err := copyWasmExec(filepath.Join(dir, "static", "wasm_exec.js"), *tinygo); err != nil {
+		fmt.Fprintln(os.Stderr, "jsext: copy wasm_exec.js to the static directory manually:", err)
+	}
+	fmt.Printf("\ncreated %s, run \"go get github.com/Nigel2392/jsext-framework && go mod tidy\" in it to add the dependencies.\n", dir)
+	return nil
+}
+
+// Copy the wasm_exec.js of the Go or TinyGo installation.
+func copyWasmExec(dst string, tinygo bool) error {
+	var candidates []string
+	if tinygo {
+		var root, err = exec.Command("tinygo", "env", "TINYGOROOT").Output()
+		if err != nil {
+			return err
+		}
+		candidates = append(candidates, filepath.Join(strings.TrimSpace(string(root)), "targets", "wasm_exec.js"))
+	} else {
+		var root, err = exec.Command("go", "env", "GOROOT").Output()
+		if err != nil {
+			return err
+		}
+		var goroot = strings.TrimSpace(string(root))
+		candidates = append(candidates,
+			filepath.Join(goroot, "lib", "wasm", "wasm_exec.js"),
+			filepath.Join(goroot, "misc", "wasm", "wasm_exec.js"),
+		)
+	}
+	for _, src := range candidates {
+		var b, err = os.ReadFile(src)
+		if err != nil {
+			continue
+		}
+		fmt.Println("writing", dst)
+		return os.WriteFile(dst, b, 0644)
+	}
+	return errors.New("wasm_exec.js not found in " + strings.Join(candidates, ", "))
+}
+
+type routeData struct {
+	Package, Name, Func, Title, Path string
+}
+
+func runRoute(args []string) error {
+	var flags = flag.NewFlagSet("route", flag.ExitOnError)
+	var path = flags.String("path", "", "path of the route, defaults to /<name>")
+	var dir = flags.String("dir", "routes", "directory of the routes package")
+	var name = parseArgs(flags, args, "route [-path /path] [-dir routes] <name>")
+	if *path == "" {
+		*path = "/" + strings.ToLower(name)
+	}
+	var fn = exportedName(name)
+	var data = routeData{
+		Package: filepath.Base(*dir),
+		Name:    strings.ToLower(name),
+		Func:    fn,
+		Title:   fn,
+		Path:    *path,
+	}
+	if err := writeTemplate(filepath.Join(*dir, fileName(fn)+".go"), "route.go.tmpl", data); err != nil {
+		return err
+	}
+	fmt.Printf("\nregister the route:\n\n\ta.Register(%q, %q, %s)\n", data.Name, data.Path, fn)
+	return nil
+}
+
+func runComponent(args []string) error {
+	var flags = flag.NewFlagSet("component", flag.ExitOnError)
+	var dir = flags.String("dir", "components", "directory of the components package")
+	var name = exportedName(parseArgs(flags, args, "component [-dir components] <Name>"))
+	var data = struct {
+		Package, Name, Class string
+	}{filepath.Base(*dir), name, strings.ReplaceAll(fileName(name), "_", "-")}
+	return writeTemplate(filepath.Join(*dir, fileName(name)+".go"), "component.go.tmpl", data)
+}
+
+type formField struct {
+	Name, Type, JSON string
+}
+
+func runForm(args []string) error {
+	var flags = flag.NewFlagSet("form", flag.ExitOnError)
+	var fields = flags.String("fields", "Name:string", "comma separated fields as Name:type")
+	var dir = flags.String("dir", "forms", "directory of the forms package")
+	var name = exportedName(parseArgs(flags, args, "form [-fields Name:string,Age:int] [-dir forms] <Name>"))
+	var base = fileName(name)
+	var data = struct {
+		Package, Name, File, Gen string
+		Time                     bool
+		Fields                   []formField
+	}{
+		Package: filepath.Base(*dir),
+		Name:    name,
+		File:    base + ".go",
+		Gen:     base + "_gen.go",
+	}
+	for _, field := range strings.Split(*fields, ",") {
+		var parts = strings.SplitN(strings.TrimSpace(field), ":", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return fmt.Errorf("invalid field %q, expected Name:type", field)
+		}
+		var f = formField{Name: exportedName(parts[0]), Type: parts[1]}
+		f.JSON = fileName(f.Name)
+		if kind, _ := fieldKindOf(f.Type); kind == "" || kind == "struct" {
+			return fmt.Errorf("unsupported type %s of field %s", f.Type, f.Name)
+		}
+		data.Time = data.Time || f.Type == "time.Time"
+		data.Fields = append(data.Fields, f)
+	}
+	var file = filepath.Join(*dir, data.File)
+	if err := writeTemplate(file, "form.go.tmpl", data); err != nil {
+		return err
+	}
+	if err := writeTemplate(filepath.Join(*dir, base+"_form.go"), "form_wasm.go.tmpl", data); err != nil {
+		return err
+	}
+	return runBind([]string{"-type", name, file})
+}
@@ -7,6 +7,7 @@ import (
 	"strconv"
 
 	"github.com/Nigel2392/jsext"
+	"github.com/Nigel2392/jsext-framework/theme"
 	"github.com/Nigel2392/jsext/elements"
 )
 
@@ -41,10 +42,10 @@ func (c *Options) SetDefaults() {
 		c.Height = "100%"
 	}
 	if c.Background == "" {
-		c.Background = "#ffffff"
+		c.Background = theme.Surface
 	}
 	if c.ControlsColor == "" {
-		c.ControlsColor = "#000000"
+		c.ControlsColor = theme.Text
 	}
 	if c.Prefix == "" {
 		c.Prefix = "jsext-carousel-"
@@ -7,6 +7,7 @@ import (
 	"strconv"
 
 	"github.com/Nigel2392/jsext"
+	"github.com/Nigel2392/jsext-framework/theme"
 	"github.com/Nigel2392/jsext/elements"
 )
 
@@ -29,10 +30,10 @@ type DropdownOptions struct {
 
 func (d *DropdownOptions) SetDefaults() {
 	if d.Background == "" {
-		d.Background = "#ffffff"
+		d.Background = theme.Surface
 	}
 	if d.Color == "" {
-		d.Color = "#333333"
+		d.Color = theme.Text
 	}
 	if d.BorderWidth == "" {
 		d.BorderWidth = "0px"
@@ -232,7 +233,7 @@ func DropdownElement(opts DropdownOptions, absolutePosition bool) *elements.Elem
 			background-color: ` + opts.Background + `;
 			border: none;
 			overflow: hidden;
-			box-shadow: 0px 8px 16px 0px rgba(0,0,0,0.2);
+			box-shadow: 0px 8px 16px 0px ` + theme.Shadow + `;
 			z-index: 1;
 			transition: height 0.5s, border 0.5s;
 		}
@@ -250,7 +251,7 @@ func DropdownElement(opts DropdownOptions, absolutePosition bool) *elements.Elem
 		.` + opts.Prefix + `dropdown-item:not(:last-child) {
 			border-bottom: ` + opts.BorderWidth + ` solid ` + opts.Color + `;
 		}
-		.` + opts.Prefix + `dropdown a:hover {background-color: #f1f1f1}
+		.` + opts.Prefix + `dropdown a:hover {background-color: ` + theme.SurfaceHover + `}
 		.` + opts.Prefix + `show {height: ` + opts.Height + `; border:` + opts.BorderWidth + ` solid ` + opts.Color + `;}
 		.` + opts.Prefix + `show > * {
 			opacity: 1;
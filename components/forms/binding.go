@@ -0,0 +1,51 @@
+//go:build js && wasm
+// +build js,wasm
+
+package forms
+
+import (
+	"strings"
+
+	"github.com/Nigel2392/jsext"
+	"github.com/Nigel2392/jsext/elements"
+)
+
+// Binding of a struct to form data, generated with the jsext bind command.
+// Bindings do not use reflection, so they can be used in TinyGo builds.
+type Binding interface {
+	// Name, value and input type of the fields.
+	FormFields() [][3]string
+	// Set the fields from submitted form data.
+	SetFormData(data map[string]string) error
+}
+
+// Render a binding into a form, like StructToForm.
+func BindingToForm(b Binding, labelClass, inputClass, action, method string) *Form {
+	var form = elements.Form(action, method)
+	for _, item := range b.FormFields() {
+		var name = item[0]
+		var label = strings.ReplaceAll(name, Delimiter, " ")
+		label = strings.ReplaceAll(label, "_", " ")
+		var elemLabel = elements.Label(label, name)
+		var elemInput = elements.Input(item[2], name, label).AttrValue(item[1])
+		if inputClass != "" {
+			elemInput.AttrClass(inputClass)
+		}
+		if labelClass != "" {
+			elemLabel.AttrClass(labelClass)
+		}
+		form.Append(elemLabel, elemInput)
+	}
+	return &Form{Inner: form, Validators: make(map[string]func(string) error)}
+}
+
+// Eventlistener for when the form is submitted, data is set on the binding
+func (f *Form) OnSubmitToBinding(b Binding, fn func(b Binding, elements []jsext.Element)) {
+	f.OnSubmit(func(data map[string]string, elements []jsext.Element) {
+		var err = b.SetFormData(data)
+		if err != nil {
+			panic(err)
+		}
+		fn(b, elements)
+	})
+}
@@ -4,19 +4,19 @@
 package loaders
 
 import (
-	"github.com/Nigel2392/jsext-framework/helpers/csshelpers"
+	"github.com/Nigel2392/jsext-framework/theme"
 	"github.com/Nigel2392/jsext/elements"
 )
 
 var (
-	COLOR_MAIN       = csshelpers.COLOR_MAIN
-	COLOR_ONE        = csshelpers.COLOR_ONE
-	COLOR_TWO        = csshelpers.COLOR_TWO
-	COLOR_THREE      = csshelpers.COLOR_THREE
-	COLOR_FOUR       = csshelpers.COLOR_FOUR
-	COLOR_FIVE       = csshelpers.COLOR_FIVE
+	COLOR_MAIN       = theme.OnOverlay
+	COLOR_ONE        = theme.Primary
+	COLOR_TWO        = theme.Warning
+	COLOR_THREE      = theme.Success
+	COLOR_FOUR       = theme.Info
+	COLOR_FIVE       = theme.PrimaryHover
 	LOADING_TEXT     = "loading"
-	BACKGROUND_COLOR = csshelpers.BACKGROUND_COLOR
+	BACKGROUND_COLOR = theme.Overlay
 )
 
 // Function for use in the loader.
@@ -8,6 +8,7 @@ import (
 
 	"github.com/Nigel2392/jsext"
 	"github.com/Nigel2392/jsext-framework/router"
+	"github.com/Nigel2392/jsext-framework/theme"
 	"github.com/Nigel2392/jsext/elements"
 )
 
@@ -78,22 +79,22 @@ func (m *MenuOptions) setDefaults() {
 		}
 	}
 	if m.CSSMap[OpenBtnColor] == "" {
-		m.CSSMap[OpenBtnColor] = "white"
+		m.CSSMap[OpenBtnColor] = theme.OnOverlay
 	}
 	if m.CSSMap[OverlayBackgroundColor] == "" {
-		m.CSSMap[OverlayBackgroundColor] = "rgba(0,0,0,0.5)"
+		m.CSSMap[OverlayBackgroundColor] = theme.Overlay
 	}
 	if m.CSSMap[TransitionDuration] == "" {
 		m.CSSMap[TransitionDuration] = "0.5s"
 	}
 	if m.CSSMap[OpenBtnBg] == "" {
-		m.CSSMap[OpenBtnBg] = "rgba(0,0,0,0.5)"
+		m.CSSMap[OpenBtnBg] = theme.Overlay
 	}
 	if m.CSSMap[CloseBtnBg] == "" {
-		m.CSSMap[CloseBtnBg] = "red"
+		m.CSSMap[CloseBtnBg] = theme.Danger
 	}
 	if m.CSSMap[CloseBtnColor] == "" {
-		m.CSSMap[CloseBtnColor] = "white"
+		m.CSSMap[CloseBtnColor] = theme.OnOverlay
 	}
 	if m.CSSMap[ControlBtnSize] == "" {
 		m.CSSMap[ControlBtnSize] = "50px"
@@ -108,16 +109,16 @@ func (m *MenuOptions) setDefaults() {
 		m.CSSMap[MenuItemCSSBlock] = ``
 	}
 	if m.CSSMap[TextColor] == "" {
-		m.CSSMap[TextColor] = "white"
+		m.CSSMap[TextColor] = theme.OnOverlay
 	}
 	if m.CSSMap[TextColorActive] == "" {
-		m.CSSMap[TextColorActive] = "white"
+		m.CSSMap[TextColorActive] = theme.OnAccent
 	}
 	if m.CSSMap[BackgroundColor] == "" {
-		m.CSSMap[BackgroundColor] = "rgba(0,0,0,0.5)"
+		m.CSSMap[BackgroundColor] = theme.Overlay
 	}
 	if m.CSSMap[BackgroundActive] == "" {
-		m.CSSMap[BackgroundActive] = "#9200ff"
+		m.CSSMap[BackgroundActive] = theme.Accent
 	}
 	if m.CSSMap[ButtonWidth] == "" {
 		m.CSSMap[ButtonWidth] = "200px"
@@ -7,12 +7,16 @@ import (
 	"errors"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/Nigel2392/jsext"
+	"github.com/Nigel2392/jsext-framework/app/shortcuts"
 	"github.com/Nigel2392/jsext-framework/helpers"
 	"github.com/Nigel2392/jsext-framework/helpers/convert"
 	"github.com/Nigel2392/jsext-framework/helpers/csshelpers"
+	"github.com/Nigel2392/jsext-framework/i18n"
+	"github.com/Nigel2392/jsext-framework/theme"
 	"github.com/Nigel2392/jsext/elements"
 )
 
@@ -48,15 +52,7 @@ func SearchBar(classPrefix, foregroundHex, background, text string) []*elements.
 	var searchContainer = elements.Div().AttrClass(classPrefix + "search-container")
 	var searchbar = searchContainer.Input("text", "search", text).AttrClass(classPrefix + "searchbar")
 	var searchBarSubmit = searchContainer.Button(text).AttrClass(classPrefix + "searchbar-submit")
-	var borderColor, err = csshelpers.Hex(foregroundHex)
-	if err != nil {
-		panic(err)
-	}
-
-	var b_A, b_G, b_B = borderColor.RGB255()
-	var b_A_str = strconv.Itoa(int(b_A))
-	var b_G_str = strconv.Itoa(int(b_G))
-	var b_B_str = strconv.Itoa(int(b_B))
+	var borderColor = mixColor(foregroundHex, "rgba(%d, %d, %d, 0.5)", "50%")
 
 	jsext.StyleBlock(classPrefix, `
 		.`+classPrefix+`search-container {
@@ -72,7 +68,7 @@ func SearchBar(classPrefix, foregroundHex, background, text string) []*elements.
 			padding: 0 5px;
 			background-color: `+background+`;
 			color: `+foregroundHex+`;
-			border: 1px solid rgba(`+b_A_str+`, `+b_G_str+`, `+b_B_str+`, 0.5);
+			border: 1px solid `+borderColor+`;
 			border-radius: 5px;
 			font-size: 20px;
 		}
@@ -87,7 +83,7 @@ func SearchBar(classPrefix, foregroundHex, background, text string) []*elements.
 			padding: 0 5px;
 			background-color: `+background+`;
 			color: `+foregroundHex+`;
-			border: 1px solid rgba(`+b_A_str+`, `+b_G_str+`, `+b_B_str+`, 0.5);
+			border: 1px solid `+borderColor+`;
 			border-radius: 5px;
 			cursor: pointer;
 			font-size: 20px;
@@ -153,7 +149,7 @@ func NewTimeCounter(elem *elements.Element) *TimeCounter {
 		time:    time.Now(),
 		element: elem,
 		formatFunc: func(t *convert.TimeTracker) string {
-			return t.Format("%YR years, %MO months, %DD days, %HH hours, %MM minutes, %SS seconds")
+			return t.Translate(nil)
 		},
 	}
 }
@@ -238,11 +234,27 @@ type RoadMapItem struct {
 	EndDate      string
 }
 
+// Translations for the roadmap.
+// Empty fields are translated with the default translator when rendering.
 type Translations struct {
 	To      string
 	Present string
 }
 
+func (t Translations) to() string {
+	if t.To == "" {
+		return i18n.T("roadmap.to")
+	}
+	return t.To
+}
+
+func (t Translations) present() string {
+	if t.Present == "" {
+		return i18n.T("roadmap.present")
+	}
+	return t.Present
+}
+
 type RoadMapOptions struct {
 	Background          string
 	ItemBackground      string
@@ -265,26 +277,26 @@ type RoadMapOptions struct {
 
 func (r *RoadMapOptions) defaultOverrides() {
 	if r.Background == "" {
-		r.Background = "#ffffff"
+		r.Background = theme.Background
 	}
 	if r.ItemBackground == "" {
-		r.ItemBackground = `rgb(85,34,195);
-		background: linear-gradient(0deg, rgba(85,34,195,0.40940126050420167) 0%, rgba(113,49,172,1) 100%)`
+		r.ItemBackground = theme.Accent + `;
+		background: linear-gradient(0deg, color-mix(in srgb, ` + theme.Accent + ` 40%, transparent) 0%, ` + theme.Accent + ` 100%)`
 	}
 	if r.Color == "" {
-		r.Color = "#ffffff"
+		r.Color = theme.OnAccent
 	}
 	if r.TitleColor == "" {
-		r.TitleColor = "#ffffff"
+		r.TitleColor = theme.OnAccent
 	}
 	if r.TagColor == "" {
-		r.TagColor = "#ffffff"
+		r.TagColor = theme.OnPrimary
 	}
 	if r.TagBackgroundColors == nil {
-		r.TagBackgroundColors = []string{"#ffffff"}
+		r.TagBackgroundColors = []string{theme.Primary}
 	}
 	if r.DivisorColor == "" {
-		r.DivisorColor = "#333333"
+		r.DivisorColor = theme.Text
 	}
 	if r.DivisorWidth == "" {
 		r.DivisorWidth = "1px"
@@ -302,13 +314,7 @@ func (r *RoadMapOptions) defaultOverrides() {
 		r.CardBorderWidth = "1px"
 	}
 	if r.CardBorderColor == "" {
-		r.CardBorderColor = "#333333"
-	}
-	if r.Translations.To == "" {
-		r.Translations.To = "to"
-	}
-	if r.Translations.Present == "" {
-		r.Translations.Present = "Present"
+		r.CardBorderColor = theme.Text
 	}
 	if r.FontScale == 0 {
 		r.FontScale = 1
@@ -371,7 +377,7 @@ func roadMapStyleOne(roadMap *RoadMapOptions) *elements.Element {
 		var ct = 0
 		var color string
 		for _, tag := range item.Tags {
-			color, ct = helpers.GetColor(roadMap.TagBackgroundColors, ct, "#5555ff")
+			color, ct = helpers.GetColor(roadMap.TagBackgroundColors, ct, theme.Primary)
 			paragraph.Span(tag).AttrClass(roadMap.classPrefix + "content-tag-item").AttrStyle("background-color:" + color)
 		}
 	}
@@ -553,11 +559,11 @@ func roadMapStyleTwo(roadMap *RoadMapOptions) *elements.Element {
 
 		if item.StartDate != "" || item.EndDate != "" {
 			if item.StartDate != "" && item.EndDate != "" {
-				card_footer.Div(item.StartDate + " " + roadMap.Translations.To + " " + item.EndDate)
+				card_footer.Div(item.StartDate + " " + roadMap.Translations.to() + " " + item.EndDate)
 			} else if item.StartDate != "" {
-				card_footer.Div(item.StartDate + " - " + roadMap.Translations.Present)
+				card_footer.Div(item.StartDate + " - " + roadMap.Translations.present())
 			} else {
-				card_footer.Div(roadMap.Translations.Present)
+				card_footer.Div(roadMap.Translations.present())
 			}
 		}
 
@@ -657,21 +663,56 @@ func roadMapStyleTwo(roadMap *RoadMapOptions) *elements.Element {
 	return timeline
 }
 
+// Shortcut scope entered while a modal is open.
+const ModalScope = "modal"
+
+// Shortcut manager of which the shortcuts are suspended while a modal is open.
+// Set to the shortcut manager of the application.
+var Shortcuts *shortcuts.Manager
+
+// Functions to leave the shortcut scope of the open modals.
+var (
+	modalScopesMu sync.Mutex
+	modalScopes   = make(map[*Modal]func())
+)
+
 type Modal elements.Element
 
 func (m *Modal) e() *elements.Element {
 	return (*elements.Element)(m)
 }
 
+// Show the modal, the shortcuts of Shortcuts are suspended until it is hidden.
 func (m *Modal) Show() {
 	if !m.e().Value().Truthy() {
 		m.Create()
 	}
 	m.e().AttrStyle("display:flex")
+	m.enterScope()
 }
 
 func (m *Modal) Hide() {
 	m.e().AttrStyle("display:none")
+	m.leaveScope()
+}
+
+func (m *Modal) enterScope() {
+	modalScopesMu.Lock()
+	defer modalScopesMu.Unlock()
+	if Shortcuts == nil || modalScopes[m] != nil {
+		return
+	}
+	modalScopes[m] = Shortcuts.Enter(ModalScope, true)
+}
+
+func (m *Modal) leaveScope() {
+	modalScopesMu.Lock()
+	var leave = modalScopes[m]
+	delete(modalScopes, m)
+	modalScopesMu.Unlock()
+	if leave != nil {
+		leave()
+	}
 }
 
 func (m *Modal) Render() jsext.Element {
@@ -733,6 +774,7 @@ func (m *Modal) Delete() {
 	if m.e().Value().Truthy() {
 		m.e().Value().Remove()
 	}
+	m.leaveScope()
 }
 
 type ModalOptions struct {
@@ -756,16 +798,16 @@ func (opts *ModalOptions) SetDefaults() {
 		opts.ClassPrefix = "jsext-modal-"
 	}
 	if opts.Background == "" {
-		opts.Background = "rgba(0,0,0,0.5)"
+		opts.Background = theme.Overlay
 	}
 	if opts.ModalBackground == "" {
-		opts.ModalBackground = "#fff"
+		opts.ModalBackground = theme.Surface
 	}
 	if opts.BorderRadius == "" {
 		opts.BorderRadius = "5px"
 	}
 	if opts.Border == "" {
-		opts.Border = "1px solid #ccc"
+		opts.Border = "1px solid " + theme.Border
 	}
 	if opts.Width == "" {
 		opts.Width = "50%"
@@ -1311,7 +1353,7 @@ func (h *HR) setDefaults() {
 		h.Height = "1px"
 	}
 	if h.BackCol == "" {
-		h.BackCol = "#000000"
+		h.BackCol = theme.Text
 	}
 	if h.BackColFormat == "" {
 		h.BackColFormat = "rgba(%d, %d, %d, 1)"
@@ -1320,7 +1362,7 @@ func (h *HR) setDefaults() {
 		h.FadeColFormat = "rgba(%d, %d, %d, 0.1)"
 	}
 	if h.FadeCol == "" {
-		h.FadeCol = "#ffffff"
+		h.FadeCol = theme.Background
 	}
 	if h.MarginTopBottom == "" {
 		h.MarginTopBottom = "0"
@@ -1333,10 +1375,19 @@ func (h *HR) setDefaults() {
 	}
 }
 
+// Format a hex color with the format, other colors such as the custom properties
+// of the theme are mixed with transparent to the opacity instead.
+func mixColor(color, format, opacity string) string {
+	if _, err := csshelpers.Hex(color); err == nil {
+		return csshelpers.FormatRGBA(color, format)
+	}
+	return "color-mix(in srgb, " + color + " " + opacity + ", transparent)"
+}
+
 func FancyHR(opts *HR) *elements.Element {
 	opts.setDefaults()
-	var mainColor = csshelpers.FormatRGBA(opts.BackCol, opts.BackColFormat)
-	var fadeColor = csshelpers.FormatRGBA(opts.FadeCol, opts.FadeColFormat)
+	var mainColor = mixColor(opts.BackCol, opts.BackColFormat, "100%")
+	var fadeColor = mixColor(opts.FadeCol, opts.FadeColFormat, "10%")
 	var hash = helpers.FNVHashString(opts.Width + opts.Height + opts.BackCol + opts.FadeCol + opts.MarginTopBottom + opts.FadeDir)
 	var hr = elements.NewElement("hr")
 	hr.AttrClass("fancy-hr" + hash)
@@ -9,6 +9,7 @@ import (
 
 	"github.com/Nigel2392/jsext-framework/components/misc"
 	"github.com/Nigel2392/jsext-framework/router"
+	"github.com/Nigel2392/jsext-framework/theme"
 	"github.com/Nigel2392/jsext/elements"
 )
 
@@ -110,8 +111,8 @@ func plainNav(foreground, background string, middle int) []*elements.Element {
 }
 
 // Variables for styling the Official navbar's colors
-var OfficialForeground = "#ffffff"
-var OfficialBackground = "#142836"
+var OfficialForeground = theme.OnPrimary
+var OfficialBackground = theme.Primary
 
 // The navbar element.
 func Official(logo *Logo, urls *router.URLs) *elements.Element {
@@ -131,13 +132,13 @@ func Official(logo *Logo, urls *router.URLs) *elements.Element {
 	return navbarMain
 }
 
-var SearchForeground = "#333333"
-var SearchBackground = "#ffffff"
+var SearchForeground = theme.Text
+var SearchBackground = theme.Surface
 var SearchText = "Search"
 
 // Returns the main navbar element, and the searchbar element, with the submit button in an array.
 func Search(logo *Logo, urls *router.URLs) (*elements.Element, []*elements.Element) {
-	var items = misc.SearchBar("search-", "#333333", "#ffffff", "Search")
+	var items = misc.SearchBar("search-", SearchForeground, SearchBackground, SearchText)
 	var searchBarContainer = items[0]
 	var navbar = Custom(logo, urls, SearchBackground, SearchForeground, searchBarContainer)
 	return navbar, items[1:]
@@ -0,0 +1,449 @@
+//go:build js && wasm
+// +build js,wasm
+
+package toasts
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Nigel2392/jsext"
+	"github.com/Nigel2392/jsext-framework/theme"
+	"github.com/Nigel2392/jsext/elements"
+	"github.com/Nigel2392/jsext/messages"
+)
+
+// Level of a toast, matches the jsext message types.
+type Level string
+
+const (
+	Info    Level = messages.Info
+	Success Level = messages.Success
+	Warning Level = messages.Warning
+	Error   Level = messages.Error
+)
+
+// Position of the toast container on the screen.
+type Position string
+
+const (
+	TopLeft      Position = "top-left"
+	TopCenter    Position = "top-center"
+	TopRight     Position = "top-right"
+	BottomLeft   Position = "bottom-left"
+	BottomCenter Position = "bottom-center"
+	BottomRight  Position = "bottom-right"
+)
+
+// Action button to display on a toast.
+type Action struct {
+	// Text of the button.
+	Text string
+	// Function to call when the button is clicked.
+	OnClick func(t *Toast)
+	// Keep the toast open after the button was clicked.
+	KeepOpen bool
+}
+
+// Options for a single toast.
+type Options struct {
+	// Time before the toast is dismissed automatically.
+	//  - 0 uses the container's timeout.
+	//  - A negative value keeps the toast open until it is dismissed.
+	Timeout time.Duration
+	// Buttons to display on the toast.
+	Actions []Action
+	// Key used for de-duplication.
+	// Defaults to the level, title and body of the toast.
+	Key string
+	// Hide the close button.
+	NoClose bool
+}
+
+// Options for the toast container.
+type ContainerOptions struct {
+	// Where to stack the toasts.
+	Position Position
+	// Maximum amount of toasts visible at once, the rest is queued.
+	MaxVisible int
+	// Default time before a toast is dismissed.
+	Timeout time.Duration
+	// Query selector to append the container to, defaults to the body.
+	AppendTo string
+	// Width of the toasts.
+	Width string
+	// Colors for the different levels.
+	Colors map[Level]string
+	// Text color of the toasts.
+	Color string
+	// Class prefix
+	Prefix string
+	// Z-index of the container.
+	ZIndex int
+}
+
+func (o *ContainerOptions) SetDefaults() {
+	if o.Position == "" {
+		o.Position = TopRight
+	}
+	if o.MaxVisible == 0 {
+		o.MaxVisible = 5
+	}
+	if o.Timeout == 0 {
+		o.Timeout = 5 * time.Second
+	}
+	if o.Width == "" {
+		o.Width = "320px"
+	}
+	if o.Colors == nil {
+		o.Colors = make(map[Level]string)
+	}
+	if o.Colors[Info] == "" {
+		o.Colors[Info] = theme.Info
+	}
+	if o.Colors[Success] == "" {
+		o.Colors[Success] = theme.Success
+	}
+	if o.Colors[Warning] == "" {
+		o.Colors[Warning] = theme.Warning
+	}
+	if o.Colors[Error] == "" {
+		o.Colors[Error] = theme.Danger
+	}
+	if o.Color == "" {
+		o.Color = theme.OnPrimary
+	}
+	if o.Prefix == "" {
+		o.Prefix = "jsext-toast-"
+	}
+	if o.ZIndex == 0 {
+		o.ZIndex = 10000
+	}
+}
+
+// A single toast notification.
+type Toast struct {
+	Level     Level
+	Title     string
+	Body      string
+	Key       string
+	count     int
+	opts      Options
+	element   *elements.Element
+	counter   *elements.Element
+	timer     *time.Timer
+	container *Container
+}
+
+// Amount of times the toast has been pushed.
+func (t *Toast) Count() int {
+	return t.count
+}
+
+// Dismiss the toast, the next queued toast will be shown.
+func (t *Toast) Dismiss() {
+	t.container.dismiss(t)
+}
+
+// Container to display toasts in.
+type Container struct {
+	opts     *ContainerOptions
+	element  *elements.Element
+	rendered bool
+	visible  []*Toast
+	queue    []*Toast
+	mu       *sync.Mutex
+}
+
+// Initialize a new toast container.
+func NewContainer(opts *ContainerOptions) *Container {
+	if opts == nil {
+		opts = &ContainerOptions{}
+	}
+	opts.SetDefaults()
+	var c = &Container{
+		opts:    opts,
+		visible: make([]*Toast, 0),
+		queue:   make([]*Toast, 0),
+		mu:      &sync.Mutex{},
+	}
+	c.element = elements.Div().AttrClass(opts.Prefix+"container", opts.Prefix+string(opts.Position))
+	c.element.StyleBlock(c.css())
+	return c
+}
+
+// Render the container.
+func (c *Container) Render() jsext.Element {
+	c.rendered = true
+	return c.element.Render()
+}
+
+// Push a new toast to the container.
+// If a toast with the same key is already visible or queued,
+// the count of the existing toast is increased and its timer is reset.
+func (c *Container) Push(level Level, title, body string, opts ...*Options) *Toast {
+	var o Options
+	if len(opts) > 0 && opts[0] != nil {
+		o = *opts[0]
+	}
+	if o.Key == "" {
+		o.Key = string(level) + ":" + title + ":" + body
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if t := c.find(o.Key); t != nil {
+		t.count++
+		if t.counter != nil {
+			t.counter.InnerText("x" + strconv.Itoa(t.count))
+			t.counter.Value().Get("style").Set("display", "inline-block")
+		}
+		c.startTimer(t)
+		return t
+	}
+
+	var t = &Toast{
+		Level:     level,
+		Title:     title,
+		Body:      body,
+		Key:       o.Key,
+		count:     1,
+		opts:      o,
+		container: c,
+	}
+	if len(c.visible) >= c.opts.MaxVisible {
+		c.queue = append(c.queue, t)
+		return t
+	}
+	c.show(t)
+	return t
+}
+
+// Dismiss all visible and queued toasts.
+func (c *Container) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.queue = c.queue[:0]
+	for _, t := range c.visible {
+		c.remove(t)
+	}
+	c.visible = c.visible[:0]
+}
+
+// Amount of toasts visible and queued.
+func (c *Container) Len() (visible int, queued int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.visible), len(c.queue)
+}
+
+func (c *Container) find(key string) *Toast {
+	for _, t := range c.visible {
+		if t.Key == key {
+			return t
+		}
+	}
+	for _, t := range c.queue {
+		if t.Key == key {
+			return t
+		}
+	}
+	return nil
+}
+
+func (c *Container) dismiss(t *Toast) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i, v := range c.queue {
+		if v == t {
+			c.queue = append(c.queue[:i], c.queue[i+1:]...)
+			return
+		}
+	}
+	for i, v := range c.visible {
+		if v == t {
+			c.visible = append(c.visible[:i], c.visible[i+1:]...)
+			c.remove(t)
+			break
+		}
+	}
+	for len(c.queue) > 0 && len(c.visible) < c.opts.MaxVisible {
+		var next = c.queue[0]
+		c.queue = c.queue[1:]
+		c.show(next)
+	}
+}
+
+// Create the toast element and append it to the container.
+func (c *Container) show(t *Toast) {
+	if !c.rendered {
+		if c.opts.AppendTo != "" {
+			c.element.RenderTo(c.opts.AppendTo)
+		} else {
+			jsext.Body.AppendChild(c.element.Render())
+		}
+		c.rendered = true
+	}
+	var prefix = c.opts.Prefix
+	t.element = elements.Div().AttrClass(prefix+"toast", prefix+string(t.Level))
+	t.element.AttrStyle("background-color:" + c.opts.Colors[t.Level])
+
+	var header = t.element.Div().AttrClass(prefix + "header")
+	if t.Title != "" {
+		header.Strong().InnerText(t.Title).AttrClass(prefix + "title")
+	}
+	t.counter = header.Span("x" + strconv.Itoa(t.count)).AttrClass(prefix + "count")
+	if t.count < 2 {
+		t.counter.AttrStyle("display:none")
+	}
+	if !t.opts.NoClose {
+		var closeBtn = header.Button("&times;").AttrClass(prefix + "close")
+		closeBtn.AddEventListener("click", func(this jsext.Value, event jsext.Event) {
+			event.PreventDefault()
+			go t.Dismiss()
+		})
+	}
+	if t.Body != "" {
+		t.element.P().InnerText(t.Body).AttrClass(prefix + "body")
+	}
+	if len(t.opts.Actions) > 0 {
+		var actions = t.element.Div().AttrClass(prefix + "actions")
+		for _, action := range t.opts.Actions {
+			var action = action
+			var btn = actions.Button().InnerText(action.Text).AttrClass(prefix + "action")
+			btn.AddEventListener("click", func(this jsext.Value, event jsext.Event) {
+				event.PreventDefault()
+				go func() {
+					if action.OnClick != nil {
+						action.OnClick(t)
+					}
+					if !action.KeepOpen {
+						t.Dismiss()
+					}
+				}()
+			})
+		}
+	}
+
+	c.element.JSExtElement().AppendChild(t.element.Render())
+	c.visible = append(c.visible, t)
+	c.startTimer(t)
+}
+
+// Remove the toast element and stop its timer.
+func (c *Container) remove(t *Toast) {
+	if t.timer != nil {
+		t.timer.Stop()
+		t.timer = nil
+	}
+	if t.element != nil {
+		t.element.Remove()
+		t.element = nil
+	}
+}
+
+// (Re)start the auto-dismiss timer of a visible toast.
+func (c *Container) startTimer(t *Toast) {
+	if t.element == nil {
+		return
+	}
+	if t.timer != nil {
+		t.timer.Stop()
+	}
+	var timeout = t.opts.Timeout
+	if timeout == 0 {
+		timeout = c.opts.Timeout
+	}
+	if timeout < 0 {
+		return
+	}
+	t.timer = time.AfterFunc(timeout, t.Dismiss)
+}
+
+func (c *Container) css() string {
+	var prefix = c.opts.Prefix
+	var vertical = "top: 0;"
+	var direction = "column"
+	switch c.opts.Position {
+	case BottomLeft, BottomCenter, BottomRight:
+		vertical = "bottom: 0;"
+		direction = "column-reverse"
+	}
+	var horizontal = "right: 0;"
+	switch c.opts.Position {
+	case TopLeft, BottomLeft:
+		horizontal = "left: 0;"
+	case TopCenter, BottomCenter:
+		horizontal = "left: 50%; transform: translateX(-50%);"
+	}
+	return `.` + prefix + `container {
+		position: fixed;
+		` + vertical + `
+		` + horizontal + `
+		display: flex;
+		flex-direction: ` + direction + `;
+		gap: 10px;
+		padding: 10px;
+		width: ` + c.opts.Width + `;
+		max-width: 100%;
+		box-sizing: border-box;
+		pointer-events: none;
+		z-index: ` + strconv.Itoa(c.opts.ZIndex) + `;
+	}
+	.` + prefix + `toast {
+		color: ` + c.opts.Color + `;
+		border-radius: 5px;
+		padding: 10px 15px;
+		box-shadow: 0 0 10px ` + theme.Shadow + `;
+		pointer-events: auto;
+		animation: ` + prefix + `fade-in 0.3s ease-in-out;
+	}
+	.` + prefix + `header {
+		display: flex;
+		align-items: center;
+		gap: 5px;
+	}
+	.` + prefix + `title {
+		flex-grow: 1;
+	}
+	.` + prefix + `count {
+		font-size: 0.8em;
+		padding: 0 5px;
+		border-radius: 5px;
+		background-color: rgba(0,0,0,0.2);
+	}
+	.` + prefix + `close {
+		margin-left: auto;
+		background: none;
+		border: none;
+		color: inherit;
+		font-size: 1.2em;
+		cursor: pointer;
+	}
+	.` + prefix + `body {
+		margin: 5px 0 0 0;
+	}
+	.` + prefix + `actions {
+		display: flex;
+		justify-content: flex-end;
+		gap: 5px;
+		margin-top: 5px;
+	}
+	.` + prefix + `action {
+		background: rgba(255,255,255,0.2);
+		border: 1px solid rgba(255,255,255,0.5);
+		border-radius: 5px;
+		color: inherit;
+		padding: 5px 10px;
+		cursor: pointer;
+	}
+	.` + prefix + `action:hover {
+		background: rgba(255,255,255,0.4);
+	}
+	@keyframes ` + prefix + `fade-in {
+		from { opacity: 0; }
+		to { opacity: 1; }
+	}`
+}
@@ -0,0 +1,380 @@
+//go:build js && wasm
+// +build js,wasm
+
+package devtools
+
+import (
+	"encoding/json"
+	"html"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall/js"
+	"time"
+
+	"github.com/Nigel2392/jsext"
+	"github.com/Nigel2392/jsext-framework/theme"
+)
+
+// Prefix of the classes of the panel.
+const PanelPrefix = "jsext-devtools-"
+
+// Tabs of the panel.
+const (
+	TabRoutes     = "routes"
+	TabNavigation = "navigation"
+	TabMiddleware = "middleware"
+	TabData       = "data"
+	TabRequests   = "requests"
+	TabErrors     = "errors"
+)
+
+var panelTabs = []string{TabRoutes, TabNavigation, TabMiddleware, TabData, TabRequests, TabErrors}
+
+// Where the panel gets its information from.
+type Source struct {
+	Recorder *Recorder
+	// Route tree of the application.
+	Routes func() []*Route
+	// Contents of the data store.
+	Data func() map[string]any
+	// Change a value in the data store, called when a value is edited in the panel.
+	SetData func(key string, value any) error
+}
+
+// In-page panel showing the state of the application.
+type Panel struct {
+	src     Source
+	tab     string
+	elem    js.Value
+	onClick js.Func
+	remove  func()
+	message string
+	mu      sync.Mutex
+}
+
+// Initialize a new panel, it is hidden until Show is called.
+func NewPanel(src Source) *Panel {
+	return &Panel{src: src, tab: TabNavigation}
+}
+
+// Check if the panel is shown.
+func (p *Panel) Visible() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.remove != nil
+}
+
+// Show the panel.
+func (p *Panel) Show() {
+	p.mu.Lock()
+	if p.remove != nil {
+		p.mu.Unlock()
+		return
+	}
+	p.elem = jsext.Document.Call("createElement", "div")
+	p.elem.Set("className", PanelPrefix+"panel")
+	p.onClick = js.FuncOf(p.click)
+	p.elem.Call("addEventListener", "click", p.onClick)
+	jsext.Body.Value().Call("appendChild", p.elem)
+	var removeListener = p.src.Recorder.OnChange(func() {
+		// Do not overwrite values which are being edited.
+		if p.currentTab() != TabData {
+			p.render()
+		}
+	})
+	p.remove = func() {
+		removeListener()
+		p.elem.Call("removeEventListener", "click", p.onClick)
+		p.onClick.Release()
+		p.elem.Call("remove")
+	}
+	p.mu.Unlock()
+	p.render()
+}
+
+// Hide the panel.
+func (p *Panel) Hide() {
+	p.mu.Lock()
+	var remove = p.remove
+	p.remove = nil
+	p.mu.Unlock()
+	if remove != nil {
+		remove()
+	}
+}
+
+// Show the panel if it is hidden, hide it otherwise.
+func (p *Panel) Toggle() {
+	if p.Visible() {
+		p.Hide()
+	} else {
+		p.Show()
+	}
+}
+
+// Switch to the tab.
+func (p *Panel) Open(tab string) {
+	p.mu.Lock()
+	p.tab = tab
+	p.message = ""
+	p.mu.Unlock()
+	p.Show()
+	p.render()
+}
+
+func (p *Panel) currentTab() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.tab
+}
+
+func (p *Panel) click(this js.Value, args []js.Value) interface{} {
+	var target = args[0].Get("target")
+	var dataset = target.Get("dataset")
+	switch action := dataset.Get("action"); {
+	case action.IsUndefined():
+		return nil
+	case action.String() == "tab":
+		p.Open(dataset.Get("tab").String())
+	case action.String() == "close":
+		// Hiding releases this callback, do it after it has returned.
+		go p.Hide()
+	case action.String() == "clear":
+		p.src.Recorder.Clear()
+	case action.String() == "refresh":
+		p.render()
+	case action.String() == "save":
+		var key = dataset.Get("key").String()
+		var input = p.elem.Call("querySelector", `textarea[data-key="`+jsEscape(key)+`"]`)
+		if key == "" {
+			key = p.elem.Call("querySelector", "."+PanelPrefix+"new-key").Get("value").String()
+			input = p.elem.Call("querySelector", "."+PanelPrefix+"new-value")
+		}
+		p.save(key, input.Get("value").String())
+	}
+	return nil
+}
+
+// Decode the edited JSON value, and set it in the data store.
+func (p *Panel) save(key, value string) {
+	var message string
+	var v any
+	if key == "" {
+		message = "Key is required"
+	} else if err := json.Unmarshal([]byte(value), &v); err != nil {
+		message = "Invalid JSON for " + key + ": " + err.Error()
+	} else if err := p.src.SetData(key, v); err != nil {
+		message = "Could not set " + key + ": " + err.Error()
+	} else {
+		message = "Saved " + key
+	}
+	p.mu.Lock()
+	p.message = message
+	p.mu.Unlock()
+	p.render()
+}
+
+func (p *Panel) render() {
+	p.mu.Lock()
+	if p.remove == nil {
+		p.mu.Unlock()
+		return
+	}
+	var tab, message, elem = p.tab, p.message, p.elem
+	p.mu.Unlock()
+
+	var b strings.Builder
+	b.WriteString("<style>" + panelCSS + "</style>")
+	b.WriteString(`<div class="` + PanelPrefix + `header">`)
+	for _, t := range panelTabs {
+		var class = PanelPrefix + "tab"
+		if t == tab {
+			class += " " + PanelPrefix + "active"
+		}
+		b.WriteString(`<button class="` + class + `" data-action="tab" data-tab="` + t + `">` + tabTitle(t) + `</button>`)
+	}
+	b.WriteString(`<button class="` + PanelPrefix + `close" data-action="close">&times;</button></div>`)
+	b.WriteString(`<div class="` + PanelPrefix + `body">`)
+	if message != "" {
+		b.WriteString(`<p class="` + PanelPrefix + `message">` + html.EscapeString(message) + `</p>`)
+	}
+	switch tab {
+	case TabRoutes:
+		if p.src.Routes != nil {
+			writeRoutes(&b, p.src.Routes())
+		}
+	case TabNavigation:
+		p.writeNavigation(&b)
+	case TabMiddleware:
+		p.writeMiddleware(&b)
+	case TabData:
+		p.writeData(&b)
+	case TabRequests:
+		p.writeRequests(&b)
+	case TabErrors:
+		p.writeErrors(&b)
+	}
+	b.WriteString(`</div>`)
+	elem.Set("innerHTML", b.String())
+}
+
+func writeRoutes(b *strings.Builder, tree []*Route) {
+	b.WriteString("<ul>")
+	for _, rt := range tree {
+		b.WriteString("<li><b>" + html.EscapeString(rt.Name) + "</b> <code>" + html.EscapeString(rt.Path) + "</code>")
+		if !rt.Handler {
+			b.WriteString(" <i>no handler</i>")
+		}
+		if len(rt.Children) > 0 {
+			writeRoutes(b, rt.Children)
+		}
+		b.WriteString("</li>")
+	}
+	b.WriteString("</ul>")
+}
+
+func (p *Panel) writeNavigation(b *strings.Builder) {
+	var navigations = p.src.Recorder.Navigations()
+	if len(navigations) > 0 {
+		var current = navigations[len(navigations)-1]
+		b.WriteString("<h4>Current vars</h4>")
+		writeVars(b, current.Vars)
+	}
+	b.WriteString("<h4>Navigations</h4><table><tr><th>Time</th><th>Route</th><th>Path</th><th>Vars</th><th>Handler</th></tr>")
+	for i := len(navigations) - 1; i >= 0; i-- {
+		var nav = navigations[i]
+		b.WriteString("<tr><td>" + formatTime(nav.Time) + "</td><td>" + html.EscapeString(nav.Route) +
+			"</td><td><code>" + html.EscapeString(nav.Path) + "</code></td><td>")
+		writeVars(b, nav.Vars)
+		b.WriteString("</td><td>" + formatDuration(nav.Duration, nav.Done) + "</td></tr>")
+	}
+	b.WriteString("</table>")
+	writeClear(b)
+}
+
+func writeVars(b *strings.Builder, vars map[string]string) {
+	var keys = make([]string, 0, len(vars))
+	for k := range vars {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		b.WriteString("<code>" + html.EscapeString(k) + "=" + html.EscapeString(vars[k]) + "</code> ")
+	}
+}
+
+func (p *Panel) writeMiddleware(b *strings.Builder) {
+	var middlewares = p.src.Recorder.Middlewares()
+	b.WriteString("<table><tr><th>Time</th><th>Route</th><th>Middleware</th><th>Duration</th><th>Next</th></tr>")
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		var m = middlewares[i]
+		b.WriteString("<tr><td>" + formatTime(m.Time) + "</td><td>" + html.EscapeString(m.Route) +
+			"</td><td>#" + strconv.Itoa(m.Index) + "</td><td>" + formatDuration(m.Duration, true) +
+			"</td><td>" + strconv.FormatBool(m.Next) + "</td></tr>")
+	}
+	b.WriteString("</table>")
+	writeClear(b)
+}
+
+func (p *Panel) writeData(b *strings.Builder) {
+	var data map[string]any
+	if p.src.Data != nil {
+		data = p.src.Data()
+	}
+	var keys = make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	b.WriteString("<table><tr><th>Key</th><th>Value (JSON)</th><th></th></tr>")
+	for _, k := range keys {
+		var value, err = json.MarshalIndent(data[k], "", "  ")
+		var key = html.EscapeString(k)
+		b.WriteString("<tr><td><code>" + key + "</code></td><td>")
+		if err != nil {
+			b.WriteString("<i>" + html.EscapeString(err.Error()) + "</i></td><td></td></tr>")
+			continue
+		}
+		b.WriteString(`<textarea data-key="` + key + `">` + html.EscapeString(string(value)) + `</textarea></td>`)
+		if p.src.SetData != nil {
+			b.WriteString(`<td><button data-action="save" data-key="` + key + `">Save</button></td>`)
+		}
+		b.WriteString("</tr>")
+	}
+	if p.src.SetData != nil {
+		b.WriteString(`<tr><td><input class="` + PanelPrefix + `new-key" placeholder="key"></td>` +
+			`<td><textarea class="` + PanelPrefix + `new-value" placeholder="JSON value"></textarea></td>` +
+			`<td><button data-action="save" data-key="">Add</button></td></tr>`)
+	}
+	b.WriteString(`</table><button data-action="refresh">Refresh</button>`)
+}
+
+func (p *Panel) writeRequests(b *strings.Builder) {
+	var requests = p.src.Recorder.Requests()
+	b.WriteString("<table><tr><th>Time</th><th>Method</th><th>URL</th><th>Status</th><th>Duration</th></tr>")
+	for i := len(requests) - 1; i >= 0; i-- {
+		var r = requests[i]
+		var status = "pending"
+		switch {
+		case r.Error != "":
+			status = html.EscapeString(r.Error)
+		case r.Done:
+			status = strconv.Itoa(r.Status)
+		}
+		b.WriteString("<tr><td>" + formatTime(r.Time) + "</td><td>" + html.EscapeString(r.Method) +
+			"</td><td><code>" + html.EscapeString(r.URL) + "</code></td><td>" + status +
+			"</td><td>" + formatDuration(r.Duration, r.Done) + "</td></tr>")
+	}
+	b.WriteString("</table>")
+	writeClear(b)
+}
+
+func (p *Panel) writeErrors(b *strings.Builder) {
+	var errs = p.src.Recorder.Errors()
+	b.WriteString("<table><tr><th>Time</th><th>Error</th></tr>")
+	for i := len(errs) - 1; i >= 0; i-- {
+		b.WriteString("<tr><td>" + formatTime(errs[i].Time) + "</td><td>" + html.EscapeString(errs[i].Message) + "</td></tr>")
+	}
+	b.WriteString("</table>")
+	writeClear(b)
+}
+
+func writeClear(b *strings.Builder) {
+	b.WriteString(`<button data-action="clear">Clear</button>`)
+}
+
+func tabTitle(tab string) string {
+	return strings.ToUpper(tab[:1]) + tab[1:]
+}
+
+func formatTime(t time.Time) string {
+	return t.Format("15:04:05.000")
+}
+
+func formatDuration(d time.Duration, done bool) string {
+	if !done {
+		return "running"
+	}
+	return d.Round(time.Microsecond).String()
+}
+
+// Escape a value for use in a quoted CSS attribute selector.
+func jsEscape(s string) string {
+	return jsext.Global.Get("CSS").Call("escape", s).String()
+}
+
+var panelCSS = `
+.` + PanelPrefix + `panel { position: fixed; right: 0; bottom: 0; width: min(720px, 100vw); height: 45vh; display: flex; flex-direction: column; z-index: 10000; background-color: ` + theme.Surface + `; color: ` + theme.Text + `; border: 1px solid ` + theme.Border + `; box-shadow: 0 0 10px ` + theme.Shadow + `; font-family: monospace; font-size: 12px; }
+.` + PanelPrefix + `header { display: flex; flex-wrap: wrap; border-bottom: 1px solid ` + theme.Border + `; }
+.` + PanelPrefix + `header button { background: none; border: none; color: ` + theme.TextMuted + `; padding: 6px 10px; cursor: pointer; font: inherit; }
+.` + PanelPrefix + `header button.` + PanelPrefix + `active { color: ` + theme.Primary + `; border-bottom: 2px solid ` + theme.Primary + `; }
+.` + PanelPrefix + `close { margin-left: auto; }
+.` + PanelPrefix + `body { overflow: auto; padding: 8px; flex: 1; }
+.` + PanelPrefix + `body table { width: 100%; border-collapse: collapse; }
+.` + PanelPrefix + `body th, .` + PanelPrefix + `body td { text-align: left; vertical-align: top; padding: 2px 6px; border-bottom: 1px solid ` + theme.Border + `; }
+.` + PanelPrefix + `body textarea { width: 100%; min-height: 3em; font: inherit; background-color: ` + theme.Background + `; color: ` + theme.Text + `; }
+.` + PanelPrefix + `body button { margin-top: 4px; font: inherit; cursor: pointer; }
+.` + PanelPrefix + `message { color: ` + theme.Info + `; margin: 0 0 6px 0; }
+`
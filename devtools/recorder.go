@@ -0,0 +1,287 @@
+package devtools
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/Nigel2392/jsext-framework/client"
+	"github.com/Nigel2392/jsext-framework/helpers/hooks"
+	"github.com/Nigel2392/jsext-framework/router/routes"
+)
+
+// A page navigation handled by the router.
+type Navigation struct {
+	Time  time.Time         `json:"time"`
+	Path  string            `json:"path"`
+	Route string            `json:"route"`
+	Vars  map[string]string `json:"vars"`
+	// Time it took the route handler to run, zero while it is running.
+	Duration time.Duration `json:"duration"`
+	Done     bool          `json:"done"`
+}
+
+// Timing of a single router middleware.
+type Middleware struct {
+	Time     time.Time     `json:"time"`
+	Route    string        `json:"route"`
+	Index    int           `json:"index"`
+	Duration time.Duration `json:"duration"`
+	// Whether the middleware allowed the next middleware or the route to run.
+	Next bool `json:"next"`
+}
+
+// A request made with the application's HTTP client.
+type Request struct {
+	ID       int           `json:"id"`
+	Method   string        `json:"method"`
+	URL      string        `json:"url"`
+	Time     time.Time     `json:"time"`
+	Duration time.Duration `json:"duration"`
+	Status   int           `json:"status"`
+	Error    string        `json:"error,omitempty"`
+	Done     bool          `json:"done"`
+}
+
+// An error which occurred in the application.
+type Error struct {
+	Time    time.Time `json:"time"`
+	Message string    `json:"message"`
+}
+
+// A node in the route tree.
+type Route struct {
+	Name     string   `json:"name"`
+	Path     string   `json:"path"`
+	Regex    string   `json:"regex,omitempty"`
+	Handler  bool     `json:"handler"`
+	Children []*Route `json:"children,omitempty"`
+}
+
+// Build the route tree of the registered routes.
+func RouteTree(rts []*routes.Route) []*Route {
+	var tree = make([]*Route, 0, len(rts))
+	for _, rt := range rts {
+		tree = append(tree, &Route{
+			Name:     rt.Name,
+			Path:     rt.Path,
+			Regex:    rt.RegexUrl,
+			Handler:  rt.Callable != nil,
+			Children: RouteTree(rt.Children),
+		})
+	}
+	return tree
+}
+
+// Recorder keeps the most recent navigations, middleware timings, requests and errors.
+type Recorder struct {
+	limit       int
+	navigations []*Navigation
+	middlewares []*Middleware
+	requests    []*Request
+	errors      []*Error
+	nextID      int
+	listeners   *hooks.Hooks[func()]
+	mu          *sync.Mutex
+}
+
+// Initialize a recorder which keeps the last limit entries of each kind.
+// Requests which are still in flight are always kept.
+func NewRecorder(limit int) *Recorder {
+	if limit <= 0 {
+		limit = 50
+	}
+	return &Recorder{
+		limit:     limit,
+		listeners: &hooks.Hooks[func()]{},
+		mu:        &sync.Mutex{},
+	}
+}
+
+// Record the start of a navigation.
+// The returned function must be called when the route handler has finished.
+func (r *Recorder) Navigate(path, route string, vars map[string]string) (done func()) {
+	var nav = &Navigation{Time: time.Now(), Path: path, Route: route, Vars: make(map[string]string, len(vars))}
+	for k, v := range vars {
+		nav.Vars[k] = v
+	}
+	r.mu.Lock()
+	r.navigations = trim(append(r.navigations, nav), r.limit)
+	r.mu.Unlock()
+	r.changed()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			r.mu.Lock()
+			nav.Duration = time.Since(nav.Time)
+			nav.Done = true
+			r.mu.Unlock()
+			r.changed()
+		})
+	}
+}
+
+// Record the timing of a middleware.
+func (r *Recorder) Middleware(route string, index int, took time.Duration, next bool) {
+	r.mu.Lock()
+	r.middlewares = trim(append(r.middlewares, &Middleware{
+		Time:     time.Now(),
+		Route:    route,
+		Index:    index,
+		Duration: took,
+		Next:     next,
+	}), r.limit)
+	r.mu.Unlock()
+	r.changed()
+}
+
+// Record an error.
+func (r *Recorder) Error(err error) {
+	if err == nil {
+		return
+	}
+	r.mu.Lock()
+	r.errors = trim(append(r.errors, &Error{Time: time.Now(), Message: err.Error()}), r.limit)
+	r.mu.Unlock()
+	r.changed()
+}
+
+// Interceptor to record the requests of a client.
+// Failed requests and responses with an error status are also recorded as errors.
+func (r *Recorder) Interceptor() client.Interceptor {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return client.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			r.mu.Lock()
+			r.nextID++
+			var rec = &Request{ID: r.nextID, Method: req.Method, URL: req.URL.String(), Time: time.Now()}
+			r.requests = append(r.requests, rec)
+			r.trimRequests()
+			r.mu.Unlock()
+			r.changed()
+
+			var resp, err = next.RoundTrip(req)
+
+			r.mu.Lock()
+			rec.Duration = time.Since(rec.Time)
+			rec.Done = true
+			if err != nil {
+				rec.Error = err.Error()
+			} else {
+				rec.Status = resp.StatusCode
+			}
+			r.trimRequests()
+			r.mu.Unlock()
+			if err != nil {
+				r.Error(err)
+			} else if resp.StatusCode >= 400 {
+				r.Error(client.NewError(resp))
+			} else {
+				r.changed()
+			}
+			return resp, err
+		})
+	}
+}
+
+// Recent navigations, oldest first.
+func (r *Recorder) Navigations() []Navigation {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return copyAll(r.navigations)
+}
+
+// Recent middleware timings, oldest first.
+func (r *Recorder) Middlewares() []Middleware {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return copyAll(r.middlewares)
+}
+
+// Requests in flight and recent requests, oldest first.
+func (r *Recorder) Requests() []Request {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return copyAll(r.requests)
+}
+
+// Requests which have not finished yet.
+func (r *Recorder) InFlight() []Request {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var inFlight = make([]Request, 0)
+	for _, req := range r.requests {
+		if !req.Done {
+			inFlight = append(inFlight, *req)
+		}
+	}
+	return inFlight
+}
+
+// Recent errors, oldest first.
+func (r *Recorder) Errors() []Error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return copyAll(r.errors)
+}
+
+// Remove all recorded entries, except for requests in flight.
+func (r *Recorder) Clear() {
+	r.mu.Lock()
+	r.navigations = nil
+	r.middlewares = nil
+	r.errors = nil
+	var inFlight []*Request
+	for _, req := range r.requests {
+		if !req.Done {
+			inFlight = append(inFlight, req)
+		}
+	}
+	r.requests = inFlight
+	r.mu.Unlock()
+	r.changed()
+}
+
+// Call the function when something is recorded.
+// The returned function removes the listener.
+func (r *Recorder) OnChange(f func()) (remove func()) {
+	return r.listeners.Add(f)
+}
+
+func (r *Recorder) changed() {
+	for _, f := range r.listeners.List() {
+		f()
+	}
+}
+
+// Remove the oldest finished requests over the limit.
+// Must be called with the recorder locked.
+func (r *Recorder) trimRequests() {
+	var over = len(r.requests) - r.limit
+	if over <= 0 {
+		return
+	}
+	var kept = r.requests[:0:0]
+	for _, req := range r.requests {
+		if over > 0 && req.Done {
+			over--
+			continue
+		}
+		kept = append(kept, req)
+	}
+	r.requests = kept
+}
+
+func trim[T any](s []*T, limit int) []*T {
+	if len(s) <= limit {
+		return s
+	}
+	return append(s[:0:0], s[len(s)-limit:]...)
+}
+
+func copyAll[T any](s []*T) []T {
+	var c = make([]T, len(s))
+	for i, v := range s {
+		c[i] = *v
+	}
+	return c
+}
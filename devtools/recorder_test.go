@@ -0,0 +1,104 @@
+package devtools_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Nigel2392/jsext-framework/client"
+	"github.com/Nigel2392/jsext-framework/devtools"
+	"github.com/Nigel2392/jsext-framework/router/routes"
+)
+
+func TestNavigations(t *testing.T) {
+	var r = devtools.NewRecorder(2)
+	var changes int
+	r.OnChange(func() { changes++ })
+
+	r.Navigate("/a", "a", nil)()
+	r.Navigate("/b", "b", map[string]string{"id": "1"})()
+	var done = r.Navigate("/c", "c", nil)
+
+	var navigations = r.Navigations()
+	if len(navigations) != 2 || navigations[0].Route != "b" || navigations[1].Route != "c" {
+		t.Fatalf("expected the last 2 navigations, got %+v", navigations)
+	}
+	if navigations[0].Vars["id"] != "1" || !navigations[0].Done {
+		t.Errorf("unexpected navigation %+v", navigations[0])
+	}
+	if navigations[1].Done {
+		t.Error("navigation finished before done was called")
+	}
+	done()
+	done()
+	if !r.Navigations()[1].Done {
+		t.Error("navigation not finished after done was called")
+	}
+	if changes != 6 {
+		t.Errorf("expected 6 changes, got %d", changes)
+	}
+
+	r.Error(errors.New("boom"))
+	r.Clear()
+	if len(r.Navigations()) != 0 || len(r.Errors()) != 0 {
+		t.Error("entries not cleared")
+	}
+}
+
+func TestRequests(t *testing.T) {
+	var block = make(chan struct{})
+	var srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/slow" {
+			<-block
+		}
+		if req.URL.Path == "/missing" {
+			http.NotFound(w, req)
+		}
+	}))
+	defer srv.Close()
+
+	var r = devtools.NewRecorder(10)
+	var c = client.New(client.BaseURL(srv.URL), r.Interceptor())
+
+	var finished = make(chan error)
+	go func() { finished <- c.Get(context.Background(), "/slow", nil) }()
+	for len(r.InFlight()) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	if got := r.InFlight()[0]; got.Method != http.MethodGet || got.URL != srv.URL+"/slow" {
+		t.Errorf("unexpected request in flight %+v", got)
+	}
+	close(block)
+	if err := <-finished; err != nil {
+		t.Fatal(err)
+	}
+	if len(r.InFlight()) != 0 {
+		t.Error("request still in flight")
+	}
+
+	c.Get(context.Background(), "/missing", nil)
+	var requests = r.Requests()
+	if len(requests) != 2 || requests[0].Status != 200 || requests[1].Status != 404 {
+		t.Fatalf("unexpected requests %+v", requests)
+	}
+	if len(r.Errors()) != 1 {
+		t.Errorf("expected the 404 to be recorded as an error, got %+v", r.Errors())
+	}
+}
+
+func TestRouteTree(t *testing.T) {
+	var tree = devtools.RouteTree([]*routes.Route{{
+		Name: "users",
+		Path: "/users",
+		Children: []*routes.Route{{
+			Name: "user",
+			Path: "/users/<<id:int>>",
+		}},
+	}})
+	if len(tree) != 1 || tree[0].Name != "users" || len(tree[0].Children) != 1 || tree[0].Children[0].Path != "/users/<<id:int>>" {
+		t.Errorf("unexpected route tree %+v", tree)
+	}
+}
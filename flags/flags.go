@@ -0,0 +1,301 @@
+// Package flags provides feature flags and remote configuration.
+//
+// Flags are loaded from providers, later providers override the flags of earlier ones.
+// A flag can be enabled for everyone, or for a percentage of the users.
+// Routes and navigation links can be gated by a flag, they are hidden while the flag is disabled.
+// In the browser, gated links of all router.URLs maps are hidden automatically.
+package flags
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"hash/fnv"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Nigel2392/jsext-framework/helpers/hooks"
+	"github.com/Nigel2392/jsext-framework/router/routes"
+	"github.com/Nigel2392/jsext-framework/router/rterr"
+	"github.com/Nigel2392/jsext-framework/router/vars"
+)
+
+// Returned by Value when the flag does not exist or has no value.
+var ErrNoValue = errors.New("flags: no value")
+
+// Flag as loaded from a provider.
+//
+// In JSON, a flag can also be written as a boolean:
+//
+//	{"search": true, "checkout": {"rollout": 25}, "banner": {"enabled": true, "value": "Sale!"}}
+type Flag struct {
+	// Enabled for everyone.
+	Enabled bool `json:"enabled"`
+	// Percentage of the users the flag is enabled for, from 0 to 100.
+	Rollout float64 `json:"rollout,omitempty"`
+	// Configuration value of the flag, see Flags.Value.
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+func (f *Flag) UnmarshalJSON(b []byte) error {
+	if bytes.Equal(b, []byte("true")) || bytes.Equal(b, []byte("false")) {
+		*f = Flag{Enabled: b[0] == 't'}
+		return nil
+	}
+	type flag Flag
+	return json.Unmarshal(b, (*flag)(f))
+}
+
+func (f Flag) equal(other Flag) bool {
+	return f.Enabled == other.Enabled && f.Rollout == other.Rollout && bytes.Equal(f.Value, other.Value)
+}
+
+// Check if a user is in the rollout of a flag.
+// Users are assigned to a stable bucket per flag, so a user keeps seeing the same features,
+// and raising the percentage only adds users.
+func InRollout(name, user string, percentage float64) bool {
+	if percentage >= 100 {
+		return true
+	}
+	if user == "" || percentage <= 0 {
+		return false
+	}
+	var h = fnv.New32a()
+	h.Write([]byte(name + ":" + user))
+	return float64(h.Sum32()%10000) < percentage*100
+}
+
+// Options for the flags.
+type Options struct {
+	// ID of the current user, used for percentage rollouts.
+	// Users without an ID only see the flags which are enabled for everyone.
+	UserID func() string
+	// Called when a provider fails to load, the flags it loaded before are kept.
+	OnError func(err error)
+}
+
+func (o *Options) SetDefaults() {
+	if o.UserID == nil {
+		o.UserID = func() string { return "" }
+	}
+	if o.OnError == nil {
+		o.OnError = func(err error) {}
+	}
+}
+
+// Feature flags loaded from providers.
+//
+// Flags are safe for concurrent use.
+type Flags struct {
+	opts      Options
+	mu        sync.RWMutex
+	providers []Provider
+	// Flags per provider, kept when a provider fails to load.
+	loaded    []map[string]Flag
+	flags     map[string]Flag
+	overrides map[string]bool
+	// Flag gating a route or link, keyed by the lower case name.
+	gates     map[string]string
+	listeners hooks.Hooks[func(*Flags)]
+	unfilter  func()
+}
+
+// Create new flags, load them with Load.
+func New(opts *Options, providers ...Provider) *Flags {
+	var o Options
+	if opts != nil {
+		o = *opts
+	}
+	o.SetDefaults()
+	var f = &Flags{
+		opts:      o,
+		providers: providers,
+		loaded:    make([]map[string]Flag, len(providers)),
+		flags:     make(map[string]Flag),
+		overrides: make(map[string]bool),
+		gates:     make(map[string]string),
+	}
+	f.unfilter = f.filterURLs()
+	return f
+}
+
+// Stop hiding the gated links of url maps, the links are shown again.
+// Call it when the flags are replaced, the routes gated by the middleware stay gated.
+func (f *Flags) Close() {
+	f.unfilter()
+}
+
+// Load the flags from the providers.
+// Returns the first error, the other providers are still loaded.
+func (f *Flags) Load(ctx context.Context) error {
+	var first error
+	var loaded = make([]map[string]Flag, len(f.providers))
+	for i, p := range f.providers {
+		var flags, err = p.Load(ctx)
+		if err != nil {
+			f.opts.OnError(err)
+			if first == nil {
+				first = err
+			}
+			continue
+		}
+		loaded[i] = flags
+	}
+	f.mu.Lock()
+	var merged = make(map[string]Flag)
+	for i := range f.loaded {
+		if loaded[i] != nil {
+			f.loaded[i] = loaded[i]
+		}
+		for name, flag := range f.loaded[i] {
+			merged[name] = flag
+		}
+	}
+	var changed = len(merged) != len(f.flags)
+	for name, flag := range merged {
+		if old, ok := f.flags[name]; !ok || !old.equal(flag) {
+			changed = true
+		}
+	}
+	f.flags = merged
+	f.mu.Unlock()
+	if changed {
+		f.changed()
+	}
+	return first
+}
+
+// Load the flags every interval, until stop is called.
+func (f *Flags) Refresh(interval time.Duration) (stop func()) {
+	var ctx, cancel = context.WithCancel(context.Background())
+	go func() {
+		var ticker = time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				f.Load(ctx)
+			}
+		}
+	}()
+	return cancel
+}
+
+// Check if a flag is enabled for the current user.
+// Unknown flags are disabled.
+func (f *Flags) Enabled(name string) bool {
+	f.mu.RLock()
+	var enabled, overridden = f.overrides[name]
+	var flag, ok = f.flags[name]
+	f.mu.RUnlock()
+	if overridden {
+		return enabled
+	}
+	if !ok {
+		return false
+	}
+	return flag.Enabled || InRollout(name, f.opts.UserID(), flag.Rollout)
+}
+
+// Get a flag as loaded from the providers.
+func (f *Flags) Get(name string) (Flag, bool) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	var flag, ok = f.flags[name]
+	return flag, ok
+}
+
+// Names of the loaded flags, sorted.
+func (f *Flags) Names() []string {
+	f.mu.RLock()
+	var names = make([]string, 0, len(f.flags))
+	for name := range f.flags {
+		names = append(names, name)
+	}
+	f.mu.RUnlock()
+	sort.Strings(names)
+	return names
+}
+
+// Decode the configuration value of a flag into v.
+func (f *Flags) Value(name string, v any) error {
+	var flag, ok = f.Get(name)
+	if !ok || len(flag.Value) == 0 {
+		return ErrNoValue
+	}
+	return json.Unmarshal(flag.Value, v)
+}
+
+// Configuration value of a flag as a string, or the default if it is not set.
+func (f *Flags) String(name, def string) string {
+	var s string
+	if f.Value(name, &s) != nil {
+		return def
+	}
+	return s
+}
+
+// Enable or disable a flag for this session, regardless of the providers.
+func (f *Flags) Override(name string, enabled bool) {
+	f.mu.Lock()
+	f.overrides[name] = enabled
+	f.mu.Unlock()
+	f.changed()
+}
+
+// Remove the overrides of the flags.
+func (f *Flags) ClearOverrides() {
+	f.mu.Lock()
+	f.overrides = make(map[string]bool)
+	f.mu.Unlock()
+	f.changed()
+}
+
+// Gate routes and navigation links by a flag, they are hidden while the flag is disabled.
+// Names are route names and keys of router.URLs, compared case-insensitively.
+func (f *Flags) Gate(flag string, names ...string) *Flags {
+	f.mu.Lock()
+	for _, name := range names {
+		f.gates[strings.ToLower(name)] = flag
+	}
+	f.mu.Unlock()
+	f.changed()
+	return f
+}
+
+// Check if a gated route or link is visible.
+// Names which are not gated are always visible.
+func (f *Flags) Visible(name string) bool {
+	f.mu.RLock()
+	var flag, ok = f.gates[strings.ToLower(name)]
+	f.mu.RUnlock()
+	return !ok || f.Enabled(flag)
+}
+
+// Router middleware which responds with a 404 for gated routes while their flag is disabled.
+func (f *Flags) Middleware() func(vars.Vars, *url.URL, *routes.Route, rterr.ErrorThrower) bool {
+	return func(v vars.Vars, u *url.URL, rt *routes.Route, t rterr.ErrorThrower) bool {
+		if rt == nil || f.Visible(rt.Name) {
+			return true
+		}
+		t.Throw(rterr.ErrCodeNotFound)
+		return false
+	}
+}
+
+// Function to call when the flags change, after loading, overriding or gating.
+func (f *Flags) OnChange(fn func(*Flags)) (remove func()) {
+	return f.listeners.Add(fn)
+}
+
+func (f *Flags) changed() {
+	for _, fn := range f.listeners.List() {
+		fn(f)
+	}
+}
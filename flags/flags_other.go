@@ -0,0 +1,9 @@
+//go:build !js || !wasm
+// +build !js !wasm
+
+package flags
+
+// Links are only rendered in the browser.
+func (f *Flags) filterURLs() (remove func()) {
+	return func() {}
+}
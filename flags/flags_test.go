@@ -0,0 +1,125 @@
+package flags_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"testing/fstest"
+
+	"github.com/Nigel2392/jsext-framework/flags"
+	"github.com/Nigel2392/jsext-framework/router/routes"
+	"github.com/Nigel2392/jsext-framework/router/rterr"
+)
+
+func TestProviders(t *testing.T) {
+	var fsys = fstest.MapFS{"flags.json": {Data: []byte(`{"search": true, "banner": {"enabled": true, "value": "Sale!"}}`)}}
+	var fail = true
+	var remote = flags.ProviderFunc(func(ctx context.Context) (map[string]flags.Flag, error) {
+		if fail {
+			return nil, errors.New("offline")
+		}
+		return map[string]flags.Flag{"search": {Enabled: false}}, nil
+	})
+	var f = flags.New(nil, flags.Static{"search": {Enabled: false}, "old": {Enabled: true}}, flags.File(fsys, "flags.json"), remote)
+	if err := f.Load(context.Background()); err == nil {
+		t.Fatal("expected the error of the failing provider")
+	}
+	if !f.Enabled("search") || !f.Enabled("old") || f.Enabled("missing") {
+		t.Fatalf("unexpected flags %v", f.Names())
+	}
+	if f.String("banner", "") != "Sale!" || f.String("search", "none") != "none" {
+		t.Fatal("unexpected values")
+	}
+	fail = false
+	var changes int
+	f.OnChange(func(*flags.Flags) { changes++ })
+	f.Load(context.Background())
+	if f.Enabled("search") || changes != 1 {
+		t.Fatalf("expected the remote flag to override, changes %d", changes)
+	}
+	// Flags of a provider are kept when it fails.
+	fail = true
+	f.Load(context.Background())
+	if f.Enabled("search") || changes != 1 {
+		t.Fatalf("expected the remote flags to be kept, changes %d", changes)
+	}
+	f.Override("search", true)
+	if !f.Enabled("search") {
+		t.Fatal("expected the override to enable the flag")
+	}
+	f.ClearOverrides()
+	if f.Enabled("search") {
+		t.Fatal("expected the override to be cleared")
+	}
+}
+
+func TestHTTP(t *testing.T) {
+	var server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"checkout": {"rollout": 100}}`))
+	}))
+	defer server.Close()
+	var f = flags.New(nil, flags.HTTP(nil, server.URL))
+	if err := f.Load(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if !f.Enabled("checkout") {
+		t.Fatal("expected a full rollout to be enabled")
+	}
+}
+
+func TestRollout(t *testing.T) {
+	var user = "1"
+	var f = flags.New(&flags.Options{UserID: func() string { return user }}, flags.Static{"beta": {Rollout: 25}})
+	f.Load(context.Background())
+	var enabled int
+	for i := 0; i < 10000; i++ {
+		user = strconv.Itoa(i)
+		if f.Enabled("beta") != flags.InRollout("beta", user, 25) {
+			t.Fatal("expected Enabled to match InRollout")
+		}
+		if f.Enabled("beta") {
+			enabled++
+		}
+		if flags.InRollout("beta", user, 25) && !flags.InRollout("beta", user, 50) {
+			t.Fatal("expected raising the percentage to keep the users")
+		}
+	}
+	if enabled < 2200 || enabled > 2800 {
+		t.Fatalf("expected about 25%% of the users, got %d", enabled)
+	}
+	user = ""
+	if f.Enabled("beta") {
+		t.Fatal("expected users without an ID to be excluded from rollouts")
+	}
+}
+
+type thrower struct{ code int }
+
+func (t *thrower) Error(code int, message string) rterr.RouterError {
+	t.code = code
+	return rterr.NewError(code, message)
+}
+
+func (t *thrower) Throw(code int) { t.code = code }
+
+func TestGate(t *testing.T) {
+	var f = flags.New(nil, flags.Static{"beta": {Enabled: false}})
+	f.Load(context.Background())
+	f.Gate("beta", "Beta", "labs")
+	if f.Visible("BETA") || f.Visible("labs") || !f.Visible("home") {
+		t.Fatal("unexpected visibility")
+	}
+	var mw = f.Middleware()
+	var th thrower
+	if mw(nil, nil, &routes.Route{Name: "beta"}, &th) || th.code != 404 {
+		t.Fatalf("expected the gated route to be hidden, got %d", th.code)
+	}
+	f.Override("beta", true)
+	th.code = 0
+	if !mw(nil, nil, &routes.Route{Name: "beta"}, &th) || th.code != 0 {
+		t.Fatal("expected the route to be visible")
+	}
+}
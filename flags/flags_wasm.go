@@ -0,0 +1,45 @@
+//go:build js && wasm
+// +build js,wasm
+
+package flags
+
+import (
+	"strconv"
+
+	"github.com/Nigel2392/jsext-framework/router"
+	"github.com/Nigel2392/jsext-framework/tokens"
+)
+
+// Hide the gated links of all url maps while their flag is disabled.
+// The links are updated when the flags change, until the returned function is called.
+func (f *Flags) filterURLs() (remove func()) {
+	var removeFilter = router.FilterURLs(f.Visible)
+	var removeListener = f.OnChange(func(*Flags) {
+		router.UpdateURLs()
+	})
+	return func() {
+		removeListener()
+		removeFilter()
+	}
+}
+
+// User ID for rollouts, read from the data of the token.
+// Numeric IDs decoded from JSON are formatted without an exponent.
+func TokenUserID(t *tokens.Token, key string) func() string {
+	return func() string {
+		if t == nil || t.AccessToken == "" {
+			return ""
+		}
+		switch id := t.Data[key].(type) {
+		case string:
+			return id
+		case float64:
+			return strconv.FormatFloat(id, 'f', -1, 64)
+		case int:
+			return strconv.Itoa(id)
+		case int64:
+			return strconv.FormatInt(id, 10)
+		}
+		return ""
+	}
+}
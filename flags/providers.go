@@ -0,0 +1,66 @@
+package flags
+
+import (
+	"context"
+	"encoding/json"
+	"io/fs"
+
+	"github.com/Nigel2392/jsext-framework/client"
+)
+
+// Provider loads flag definitions.
+type Provider interface {
+	Load(ctx context.Context) (map[string]Flag, error)
+}
+
+// Adapter to use a function as a provider.
+type ProviderFunc func(ctx context.Context) (map[string]Flag, error)
+
+func (f ProviderFunc) Load(ctx context.Context) (map[string]Flag, error) {
+	return f(ctx)
+}
+
+// Flags defined in code.
+type Static map[string]Flag
+
+func (s Static) Load(ctx context.Context) (map[string]Flag, error) {
+	var flags = make(map[string]Flag, len(s))
+	for name, flag := range s {
+		flags[name] = flag
+	}
+	return flags, nil
+}
+
+// Flags from a JSON object of flags.
+func JSON(data []byte) Provider {
+	return ProviderFunc(func(ctx context.Context) (map[string]Flag, error) {
+		var flags map[string]Flag
+		return flags, json.Unmarshal(data, &flags)
+	})
+}
+
+// Flags from a JSON file, for example in an embed.FS.
+func File(fsys fs.FS, name string) Provider {
+	return ProviderFunc(func(ctx context.Context) (map[string]Flag, error) {
+		var data, err = fs.ReadFile(fsys, name)
+		if err != nil {
+			return nil, err
+		}
+		return JSON(data).Load(ctx)
+	})
+}
+
+// Flags from a JSON object served over HTTP.
+// If c is nil, a client without interceptors is used.
+func HTTP(c *client.Client, url string) Provider {
+	if c == nil {
+		c = client.New()
+	}
+	return ProviderFunc(func(ctx context.Context) (map[string]Flag, error) {
+		var flags map[string]Flag
+		if err := c.Get(ctx, url, &flags); err != nil {
+			return nil, err
+		}
+		return flags, nil
+	})
+}
@@ -4,6 +4,8 @@ import (
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/Nigel2392/jsext-framework/i18n"
 )
 
 func FormatNumber(value any) string {
@@ -123,6 +125,32 @@ func (t *TimeTracker) Format(format string) string {
 	return format
 }
 
+// Format the tracked time with the plural time unit messages of the translator.
+// If the translator is nil, the default translator is used.
+//
+//	"2 years, 1 month, 0 days, 3 hours, 1 minute, 10 seconds"
+func (t *TimeTracker) Translate(tr *i18n.Translator) string {
+	if tr == nil {
+		tr = i18n.Default
+	}
+	var units = [...]struct {
+		key   string
+		count int
+	}{
+		{"time.years", t.Years},
+		{"time.months", t.Months},
+		{"time.days", t.Days},
+		{"time.hours", t.Hours},
+		{"time.minutes", t.Minutes},
+		{"time.seconds", t.Seconds},
+	}
+	var parts = make([]string, len(units))
+	for i, unit := range units {
+		parts[i] = tr.T(unit.key, i18n.Args{"count": unit.count})
+	}
+	return strings.Join(parts, tr.T("time.separator"))
+}
+
 func (t *TimeTracker) IsZero() bool {
 	return t.time.IsZero()
 }
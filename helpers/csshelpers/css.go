@@ -6,6 +6,8 @@ package csshelpers
 import "strconv"
 
 // General colors for use in CSS. Can be overridden.
+// The framework components use the CSS custom properties of the theme package instead,
+// which can be changed at runtime.
 var (
 	COLOR_MAIN        = "white"           // Main color - white
 	COLOR_ONE         = "#222e50"         // COLOR_ONE - dark blue
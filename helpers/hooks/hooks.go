@@ -0,0 +1,53 @@
+// Package hooks keeps listeners which are called in the order they were added,
+// and can be removed again.
+package hooks
+
+import "sync"
+
+// Hooks of type F, such as func(locale string).
+// The zero value is ready to use, and safe for concurrent use.
+type Hooks[F any] struct {
+	mu    sync.Mutex
+	hooks []*hook[F]
+}
+
+type hook[F any] struct {
+	f F
+}
+
+// Add the function, the returned function removes it.
+func (h *Hooks[F]) Add(f F) (remove func()) {
+	var added = &hook[F]{f: f}
+	h.mu.Lock()
+	h.hooks = append(h.hooks, added)
+	h.mu.Unlock()
+	return func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		for i, hook := range h.hooks {
+			if hook == added {
+				h.hooks = append(h.hooks[:i:i], h.hooks[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// Functions in the order they were added.
+// Call them outside of any locks, so they can add and remove hooks.
+func (h *Hooks[F]) List() []F {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	var fs = make([]F, len(h.hooks))
+	for i, hook := range h.hooks {
+		fs[i] = hook.f
+	}
+	return fs
+}
+
+// Number of functions.
+func (h *Hooks[F]) Len() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.hooks)
+}
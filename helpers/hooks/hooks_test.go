@@ -0,0 +1,56 @@
+package hooks_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Nigel2392/jsext-framework/helpers/hooks"
+)
+
+func TestHooks(t *testing.T) {
+	var h hooks.Hooks[func() string]
+	var add = func(s string) func() {
+		return h.Add(func() string { return s })
+	}
+	var call = func() []string {
+		var got = []string{}
+		for _, f := range h.List() {
+			got = append(got, f())
+		}
+		return got
+	}
+
+	var removeA = add("a")
+	var removeB = add("b")
+	add("c")
+	if got := call(); !reflect.DeepEqual(got, []string{"a", "b", "c"}) {
+		t.Fatalf("got %v", got)
+	}
+	removeB()
+	removeB()
+	add("d")
+	if got := call(); !reflect.DeepEqual(got, []string{"a", "c", "d"}) {
+		t.Fatalf("got %v after removing b", got)
+	}
+	removeA()
+	if got := call(); !reflect.DeepEqual(got, []string{"c", "d"}) || h.Len() != 2 {
+		t.Fatalf("got %v after removing a", got)
+	}
+}
+
+func TestRemoveWhileCalling(t *testing.T) {
+	var h hooks.Hooks[func()]
+	var calls int
+	var remove func()
+	remove = h.Add(func() {
+		calls++
+		remove()
+	})
+	h.Add(func() { calls++ })
+	for _, f := range h.List() {
+		f()
+	}
+	if calls != 2 || h.Len() != 1 {
+		t.Fatalf("called %d times, %d hooks left", calls, h.Len())
+	}
+}
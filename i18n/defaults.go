@@ -0,0 +1,93 @@
+package i18n
+
+// Messages used by the framework, loaded into the default translator.
+//
+// Override them by loading a catalog with the same keys:
+//
+//	i18n.Default.Load("en", i18n.Catalog{"error.404": "Page not found"})
+var Defaults = map[string]Catalog{
+	"en": {
+		"error.format":           "Error code {code}: {message}",
+		"error.400":              "Invalid request",
+		"error.401":              "Not authorized",
+		"error.403":              "Forbidden",
+		"error.404":              "Not found",
+		"error.405":              "Method not allowed",
+		"error.406":              "Unacceptable request format",
+		"error.407":              "Proxy authentication required",
+		"error.408":              "Request timeout",
+		"error.418":              "I'm a teapot",
+		"error.500":              "Internal server error",
+		"error.501":              "Not yet implemented",
+		"error.502":              "Bad gateway",
+		"error.503":              "Service unavailable",
+		"error.unknown":          "Unknown error has occurred.",
+		"router.no_route":        "no route found for path: {path}",
+		"router.error.title":     "Error",
+		"router.error.close":     "Close",
+		"roadmap.to":             "to",
+		"roadmap.present":        "Present",
+		"shortcuts.title":        "Keyboard shortcuts",
+		"shortcuts.close":        "Close",
+		"shortcuts.then":         "then",
+		"shortcuts.scope.global": "General",
+		"shortcuts.scope.route":  "This page",
+		"shortcuts.help":         "Show keyboard shortcuts",
+		"idle.title":             "Are you still there?",
+		"idle.message":           "You will be signed out in {count, plural, one {# second} other {# seconds}} due to inactivity.",
+		"idle.stay":              "Stay signed in",
+		"idle.logout":            "Sign out",
+		"time.years":             "{count, plural, one {# year} other {# years}}",
+		"time.months":            "{count, plural, one {# month} other {# months}}",
+		"time.days":              "{count, plural, one {# day} other {# days}}",
+		"time.hours":             "{count, plural, one {# hour} other {# hours}}",
+		"time.minutes":           "{count, plural, one {# minute} other {# minutes}}",
+		"time.seconds":           "{count, plural, one {# second} other {# seconds}}",
+		"time.separator":         ", ",
+	},
+	"nl": {
+		"error.format":           "Foutcode {code}: {message}",
+		"error.400":              "Ongeldig verzoek",
+		"error.401":              "Niet geautoriseerd",
+		"error.403":              "Geen toegang",
+		"error.404":              "Niet gevonden",
+		"error.405":              "Methode niet toegestaan",
+		"error.406":              "Onaanvaardbaar verzoekformaat",
+		"error.407":              "Proxy-authenticatie vereist",
+		"error.408":              "Time-out van verzoek",
+		"error.418":              "Ik ben een theepot",
+		"error.500":              "Interne serverfout",
+		"error.501":              "Nog niet geïmplementeerd",
+		"error.502":              "Ongeldige gateway",
+		"error.503":              "Dienst niet beschikbaar",
+		"error.unknown":          "Er is een onbekende fout opgetreden.",
+		"router.no_route":        "geen route gevonden voor pad: {path}",
+		"router.error.title":     "Fout",
+		"router.error.close":     "Sluiten",
+		"roadmap.to":             "tot",
+		"roadmap.present":        "Heden",
+		"shortcuts.title":        "Sneltoetsen",
+		"shortcuts.close":        "Sluiten",
+		"shortcuts.then":         "dan",
+		"shortcuts.scope.global": "Algemeen",
+		"shortcuts.scope.route":  "Deze pagina",
+		"shortcuts.help":         "Sneltoetsen weergeven",
+		"idle.title":             "Bent u er nog?",
+		"idle.message":           "U wordt over {count, plural, one {# seconde} other {# seconden}} afgemeld wegens inactiviteit.",
+		"idle.stay":              "Aangemeld blijven",
+		"idle.logout":            "Afmelden",
+		"time.years":             "{count, plural, one {# jaar} other {# jaar}}",
+		"time.months":            "{count, plural, one {# maand} other {# maanden}}",
+		"time.days":              "{count, plural, one {# dag} other {# dagen}}",
+		"time.hours":             "{count, plural, one {# uur} other {# uur}}",
+		"time.minutes":           "{count, plural, one {# minuut} other {# minuten}}",
+		"time.seconds":           "{count, plural, one {# seconde} other {# seconden}}",
+		"time.separator":         ", ",
+	},
+}
+
+func init() {
+	for locale, catalog := range Defaults {
+		Default.Load(locale, catalog)
+	}
+}
@@ -0,0 +1,188 @@
+package i18n
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Style to format dates with.
+type DateStyle int
+
+const (
+	DateShort  DateStyle = 0
+	DateMedium DateStyle = 1
+	DateLong   DateStyle = 2
+	DateFull   DateStyle = 3
+	DateTime   DateStyle = 4
+)
+
+// Locale specific formatting of numbers and dates.
+//
+// Date layouts use the layout of the time package.
+// English month and day names in the formatted date are replaced with the localized names.
+type LocaleFormat struct {
+	Decimal     string
+	Group       string
+	Short       string
+	Medium      string
+	Long        string
+	Full        string
+	Time        string
+	Months      [12]string
+	ShortMonths [12]string
+	Days        [7]string
+	ShortDays   [7]string
+}
+
+var englishFormat = LocaleFormat{
+	Decimal:     ".",
+	Group:       ",",
+	Short:       "1/2/06",
+	Medium:      "Jan 2, 2006",
+	Long:        "January 2, 2006",
+	Full:        "Monday, January 2, 2006",
+	Time:        "Jan 2, 2006, 3:04 PM",
+	Months:      [12]string{"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"},
+	ShortMonths: [12]string{"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sep", "Oct", "Nov", "Dec"},
+	Days:        [7]string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"},
+	ShortDays:   [7]string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"},
+}
+
+var formats = map[string]LocaleFormat{
+	"en": englishFormat,
+	"nl": {
+		Decimal:     ",",
+		Group:       ".",
+		Short:       "02-01-2006",
+		Medium:      "2 Jan 2006",
+		Long:        "2 January 2006",
+		Full:        "Monday 2 January 2006",
+		Time:        "2 Jan 2006 15:04",
+		Months:      [12]string{"januari", "februari", "maart", "april", "mei", "juni", "juli", "augustus", "september", "oktober", "november", "december"},
+		ShortMonths: [12]string{"jan", "feb", "mrt", "apr", "mei", "jun", "jul", "aug", "sep", "okt", "nov", "dec"},
+		Days:        [7]string{"zondag", "maandag", "dinsdag", "woensdag", "donderdag", "vrijdag", "zaterdag"},
+		ShortDays:   [7]string{"zo", "ma", "di", "wo", "do", "vr", "za"},
+	},
+	"de": {
+		Decimal:     ",",
+		Group:       ".",
+		Short:       "02.01.06",
+		Medium:      "02.01.2006",
+		Long:        "2. January 2006",
+		Full:        "Monday, 2. January 2006",
+		Time:        "02.01.2006, 15:04",
+		Months:      [12]string{"Januar", "Februar", "März", "April", "Mai", "Juni", "Juli", "August", "September", "Oktober", "November", "Dezember"},
+		ShortMonths: [12]string{"Jan", "Feb", "Mär", "Apr", "Mai", "Jun", "Jul", "Aug", "Sep", "Okt", "Nov", "Dez"},
+		Days:        [7]string{"Sonntag", "Montag", "Dienstag", "Mittwoch", "Donnerstag", "Freitag", "Samstag"},
+		ShortDays:   [7]string{"So", "Mo", "Di", "Mi", "Do", "Fr", "Sa"},
+	},
+	"fr": {
+		Decimal:     ",",
+		Group:       " ",
+		Short:       "02/01/2006",
+		Medium:      "2 Jan 2006",
+		Long:        "2 January 2006",
+		Full:        "Monday 2 January 2006",
+		Time:        "2 Jan 2006 15:04",
+		Months:      [12]string{"janvier", "février", "mars", "avril", "mai", "juin", "juillet", "août", "septembre", "octobre", "novembre", "décembre"},
+		ShortMonths: [12]string{"janv.", "févr.", "mars", "avr.", "mai", "juin", "juil.", "août", "sept.", "oct.", "nov.", "déc."},
+		Days:        [7]string{"dimanche", "lundi", "mardi", "mercredi", "jeudi", "vendredi", "samedi"},
+		ShortDays:   [7]string{"dim.", "lun.", "mar.", "mer.", "jeu.", "ven.", "sam."},
+	},
+}
+
+var formatsMu = &sync.RWMutex{}
+
+// Register the number and date format for a locale.
+func RegisterFormat(locale string, f LocaleFormat) {
+	formatsMu.Lock()
+	formats[normalize(locale)] = f
+	formatsMu.Unlock()
+}
+
+// Get the format for a locale.
+// Falls back to the format of the language, and then to English.
+func GetFormat(locale string) LocaleFormat {
+	locale = normalize(locale)
+	formatsMu.RLock()
+	defer formatsMu.RUnlock()
+	if f, ok := formats[locale]; ok {
+		return f
+	}
+	if f, ok := formats[language(locale)]; ok {
+		return f
+	}
+	return englishFormat
+}
+
+// Format a number with the default translator's locale.
+func FormatNumber(n float64) string {
+	return FormatNumberLocale(Default.Locale(), n)
+}
+
+// Format a number with the decimal and group separators of the locale.
+func FormatNumberLocale(locale string, n float64) string {
+	var f = GetFormat(locale)
+	var s = strconv.FormatFloat(n, 'f', -1, 64)
+	var neg = strings.HasPrefix(s, "-")
+	if neg {
+		s = s[1:]
+	}
+	var intPart, fracPart = s, ""
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		intPart, fracPart = s[:i], s[i+1:]
+	}
+	var sb strings.Builder
+	if neg {
+		sb.WriteByte('-')
+	}
+	for i, c := range intPart {
+		if i > 0 && (len(intPart)-i)%3 == 0 {
+			sb.WriteString(f.Group)
+		}
+		sb.WriteRune(c)
+	}
+	if fracPart != "" {
+		sb.WriteString(f.Decimal)
+		sb.WriteString(fracPart)
+	}
+	return sb.String()
+}
+
+// Format a date with the default translator's locale.
+func FormatDate(t time.Time, style DateStyle) string {
+	return FormatDateLocale(Default.Locale(), t, style)
+}
+
+// Format a date in the style of the locale.
+func FormatDateLocale(locale string, t time.Time, style DateStyle) string {
+	var f = GetFormat(locale)
+	var layout string
+	switch style {
+	case DateShort:
+		layout = f.Short
+	case DateLong:
+		layout = f.Long
+	case DateFull:
+		layout = f.Full
+	case DateTime:
+		layout = f.Time
+	default:
+		layout = f.Medium
+	}
+	var s = t.Format(layout)
+	var month, day = t.Month() - 1, t.Weekday()
+	if strings.Contains(layout, "January") {
+		s = strings.Replace(s, englishFormat.Months[month], f.Months[month], 1)
+	} else if strings.Contains(layout, "Jan") {
+		s = strings.Replace(s, englishFormat.ShortMonths[month], f.ShortMonths[month], 1)
+	}
+	if strings.Contains(layout, "Monday") {
+		s = strings.Replace(s, englishFormat.Days[day], f.Days[day], 1)
+	} else if strings.Contains(layout, "Mon") {
+		s = strings.Replace(s, englishFormat.ShortDays[day], f.ShortDays[day], 1)
+	}
+	return s
+}
@@ -0,0 +1,217 @@
+package i18n
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/Nigel2392/jsext-framework/helpers/hooks"
+)
+
+// Arguments to format a message with.
+type Args map[string]any
+
+// Catalog of messages for a single locale.
+// Messages are in the ICU message format, see Format for the supported syntax.
+type Catalog map[string]string
+
+// Translator holds the message catalogs, and the current locale.
+type Translator struct {
+	locale    string
+	fallback  string
+	catalogs  map[string]Catalog
+	listeners *hooks.Hooks[func(locale string)]
+	mu        *sync.RWMutex
+}
+
+// Default translator, used by the framework components.
+var Default = New("en")
+
+// Initialize a new translator.
+// The locale is also used as the fallback locale.
+func New(locale string) *Translator {
+	return &Translator{
+		locale:    normalize(locale),
+		fallback:  normalize(locale),
+		catalogs:  make(map[string]Catalog),
+		listeners: &hooks.Hooks[func(locale string)]{},
+		mu:        &sync.RWMutex{},
+	}
+}
+
+// Current locale of the translator.
+func (t *Translator) Locale() string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.locale
+}
+
+// Set the locale to fall back to if a message is not found in the current locale.
+func (t *Translator) SetFallback(locale string) *Translator {
+	t.mu.Lock()
+	t.fallback = normalize(locale)
+	t.mu.Unlock()
+	return t
+}
+
+// Change the current locale.
+// All functions registered with OnChange are called with the new locale.
+func (t *Translator) SetLocale(locale string) {
+	locale = normalize(locale)
+	t.mu.Lock()
+	if t.locale == locale {
+		t.mu.Unlock()
+		return
+	}
+	t.locale = locale
+	var listeners = t.listeners.List()
+	t.mu.Unlock()
+	for _, f := range listeners {
+		f(locale)
+	}
+}
+
+// Register a function to call when the locale changes.
+// Use this to re-render components.
+// The returned function removes the listener.
+func (t *Translator) OnChange(f func(locale string)) (remove func()) {
+	return t.listeners.Add(f)
+}
+
+// Add messages to the catalog of a locale.
+// Existing messages with the same key are overwritten.
+func (t *Translator) Load(locale string, catalog Catalog) *Translator {
+	locale = normalize(locale)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var c, ok = t.catalogs[locale]
+	if !ok {
+		c = make(Catalog, len(catalog))
+		t.catalogs[locale] = c
+	}
+	for k, v := range catalog {
+		c[k] = v
+	}
+	return t
+}
+
+// Add messages to the catalog of a locale from JSON.
+// Nested objects are flattened, with the keys joined by a dot.
+//
+//	{"errors": {"404": "Not found"}} -> "errors.404": "Not found"
+func (t *Translator) LoadJSON(locale string, data []byte) error {
+	var m map[string]any
+	if err := json.Unmarshal(data, &m); err != nil {
+		return err
+	}
+	var catalog = make(Catalog)
+	flatten(catalog, "", m)
+	t.Load(locale, catalog)
+	return nil
+}
+
+// Locales which have a catalog loaded.
+func (t *Translator) Locales() []string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	var locales = make([]string, 0, len(t.catalogs))
+	for locale := range t.catalogs {
+		locales = append(locales, locale)
+	}
+	return locales
+}
+
+// Check if a message exists for the current locale, or its fallbacks.
+func (t *Translator) Has(key string) bool {
+	var _, _, ok = t.lookup(key)
+	return ok
+}
+
+// Translate a message, and format it with the arguments.
+// If the message is not found, the key is returned.
+func (t *Translator) T(key string, args ...Args) string {
+	var msg, locale, ok = t.lookup(key)
+	if !ok {
+		return key
+	}
+	var a Args
+	if len(args) > 0 {
+		a = args[0]
+	}
+	var s, err = FormatLocale(locale, msg, a)
+	if err != nil {
+		return msg
+	}
+	return s
+}
+
+// Find a message for the current locale.
+// Looks in the current locale, the language of the current locale, and the fallback locale.
+// Returns the message and the locale the message was found in.
+func (t *Translator) lookup(key string) (string, string, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	for _, locale := range [...]string{t.locale, language(t.locale), t.fallback} {
+		if msg, ok := t.catalogs[locale][key]; ok {
+			return msg, locale, true
+		}
+	}
+	return "", "", false
+}
+
+// Shorthand for Default.T
+func T(key string, args ...Args) string {
+	return Default.T(key, args...)
+}
+
+// Shorthand for Default.SetLocale
+func SetLocale(locale string) {
+	Default.SetLocale(locale)
+}
+
+// Shorthand for Default.Locale
+func Locale() string {
+	return Default.Locale()
+}
+
+// Shorthand for Default.OnChange
+func OnChange(f func(locale string)) (remove func()) {
+	return Default.OnChange(f)
+}
+
+// Normalize a locale to lowercase with dashes.
+//
+//	"en_US" -> "en-us"
+func normalize(locale string) string {
+	return strings.ToLower(strings.ReplaceAll(locale, "_", "-"))
+}
+
+// Get the language part of a locale.
+//
+//	"en-us" -> "en"
+func language(locale string) string {
+	if i := strings.IndexByte(locale, '-'); i > 0 {
+		return locale[:i]
+	}
+	return locale
+}
+
+func flatten(catalog Catalog, prefix string, m map[string]any) {
+	for k, v := range m {
+		var key = k
+		if prefix != "" {
+			key = prefix + "." + k
+		}
+		switch v := v.(type) {
+		case string:
+			catalog[key] = v
+		case map[string]any:
+			flatten(catalog, key, v)
+		case float64:
+			catalog[key] = strconv.FormatFloat(v, 'f', -1, 64)
+		case bool:
+			catalog[key] = strconv.FormatBool(v)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package i18n_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Nigel2392/jsext-framework/i18n"
+)
+
+func TestFormat(t *testing.T) {
+	var tests = []struct {
+		locale string
+		msg    string
+		args   i18n.Args
+		want   string
+	}{
+		{"en", "Hello, {name}!", i18n.Args{"name": "World"}, "Hello, World!"},
+		{"en", "{n, plural, =0 {no items} one {# item} other {# items}}", i18n.Args{"n": 0}, "no items"},
+		{"en", "{n, plural, =0 {no items} one {# item} other {# items}}", i18n.Args{"n": 1}, "1 item"},
+		{"en", "{n, plural, =0 {no items} one {# item} other {# items}}", i18n.Args{"n": 1200}, "1,200 items"},
+		{"nl", "{n, plural, one {# item} other {# items}}", i18n.Args{"n": 1200.5}, "1.200,5 items"},
+		{"ru", "{n, plural, one {# файл} few {# файла} many {# файлов} other {# файла}}", i18n.Args{"n": 22}, "22 файла"},
+		{"ru", "{n, plural, one {# файл} few {# файла} many {# файлов} other {# файла}}", i18n.Args{"n": 11}, "11 файлов"},
+		{"en", "{n, selectordinal, one {#st} two {#nd} few {#rd} other {#th}}", i18n.Args{"n": 23}, "23rd"},
+		{"en", "{g, select, male {He} female {She} other {They}} replied", i18n.Args{"g": "female"}, "She replied"},
+		{"en", "{g, select, male {He} female {She} other {They}} replied", i18n.Args{"g": "x"}, "They replied"},
+		{"en", "It''s '{literal}'", nil, "It's {literal}"},
+	}
+	for _, test := range tests {
+		var got, err = i18n.FormatLocale(test.locale, test.msg, test.args)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %s", test.msg, err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("%q: expected %q, got %q", test.msg, test.want, got)
+		}
+	}
+}
+
+func TestFormatErrors(t *testing.T) {
+	for _, msg := range []string{"{name", "name}", "{n, plural, one {#}}", "{missing}"} {
+		if _, err := i18n.FormatLocale("en", msg, i18n.Args{"n": 2, "name": "x"}); err == nil {
+			t.Errorf("%q: expected an error", msg)
+		}
+	}
+}
+
+func TestTranslator(t *testing.T) {
+	var tr = i18n.New("en")
+	if err := tr.LoadJSON("en", []byte(`{"greeting": {"hello": "Hello, {name}"}}`)); err != nil {
+		t.Fatal(err)
+	}
+	tr.Load("nl", i18n.Catalog{"greeting.hello": "Hallo, {name}"})
+
+	var changed string
+	var remove = tr.OnChange(func(locale string) { changed = locale })
+
+	if got := tr.T("greeting.hello", i18n.Args{"name": "Bob"}); got != "Hello, Bob" {
+		t.Errorf("expected %q, got %q", "Hello, Bob", got)
+	}
+	tr.SetLocale("nl_NL")
+	if changed != "nl-nl" {
+		t.Errorf("expected listener to be called with %q, got %q", "nl-nl", changed)
+	}
+	if got := tr.T("greeting.hello", i18n.Args{"name": "Bob"}); got != "Hallo, Bob" {
+		t.Errorf("expected %q, got %q", "Hallo, Bob", got)
+	}
+	remove()
+	tr.SetLocale("de")
+	if changed != "nl-nl" {
+		t.Error("removed listener was called")
+	}
+	if got := tr.T("greeting.hello", i18n.Args{"name": "Bob"}); got != "Hello, Bob" {
+		t.Errorf("expected fallback %q, got %q", "Hello, Bob", got)
+	}
+	if got := tr.T("missing.key"); got != "missing.key" {
+		t.Errorf("expected the key for a missing message, got %q", got)
+	}
+}
+
+func TestFormatDate(t *testing.T) {
+	var date = time.Date(2023, time.March, 5, 14, 30, 0, 0, time.UTC)
+	var tests = []struct {
+		locale string
+		style  i18n.DateStyle
+		want   string
+	}{
+		{"en", i18n.DateShort, "3/5/23"},
+		{"en", i18n.DateLong, "March 5, 2023"},
+		{"en-US", i18n.DateFull, "Sunday, March 5, 2023"},
+		{"nl", i18n.DateMedium, "5 mrt 2023"},
+		{"nl", i18n.DateFull, "zondag 5 maart 2023"},
+		{"de", i18n.DateLong, "5. März 2023"},
+	}
+	for _, test := range tests {
+		if got := i18n.FormatDateLocale(test.locale, date, test.style); got != test.want {
+			t.Errorf("%s: expected %q, got %q", test.locale, test.want, got)
+		}
+	}
+}
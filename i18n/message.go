@@ -0,0 +1,297 @@
+package i18n
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Format a message in the ICU message format with the default translator's locale.
+func Format(msg string, args Args) (string, error) {
+	return FormatLocale(Default.Locale(), msg, args)
+}
+
+// Format a message in the ICU message format.
+//
+// The following syntax is supported:
+//
+//	{name}                                  -> value of the argument
+//	{count, number}                         -> locale-aware number
+//	{when, date}                            -> locale-aware date, optionally: short, medium, long or full
+//	{count, plural, =0 {none} one {# item} other {# items}}
+//	{place, selectordinal, one {#st} two {#nd} few {#rd} other {#th}}
+//	{gender, select, male {he} female {she} other {they}}
+//
+// Inside of plural messages, # is replaced with the formatted number.
+// Quote special characters with apostrophes, like so: '{' '}' '#'.
+// Two apostrophes in a row produce a literal apostrophe.
+func FormatLocale(locale, msg string, args Args) (string, error) {
+	var f = &formatter{locale: normalize(locale), args: args}
+	var sb strings.Builder
+	if err := f.format(&sb, msg, nil); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
+
+type formatter struct {
+	locale string
+	args   Args
+}
+
+func (f *formatter) format(sb *strings.Builder, msg string, num *float64) error {
+	for i := 0; i < len(msg); i++ {
+		switch c := msg[i]; c {
+		case '\'':
+			if i+1 < len(msg) && msg[i+1] == '\'' {
+				sb.WriteByte('\'')
+				i++
+			} else if i+1 < len(msg) && (msg[i+1] == '{' || msg[i+1] == '}' || msg[i+1] == '#') {
+				var end = strings.IndexByte(msg[i+1:], '\'')
+				if end < 0 {
+					sb.WriteString(msg[i+1:])
+					return nil
+				}
+				sb.WriteString(msg[i+1 : i+1+end])
+				i += end + 1
+			} else {
+				sb.WriteByte(c)
+			}
+		case '{':
+			var end, err = matchBrace(msg, i)
+			if err != nil {
+				return err
+			}
+			if err = f.argument(sb, msg[i+1:end]); err != nil {
+				return err
+			}
+			i = end
+		case '}':
+			return errors.New("unexpected } at position " + strconv.Itoa(i))
+		case '#':
+			if num != nil {
+				sb.WriteString(FormatNumberLocale(f.locale, *num))
+			} else {
+				sb.WriteByte(c)
+			}
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return nil
+}
+
+// Format a single argument, without the surrounding braces.
+func (f *formatter) argument(sb *strings.Builder, arg string) error {
+	var parts = splitTop(arg, 3)
+	var name = strings.TrimSpace(parts[0])
+	var value, ok = f.args[name]
+	if !ok {
+		return errors.New("missing argument: " + name)
+	}
+	if len(parts) == 1 {
+		sb.WriteString(f.stringify(value, ""))
+		return nil
+	}
+	var typ = strings.TrimSpace(parts[1])
+	var style string
+	if len(parts) == 3 {
+		style = strings.TrimSpace(parts[2])
+	}
+	switch typ {
+	case "number", "date", "time":
+		sb.WriteString(f.stringify(value, style))
+		return nil
+	case "plural", "selectordinal":
+		var n, ok = toFloat(value)
+		if !ok {
+			return fmt.Errorf("argument %s is not a number", name)
+		}
+		var options, err = parseOptions(style)
+		if err != nil {
+			return err
+		}
+		if msg, ok := options["="+strconv.FormatFloat(n, 'f', -1, 64)]; ok {
+			return f.format(sb, msg, &n)
+		}
+		var category string
+		if typ == "plural" {
+			category = PluralCategory(f.locale, n)
+		} else {
+			category = OrdinalCategory(f.locale, n)
+		}
+		if msg, ok := options[category]; ok {
+			return f.format(sb, msg, &n)
+		}
+		if msg, ok := options["other"]; ok {
+			return f.format(sb, msg, &n)
+		}
+		return errors.New("plural argument " + name + " has no other option")
+	case "select":
+		var options, err = parseOptions(style)
+		if err != nil {
+			return err
+		}
+		if msg, ok := options[f.stringify(value, "")]; ok {
+			return f.format(sb, msg, nil)
+		}
+		if msg, ok := options["other"]; ok {
+			return f.format(sb, msg, nil)
+		}
+		return errors.New("select argument " + name + " has no other option")
+	}
+	return errors.New("unknown argument type: " + typ)
+}
+
+// Convert an argument to a string.
+func (f *formatter) stringify(value any, style string) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case time.Time:
+		var ds = DateMedium
+		switch style {
+		case "short":
+			ds = DateShort
+		case "long":
+			ds = DateLong
+		case "full":
+			ds = DateFull
+		}
+		return FormatDateLocale(f.locale, v, ds)
+	case fmt.Stringer:
+		return v.String()
+	case error:
+		return v.Error()
+	}
+	if n, ok := toFloat(value); ok {
+		if style == "integer" {
+			return FormatNumberLocale(f.locale, float64(int64(n)))
+		}
+		return FormatNumberLocale(f.locale, n)
+	}
+	return fmt.Sprint(value)
+}
+
+// Parse plural and select options.
+//
+//	one {# item} other {# items} -> {"one": "# item", "other": "# items"}
+func parseOptions(s string) (map[string]string, error) {
+	var options = make(map[string]string)
+	var i = 0
+	for {
+		for i < len(s) && isSpace(s[i]) {
+			i++
+		}
+		if i >= len(s) {
+			break
+		}
+		var start = i
+		for i < len(s) && !isSpace(s[i]) && s[i] != '{' {
+			i++
+		}
+		var selector = s[start:i]
+		// Skip the plural offset, it is not supported.
+		if strings.HasPrefix(selector, "offset:") {
+			continue
+		}
+		for i < len(s) && isSpace(s[i]) {
+			i++
+		}
+		if i >= len(s) || s[i] != '{' {
+			return nil, errors.New("expected { after option " + selector)
+		}
+		var end, err = matchBrace(s, i)
+		if err != nil {
+			return nil, err
+		}
+		options[selector] = s[i+1 : end]
+		i = end + 1
+	}
+	return options, nil
+}
+
+// Find the closing brace for the opening brace at position start.
+func matchBrace(s string, start int) (int, error) {
+	var depth = 0
+	var quoted = false
+	for i := start; i < len(s); i++ {
+		switch s[i] {
+		case '\'':
+			if i+1 < len(s) && s[i+1] == '\'' {
+				i++
+			} else if quoted || (i+1 < len(s) && (s[i+1] == '{' || s[i+1] == '}' || s[i+1] == '#')) {
+				quoted = !quoted
+			}
+		case '{':
+			if !quoted {
+				depth++
+			}
+		case '}':
+			if !quoted {
+				depth--
+				if depth == 0 {
+					return i, nil
+				}
+			}
+		}
+	}
+	return 0, errors.New("unclosed { at position " + strconv.Itoa(start))
+}
+
+// Split on commas which are not inside of braces, into at most n parts.
+func splitTop(s string, n int) []string {
+	var parts = make([]string, 0, n)
+	var depth = 0
+	var last = 0
+	for i := 0; i < len(s) && len(parts) < n-1; i++ {
+		switch s[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, s[last:i])
+				last = i + 1
+			}
+		}
+	}
+	return append(parts, s[last:])
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+func toFloat(value any) (float64, bool) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), true
+	case int8:
+		return float64(v), true
+	case int16:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint:
+		return float64(v), true
+	case uint8:
+		return float64(v), true
+	case uint16:
+		return float64(v), true
+	case uint32:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case float32:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
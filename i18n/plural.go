@@ -0,0 +1,179 @@
+package i18n
+
+import "math"
+
+// Plural categories, as defined by the Unicode CLDR.
+const (
+	Zero  = "zero"
+	One   = "one"
+	Two   = "two"
+	Few   = "few"
+	Many  = "many"
+	Other = "other"
+)
+
+// Rule to get the plural category of a number.
+type PluralRule func(n float64) string
+
+// Cardinal plural rules per language, based on the Unicode CLDR.
+// Rules can be added or overridden for other languages.
+var PluralRules = map[string]PluralRule{
+	"en": pluralOne,
+	"nl": pluralOne,
+	"de": pluralOne,
+	"es": pluralOne,
+	"it": pluralOne,
+	"sv": pluralOne,
+	"da": pluralOne,
+	"nb": pluralOne,
+	"fi": pluralOne,
+	"el": pluralOne,
+	"tr": pluralOne,
+	"fr": pluralZeroOne,
+	"pt": pluralZeroOne,
+	"ru": pluralSlavic,
+	"uk": pluralSlavic,
+	"pl": pluralPolish,
+	"cs": pluralCzech,
+	"sk": pluralCzech,
+	"ar": pluralArabic,
+	"ja": pluralNone,
+	"zh": pluralNone,
+	"ko": pluralNone,
+	"th": pluralNone,
+	"vi": pluralNone,
+	"id": pluralNone,
+}
+
+// Ordinal plural rules per language, based on the Unicode CLDR.
+// Languages without a rule always use the "other" category.
+var OrdinalRules = map[string]PluralRule{
+	"en": ordinalEnglish,
+	"fr": func(n float64) string {
+		if n == 1 {
+			return One
+		}
+		return Other
+	},
+}
+
+// Get the plural category of a number for the locale.
+// Falls back to the rule for the language, and then to the English rule.
+func PluralCategory(locale string, n float64) string {
+	return findRule(PluralRules, locale, pluralOne)(n)
+}
+
+// Get the ordinal category of a number for the locale.
+func OrdinalCategory(locale string, n float64) string {
+	return findRule(OrdinalRules, locale, pluralNone)(n)
+}
+
+func findRule(rules map[string]PluralRule, locale string, def PluralRule) PluralRule {
+	locale = normalize(locale)
+	if rule, ok := rules[locale]; ok {
+		return rule
+	}
+	if rule, ok := rules[language(locale)]; ok {
+		return rule
+	}
+	return def
+}
+
+func isInt(n float64) bool {
+	return n == math.Trunc(n)
+}
+
+func mod(n float64, m int64) int64 {
+	return int64(math.Abs(n)) % m
+}
+
+func pluralNone(n float64) string {
+	return Other
+}
+
+func pluralOne(n float64) string {
+	if n == 1 {
+		return One
+	}
+	return Other
+}
+
+func pluralZeroOne(n float64) string {
+	if n >= 0 && n < 2 {
+		return One
+	}
+	return Other
+}
+
+func pluralSlavic(n float64) string {
+	if !isInt(n) {
+		return Other
+	}
+	var n10, n100 = mod(n, 10), mod(n, 100)
+	switch {
+	case n10 == 1 && n100 != 11:
+		return One
+	case n10 >= 2 && n10 <= 4 && (n100 < 12 || n100 > 14):
+		return Few
+	}
+	return Many
+}
+
+func pluralPolish(n float64) string {
+	if !isInt(n) {
+		return Other
+	}
+	var n10, n100 = mod(n, 10), mod(n, 100)
+	switch {
+	case n == 1:
+		return One
+	case n10 >= 2 && n10 <= 4 && (n100 < 12 || n100 > 14):
+		return Few
+	}
+	return Many
+}
+
+func pluralCzech(n float64) string {
+	switch {
+	case !isInt(n):
+		return Many
+	case n == 1:
+		return One
+	case n >= 2 && n <= 4:
+		return Few
+	}
+	return Other
+}
+
+func pluralArabic(n float64) string {
+	if !isInt(n) {
+		return Other
+	}
+	var n100 = mod(n, 100)
+	switch {
+	case n == 0:
+		return Zero
+	case n == 1:
+		return One
+	case n == 2:
+		return Two
+	case n100 >= 3 && n100 <= 10:
+		return Few
+	case n100 >= 11:
+		return Many
+	}
+	return Other
+}
+
+func ordinalEnglish(n float64) string {
+	var n10, n100 = mod(n, 10), mod(n, 100)
+	switch {
+	case n10 == 1 && n100 != 11:
+		return One
+	case n10 == 2 && n100 != 12:
+		return Two
+	case n10 == 3 && n100 != 13:
+		return Few
+	}
+	return Other
+}
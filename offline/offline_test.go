@@ -0,0 +1,253 @@
+package offline_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Nigel2392/jsext-framework/client"
+	"github.com/Nigel2392/jsext-framework/offline"
+)
+
+// Server recording the bodies of the requests it received.
+type server struct {
+	*httptest.Server
+	bodies []string
+	status func(body string) int
+	mu     sync.Mutex
+}
+
+func newServer(status func(body string) int) *server {
+	var s = &server{status: status}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var b, _ = io.ReadAll(r.Body)
+		s.mu.Lock()
+		s.bodies = append(s.bodies, string(b))
+		s.mu.Unlock()
+		w.WriteHeader(s.status(string(b)))
+	}))
+	return s
+}
+
+func (s *server) received() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.bodies...)
+}
+
+func ok(string) int { return http.StatusOK }
+
+func TestQueueWhileOffline(t *testing.T) {
+	var srv = newServer(ok)
+	defer srv.Close()
+
+	var status = offline.NewStatus(true)
+	var replayed = make(chan string, 2)
+	var q, err = offline.NewQueue(&offline.QueueOptions{
+		Status:     status,
+		OnReplayed: func(r *offline.Request, resp *http.Response) { replayed <- string(r.Body) },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var c = client.New(client.BaseURL(srv.URL), q.Interceptor())
+
+	status.Simulate(true)
+	for _, body := range []string{"first", "second"} {
+		var resp, err = c.Do(mustRequest(t, c, http.MethodPost, body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !offline.IsQueued(resp) || resp.StatusCode != http.StatusAccepted {
+			t.Errorf("expected a queued response, got %d", resp.StatusCode)
+		}
+	}
+	if err := c.Get(context.Background(), "/", nil); err != nil {
+		t.Errorf("expected GET requests not to be queued, got %s", err)
+	}
+	if q.Len() != 2 {
+		t.Fatalf("expected 2 queued requests, got %d", q.Len())
+	}
+	if got := srv.received(); len(got) != 1 {
+		t.Errorf("expected only the GET request to reach the server, got %v", got)
+	}
+
+	// Going online replays the queue in order.
+	status.Simulate(false)
+	for _, want := range []string{"first", "second"} {
+		select {
+		case got := <-replayed:
+			if got != want {
+				t.Errorf("expected %q to be replayed, got %q", want, got)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("expected %q to be replayed", want)
+		}
+	}
+	waitFor(t, func() bool { return q.Len() == 0 })
+}
+
+func TestReplayConflict(t *testing.T) {
+	var srv = newServer(func(body string) int {
+		if body == "stale" {
+			return http.StatusConflict
+		}
+		return http.StatusOK
+	})
+	defer srv.Close()
+
+	var status = offline.NewStatus(false)
+	var conflicts int
+	var q, _ = offline.NewQueue(&offline.QueueOptions{
+		Status: status,
+		OnConflict: func(r *offline.Request, resp *http.Response) offline.Resolution {
+			conflicts++
+			if conflicts == 1 {
+				return offline.Keep
+			}
+			r.Body = []byte("merged")
+			return offline.Retry
+		},
+	})
+	var c = client.New(client.BaseURL(srv.URL), q.Interceptor())
+	c.Do(mustRequest(t, c, http.MethodPut, "stale"))
+	c.Do(mustRequest(t, c, http.MethodPut, "next"))
+
+	// Replay manually instead of when the status changes.
+	q.Close()
+	status.Set(true)
+	if err := q.Replay(context.Background()); err != offline.ErrConflict {
+		t.Fatalf("expected the conflict to stop replaying, got %v", err)
+	}
+	if q.Len() != 2 {
+		t.Fatalf("expected the conflicting request to be kept, got %d queued", q.Len())
+	}
+
+	if err := q.Replay(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if q.Len() != 0 {
+		t.Errorf("expected the queue to be empty, got %d", q.Len())
+	}
+	var got = strings.Join(srv.received(), ",")
+	if !strings.HasSuffix(got, "stale,merged,next") {
+		t.Errorf("unexpected requests: %s", got)
+	}
+}
+
+func TestQueueOnNetworkError(t *testing.T) {
+	var srv = newServer(ok)
+	var url = srv.URL
+	srv.Close()
+
+	var q, _ = offline.NewQueue(&offline.QueueOptions{QueueOnNetworkError: true})
+	var c = client.New(client.BaseURL(url), q.Interceptor())
+	var resp, err = c.Do(mustRequest(t, c, http.MethodPost, "body"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !offline.IsQueued(resp) {
+		t.Error("expected the request to be queued on a network error")
+	}
+	if err := q.Replay(context.Background()); err == nil {
+		t.Error("expected replaying to fail while the server is down")
+	}
+	if q.Len() != 1 || string(q.Requests()[0].Body) != "body" {
+		t.Error("expected the request to stay queued")
+	}
+}
+
+func TestQueueStripsCredentials(t *testing.T) {
+	var auth = make(chan string, 1)
+	var srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth <- r.Header.Get("Authorization")
+	}))
+	defer srv.Close()
+
+	var store = &offline.MemoryStore{}
+	var status = offline.NewStatus(false)
+	var q, _ = offline.NewQueue(&offline.QueueOptions{
+		Store:  store,
+		Status: status,
+		Authorize: func(next http.RoundTripper) http.RoundTripper {
+			return client.RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+				r.Header.Set("Authorization", "Bearer new")
+				return next.RoundTrip(r)
+			})
+		},
+	})
+	q.Close()
+	var c = client.New(client.BaseURL(srv.URL), q.Interceptor())
+	var r = mustRequest(t, c, http.MethodPost, "body")
+	r.Header.Set("Authorization", "Bearer old")
+	r.Header.Set("Cookie", "session=old")
+	r.Header.Set("X-Request", "kept")
+	c.Do(r)
+
+	var requests, _ = store.Load()
+	if len(requests) != 1 {
+		t.Fatalf("expected 1 stored request, got %d", len(requests))
+	}
+	var header = requests[0].Header
+	if header.Get("Authorization") != "" || header.Get("Cookie") != "" || header.Get("X-Request") != "kept" {
+		t.Errorf("unexpected stored headers: %v", header)
+	}
+
+	status.Set(true)
+	if err := q.Replay(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if got := <-auth; got != "Bearer new" {
+		t.Errorf("expected the replayed request to be authorized again, got %q", got)
+	}
+}
+
+func TestServiceWorkerHandler(t *testing.T) {
+	var mux = http.NewServeMux()
+	mux.Handle("/sw.js", offline.ServiceWorkerHandler(&offline.ServiceWorkerOptions{
+		Version:  "v2",
+		Precache: []string{"/app.html", "/main.wasm"},
+		Runtime:  []string{"/static/"},
+	}))
+	var srv = httptest.NewServer(mux)
+	defer srv.Close()
+
+	var resp, err = http.Get(srv.URL + "/sw.js")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var b, _ = io.ReadAll(resp.Body)
+	if ct := resp.Header.Get("Content-Type"); ct != "application/javascript" {
+		t.Errorf("unexpected content type %q", ct)
+	}
+	for _, want := range []string{`"jsext-v2"`, `["/app.html","/main.wasm"]`, `const FALLBACK = "/app.html"`, `["/static/"]`} {
+		if !strings.Contains(string(b), want) {
+			t.Errorf("expected %s in the service worker", want)
+		}
+	}
+}
+
+func mustRequest(t *testing.T, c *client.Client, method, body string) *http.Request {
+	var r, err = c.NewRequest(context.Background(), method, "/items", body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	var deadline = time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
@@ -0,0 +1,87 @@
+//go:build js && wasm
+// +build js,wasm
+
+package offline
+
+import (
+	"encoding/json"
+	"errors"
+	"syscall/js"
+
+	"github.com/Nigel2392/jsext"
+)
+
+// Update the status with the online and offline events of the browser.
+// The returned function stops listening.
+func WatchBrowser(s *Status) (stop func()) {
+	s.Set(jsext.Global.Get("navigator").Get("onLine").Bool())
+	var onOnline = js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		go s.Set(true)
+		return nil
+	})
+	var onOffline = js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		go s.Set(false)
+		return nil
+	})
+	var window = js.Value(jsext.Window)
+	window.Call("addEventListener", "online", onOnline)
+	window.Call("addEventListener", "offline", onOffline)
+	return func() {
+		window.Call("removeEventListener", "online", onOnline)
+		window.Call("removeEventListener", "offline", onOffline)
+		onOnline.Release()
+		onOffline.Release()
+	}
+}
+
+// Register the service worker at the script url.
+// Blocks until the registration has finished, do not call this from a javascript callback.
+func RegisterServiceWorker(scriptURL string) error {
+	var container = jsext.Global.Get("navigator").Get("serviceWorker")
+	if container.IsUndefined() {
+		return errors.New("offline: service workers are not supported, the page must be served over https or from localhost")
+	}
+	var done = make(chan error, 1)
+	var then, catch js.Func
+	then = js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		done <- nil
+		return nil
+	})
+	catch = js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		done <- errors.New("offline: could not register service worker: " + args[0].Call("toString").String())
+		return nil
+	})
+	container.Call("register", scriptURL).Call("then", then).Call("catch", catch)
+	var err = <-done
+	then.Release()
+	catch.Release()
+	return err
+}
+
+// Store which persists the queue in localStorage under the key.
+type LocalStorage string
+
+func (s LocalStorage) Load() ([]*Request, error) {
+	var v = jsext.Global.Get("localStorage").Call("getItem", string(s))
+	if v.IsNull() || v.IsUndefined() {
+		return nil, nil
+	}
+	var requests []*Request
+	if err := json.Unmarshal([]byte(v.String()), &requests); err != nil {
+		return nil, err
+	}
+	return requests, nil
+}
+
+func (s LocalStorage) Save(requests []*Request) error {
+	if len(requests) == 0 {
+		jsext.Global.Get("localStorage").Call("removeItem", string(s))
+		return nil
+	}
+	var data, err = json.Marshal(requests)
+	if err != nil {
+		return err
+	}
+	jsext.Global.Get("localStorage").Call("setItem", string(s), string(data))
+	return nil
+}
@@ -0,0 +1,427 @@
+package offline
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Nigel2392/jsext-framework/client"
+)
+
+// Header set on the response of a request which was queued instead of sent.
+const QueuedHeader = "X-Offline-Queued"
+
+// Returned by Replay when replaying was stopped because a conflict was kept in the queue.
+var ErrConflict = errors.New("offline: replay stopped on conflict")
+
+// Returned by Replay when the queue is already being replayed.
+var ErrReplaying = errors.New("offline: queue is already being replayed")
+
+// A request which was queued while offline.
+type Request struct {
+	ID       string
+	Method   string
+	URL      string
+	Header   http.Header
+	Body     []byte
+	QueuedAt time.Time
+	// Amount of times replaying the request was attempted.
+	Attempts int
+}
+
+// Create a new http request for the queued request.
+func (r *Request) HTTPRequest(ctx context.Context) (*http.Request, error) {
+	var req, err = http.NewRequestWithContext(ctx, r.Method, r.URL, bytes.NewReader(r.Body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header = r.Header.Clone()
+	if req.Header == nil {
+		req.Header = make(http.Header)
+	}
+	return req, nil
+}
+
+// Resolution of a conflict while replaying.
+type Resolution int
+
+const (
+	// Remove the request from the queue, and continue replaying.
+	Discard Resolution = iota
+	// Keep the request in the queue, and stop replaying.
+	Keep
+	// Send the request again, the conflict function may have changed it.
+	Retry
+)
+
+// Persistent storage for the queue.
+type Store interface {
+	Load() ([]*Request, error)
+	Save([]*Request) error
+}
+
+// Store which keeps the queue in memory.
+type MemoryStore struct {
+	requests []*Request
+	mu       sync.Mutex
+}
+
+func (s *MemoryStore) Load() ([]*Request, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*Request(nil), s.requests...), nil
+}
+
+func (s *MemoryStore) Save(requests []*Request) error {
+	s.mu.Lock()
+	s.requests = append([]*Request(nil), requests...)
+	s.mu.Unlock()
+	return nil
+}
+
+// Options for the request queue.
+type QueueOptions struct {
+	// Where the queue is persisted, defaults to memory.
+	Store Store
+	// Network status, requests are queued while it is offline.
+	// Defaults to a status which is always online.
+	Status *Status
+	// Methods of requests to queue.
+	// Defaults to POST, PUT, PATCH and DELETE.
+	Methods []string
+	// Also queue requests which fail with a network error while online.
+	QueueOnNetworkError bool
+	// Status codes which are treated as a conflict while replaying.
+	// Defaults to 409 and 412.
+	ConflictStatus []int
+	// Maximum amount of retries of a single conflict.
+	MaxRetries int
+	// Decide what happens with a request which conflicts while replaying.
+	// The response body is closed after the function returns.
+	// Defaults to discarding the request.
+	OnConflict func(r *Request, resp *http.Response) Resolution
+	// Called when a request is queued.
+	OnQueued func(r *Request)
+	// Called when a queued request was replayed successfully.
+	OnReplayed func(r *Request, resp *http.Response)
+	// Called when a queued request was rejected by the server, the request is removed from the queue.
+	OnFailed func(r *Request, resp *http.Response)
+	// Headers which are removed before a request is queued, so credentials are not persisted.
+	// Defaults to Authorization, Proxy-Authorization and Cookie.
+	StripHeaders []string
+	// Interceptor which authorizes replayed requests, such as the interceptor of a token.
+	// Only needed when the queue is added after the authentication interceptor,
+	// otherwise the authentication interceptor already runs when a request is replayed.
+	Authorize client.Interceptor
+}
+
+func (o *QueueOptions) SetDefaults() {
+	if o.Store == nil {
+		o.Store = &MemoryStore{}
+	}
+	if o.Status == nil {
+		o.Status = NewStatus(true)
+	}
+	if o.Methods == nil {
+		o.Methods = []string{http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}
+	}
+	if o.ConflictStatus == nil {
+		o.ConflictStatus = []int{http.StatusConflict, http.StatusPreconditionFailed}
+	}
+	if o.MaxRetries == 0 {
+		o.MaxRetries = 3
+	}
+	if o.StripHeaders == nil {
+		o.StripHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie"}
+	}
+	if o.OnConflict == nil {
+		o.OnConflict = func(r *Request, resp *http.Response) Resolution {
+			return Discard
+		}
+	}
+}
+
+// Queue for mutating requests made while offline.
+//
+// Queued requests are replayed in order with Replay,
+// by default this happens when the network status changes to online.
+type Queue struct {
+	opts      QueueOptions
+	requests  []*Request
+	transport http.RoundTripper
+	replaying bool
+	nextID    int
+	remove    func()
+	mu        *sync.Mutex
+}
+
+// Initialize a new queue, loading the persisted requests from the store.
+// Queued requests are replayed automatically when the network status changes to online.
+func NewQueue(opts *QueueOptions) (*Queue, error) {
+	var q = &Queue{mu: &sync.Mutex{}}
+	if opts != nil {
+		q.opts = *opts
+	}
+	q.opts.SetDefaults()
+	var requests, err = q.opts.Store.Load()
+	if err != nil {
+		return nil, err
+	}
+	q.requests = requests
+	q.remove = q.opts.Status.OnChange(func(online bool) {
+		if online {
+			go q.Replay(context.Background())
+		}
+	})
+	return q, nil
+}
+
+// Stop replaying automatically when the network status changes.
+func (q *Queue) Close() {
+	q.remove()
+}
+
+// Network status of the queue.
+func (q *Queue) Status() *Status {
+	return q.opts.Status
+}
+
+// Amount of queued requests.
+func (q *Queue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.requests)
+}
+
+// Queued requests, in the order they will be replayed.
+func (q *Queue) Requests() []*Request {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return append([]*Request(nil), q.requests...)
+}
+
+// Remove all queued requests.
+func (q *Queue) Clear() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.requests = nil
+	return q.opts.Store.Save(q.requests)
+}
+
+// Interceptor to queue requests in a client.
+// Replayed requests are sent through the interceptors after the queue.
+//
+// Credentials are stripped before a request is queued, add the queue before the authentication interceptor
+// so replayed requests are authorized with the current token, or set QueueOptions.Authorize.
+func (q *Queue) Interceptor() client.Interceptor {
+	return func(next http.RoundTripper) http.RoundTripper {
+		q.mu.Lock()
+		q.transport = next
+		q.mu.Unlock()
+		return client.RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			if !q.queues(r.Method) {
+				return next.RoundTrip(r)
+			}
+			if !q.opts.Status.Online() {
+				return q.enqueue(r)
+			}
+			if !q.opts.QueueOnNetworkError {
+				return next.RoundTrip(r)
+			}
+			// Keep a copy of the body, the transport consumes it.
+			var body, err = readBody(r)
+			if err != nil {
+				return nil, err
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			resp, err := next.RoundTrip(r)
+			if err != nil && r.Context().Err() == nil {
+				r.Body = io.NopCloser(bytes.NewReader(body))
+				return q.enqueue(r)
+			}
+			return resp, err
+		})
+	}
+}
+
+// Check if a response is the placeholder for a queued request.
+func IsQueued(resp *http.Response) bool {
+	return resp != nil && resp.Header.Get(QueuedHeader) != ""
+}
+
+func (q *Queue) queues(method string) bool {
+	for _, m := range q.opts.Methods {
+		if strings.EqualFold(m, method) {
+			return true
+		}
+	}
+	return false
+}
+
+// Add the request to the queue, and return a placeholder response.
+func (q *Queue) enqueue(r *http.Request) (*http.Response, error) {
+	var body, err = readBody(r)
+	if err != nil {
+		return nil, err
+	}
+	var header = r.Header.Clone()
+	for _, h := range q.opts.StripHeaders {
+		header.Del(h)
+	}
+	q.mu.Lock()
+	q.nextID++
+	var req = &Request{
+		ID:       strconv.FormatInt(time.Now().UnixNano(), 36) + "-" + strconv.Itoa(q.nextID),
+		Method:   r.Method,
+		URL:      r.URL.String(),
+		Header:   header,
+		Body:     body,
+		QueuedAt: time.Now(),
+	}
+	q.requests = append(q.requests, req)
+	err = q.opts.Store.Save(q.requests)
+	q.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	if q.opts.OnQueued != nil {
+		q.opts.OnQueued(req)
+	}
+	return &http.Response{
+		Status:     "202 Accepted",
+		StatusCode: http.StatusAccepted,
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     http.Header{QueuedHeader: []string{req.ID}},
+		Body:       http.NoBody,
+		Request:    r,
+	}, nil
+}
+
+// Replay the queued requests in order.
+//
+// Replaying stops at the first network error or server error, the request stays queued.
+// Conflicts are resolved with OnConflict, other rejected requests are removed and passed to OnFailed.
+func (q *Queue) Replay(ctx context.Context) error {
+	q.mu.Lock()
+	if q.replaying {
+		q.mu.Unlock()
+		return ErrReplaying
+	}
+	if q.transport == nil {
+		q.transport = http.DefaultTransport
+	}
+	q.replaying = true
+	var transport = q.transport
+	q.mu.Unlock()
+	if q.opts.Authorize != nil {
+		transport = q.opts.Authorize(transport)
+	}
+	defer func() {
+		q.mu.Lock()
+		q.replaying = false
+		q.mu.Unlock()
+	}()
+
+	for {
+		q.mu.Lock()
+		if len(q.requests) == 0 {
+			q.mu.Unlock()
+			return nil
+		}
+		var req = q.requests[0]
+		q.mu.Unlock()
+
+		if !q.opts.Status.Online() {
+			return nil
+		}
+		var done, err = q.replay(ctx, transport, req)
+		if err != nil {
+			return err
+		}
+		if done {
+			if err = q.pop(req); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Replay a single request.
+// Returns true if the request should be removed from the queue.
+func (q *Queue) replay(ctx context.Context, transport http.RoundTripper, req *Request) (bool, error) {
+	for retries := 0; ; retries++ {
+		var r, err = req.HTTPRequest(ctx)
+		if err != nil {
+			return false, err
+		}
+		req.Attempts++
+		resp, err := transport.RoundTrip(r)
+		if err != nil {
+			return false, err
+		}
+		switch {
+		case q.conflicts(resp.StatusCode):
+			var resolution = q.opts.OnConflict(req, resp)
+			resp.Body.Close()
+			switch {
+			case resolution == Discard:
+				return true, nil
+			case resolution == Retry && retries < q.opts.MaxRetries:
+				continue
+			}
+			return false, ErrConflict
+		case resp.StatusCode >= 500:
+			var err = client.NewError(resp)
+			resp.Body.Close()
+			return false, err
+		case resp.StatusCode >= 400:
+			if q.opts.OnFailed != nil {
+				q.opts.OnFailed(req, resp)
+			}
+		default:
+			if q.opts.OnReplayed != nil {
+				q.opts.OnReplayed(req, resp)
+			}
+		}
+		resp.Body.Close()
+		return true, nil
+	}
+}
+
+func (q *Queue) conflicts(status int) bool {
+	for _, s := range q.opts.ConflictStatus {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// Remove the request from the queue.
+func (q *Queue) pop(req *Request) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for i, r := range q.requests {
+		if r == req {
+			q.requests = append(q.requests[:i:i], q.requests[i+1:]...)
+			break
+		}
+	}
+	return q.opts.Store.Save(q.requests)
+}
+
+func readBody(r *http.Request) ([]byte, error) {
+	if r.Body == nil || r.Body == http.NoBody {
+		return nil, nil
+	}
+	defer r.Body.Close()
+	return io.ReadAll(r.Body)
+}
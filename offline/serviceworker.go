@@ -0,0 +1,121 @@
+package offline
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// Options for the generated service worker.
+type ServiceWorkerOptions struct {
+	// Version of the cache, change it to invalidate the cached files when deploying a new build.
+	Version string
+	// Files cached when the service worker is installed,
+	// such as the wasm binary, wasm_exec.js and index.html.
+	Precache []string
+	// Page served for navigations while offline, so that the router can handle the path.
+	// Defaults to the first precached file ending in .html, or "/index.html".
+	Fallback string
+	// Path prefixes of files which are cached the first time they are requested, such as "/static/".
+	// Cached files are served from the cache, and updated in the background.
+	Runtime []string
+}
+
+func (o *ServiceWorkerOptions) SetDefaults() {
+	if o.Version == "" {
+		o.Version = "v1"
+	}
+	if o.Precache == nil {
+		o.Precache = []string{"/", "/index.html", "/wasm_exec.js", "/main.wasm"}
+	}
+	if o.Fallback == "" {
+		o.Fallback = "/index.html"
+		for _, p := range o.Precache {
+			if strings.HasSuffix(p, ".html") {
+				o.Fallback = p
+				break
+			}
+		}
+	}
+}
+
+// Generate the javascript of the service worker.
+//
+// Navigations are fetched from the network, and fall back to the cached fallback page while offline.
+// Precached files and files matching the runtime prefixes are served from the cache,
+// and revalidated in the background. Other requests are not touched.
+func ServiceWorkerScript(opts *ServiceWorkerOptions) string {
+	var o ServiceWorkerOptions
+	if opts != nil {
+		o = *opts
+	}
+	o.SetDefaults()
+	var precache, _ = json.Marshal(o.Precache)
+	var runtime, _ = json.Marshal(append([]string{}, o.Runtime...))
+	var cache, _ = json.Marshal("jsext-" + o.Version)
+	var fallback, _ = json.Marshal(o.Fallback)
+	return `const CACHE = ` + string(cache) + `;
+const PRECACHE = ` + string(precache) + `;
+const RUNTIME = ` + string(runtime) + `;
+const FALLBACK = ` + string(fallback) + `;
+
+self.addEventListener("install", (event) => {
+	event.waitUntil(caches.open(CACHE).then((cache) => cache.addAll(PRECACHE)).then(() => self.skipWaiting()));
+});
+
+self.addEventListener("activate", (event) => {
+	event.waitUntil(caches.keys().then((keys) => Promise.all(
+		keys.filter((key) => key.startsWith("jsext-") && key !== CACHE).map((key) => caches.delete(key))
+	)).then(() => self.clients.claim()));
+});
+
+function cacheable(url) {
+	const path = new URL(url).pathname;
+	return PRECACHE.includes(path) || RUNTIME.some((prefix) => path.startsWith(prefix));
+}
+
+self.addEventListener("fetch", (event) => {
+	const request = event.request;
+	if (request.method !== "GET" || new URL(request.url).origin !== self.location.origin) {
+		return;
+	}
+	if (request.mode === "navigate") {
+		event.respondWith(fetch(request).catch(() => caches.match(FALLBACK)));
+		return;
+	}
+	if (!cacheable(request.url)) {
+		return;
+	}
+	event.respondWith(caches.open(CACHE).then((cache) => cache.match(request).then((cached) => {
+		const network = fetch(request).then((response) => {
+			if (response.ok) {
+				cache.put(request, response.clone());
+			}
+			return response;
+		});
+		if (cached) {
+			event.waitUntil(network.catch(() => {}));
+			return cached;
+		}
+		return network;
+	})));
+});
+
+self.addEventListener("message", (event) => {
+	if (event.data === "skipWaiting") {
+		self.skipWaiting();
+	}
+});
+`
+}
+
+// Handler serving the generated service worker.
+// Serve it from the root of the site, so that it controls all pages.
+func ServiceWorkerHandler(opts *ServiceWorkerOptions) http.Handler {
+	var script = []byte(ServiceWorkerScript(opts))
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/javascript")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Write(script)
+	})
+}
@@ -0,0 +1,74 @@
+package offline
+
+import (
+	"sync"
+
+	"github.com/Nigel2392/jsext-framework/helpers/hooks"
+)
+
+// Status of the network connection.
+//
+// The status can be forced offline with Simulate, to test the offline behaviour of the application.
+type Status struct {
+	online    bool
+	simulated bool
+	listeners *hooks.Hooks[func(online bool)]
+	mu        *sync.Mutex
+}
+
+// Initialize a new network status.
+func NewStatus(online bool) *Status {
+	return &Status{
+		online:    online,
+		listeners: &hooks.Hooks[func(online bool)]{},
+		mu:        &sync.Mutex{},
+	}
+}
+
+// Check if the network is online.
+// Always false while offline mode is simulated.
+func (s *Status) Online() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.online && !s.simulated
+}
+
+// Set the status reported by the browser.
+func (s *Status) Set(online bool) {
+	s.update(func() { s.online = online })
+}
+
+// Simulate offline mode, regardless of the status reported by the browser.
+func (s *Status) Simulate(offline bool) {
+	s.update(func() { s.simulated = offline })
+}
+
+// Check if offline mode is simulated.
+func (s *Status) Simulated() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.simulated
+}
+
+// Register a function to call when the status changes.
+// The returned function removes the listener.
+func (s *Status) OnChange(f func(online bool)) (remove func()) {
+	return s.listeners.Add(f)
+}
+
+// Apply the change, and call the listeners if the status changed.
+func (s *Status) update(change func()) {
+	s.mu.Lock()
+	var before = s.online && !s.simulated
+	change()
+	var after = s.online && !s.simulated
+	if before == after {
+		s.mu.Unlock()
+		return
+	}
+	var listeners = s.listeners.List()
+	s.mu.Unlock()
+	for _, f := range listeners {
+		f(after)
+	}
+}
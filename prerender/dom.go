@@ -0,0 +1,484 @@
+package prerender
+
+// Minimal DOM for running an application in Node.js, loaded before the WebAssembly binary.
+//
+// Elements keep their attributes and children, and are serialized with innerHTML and outerHTML.
+// HTML set with innerHTML or insertAdjacentHTML is kept as is, and is not parsed.
+// Layout, events and observers are stubs, so the application can run without a browser.
+const DOM = `"use strict";
+
+const VOID = new Set(["area", "base", "br", "col", "embed", "hr", "img", "input", "link", "meta", "source", "track", "wbr"]);
+
+const escapeText = (s) => String(s).replace(/&/g, "&amp;").replace(/</g, "&lt;").replace(/>/g, "&gt;");
+const escapeAttr = (s) => String(s).replace(/&/g, "&amp;").replace(/"/g, "&quot;");
+const kebab = (s) => s.replace(/[A-Z]/g, (c) => "-" + c.toLowerCase());
+
+class Node {
+	constructor(type) {
+		this.nodeType = type;
+		this.childNodes = [];
+		this.parentNode = null;
+	}
+	get firstChild() { return this.childNodes[0] || null; }
+	get lastChild() { return this.childNodes[this.childNodes.length - 1] || null; }
+	get children() { return this.childNodes.filter((n) => n.nodeType === 1); }
+	get childElementCount() { return this.children.length; }
+	get firstElementChild() { return this.children[0] || null; }
+	get parentElement() { return this.parentNode && this.parentNode.nodeType === 1 ? this.parentNode : null; }
+	get nextSibling() { return this.sibling(1); }
+	get previousSibling() { return this.sibling(-1); }
+	get ownerDocument() { return globalThis.document; }
+	get isConnected() { return globalThis.document.contains(this); }
+	sibling(offset) {
+		if (!this.parentNode) return null;
+		return this.parentNode.childNodes[this.parentNode.childNodes.indexOf(this) + offset] || null;
+	}
+	hasChildNodes() { return this.childNodes.length > 0; }
+	appendChild(child) { return this.insertBefore(child, null); }
+	insertBefore(child, ref) {
+		if (child.nodeType === 11) {
+			for (const n of [...child.childNodes]) this.insertBefore(n, ref);
+			return child;
+		}
+		if (child.parentNode) child.parentNode.removeChild(child);
+		const i = ref ? this.childNodes.indexOf(ref) : -1;
+		if (i < 0) this.childNodes.push(child);
+		else this.childNodes.splice(i, 0, child);
+		child.parentNode = this;
+		return child;
+	}
+	removeChild(child) {
+		const i = this.childNodes.indexOf(child);
+		if (i >= 0) this.childNodes.splice(i, 1);
+		child.parentNode = null;
+		return child;
+	}
+	replaceChild(child, old) {
+		this.insertBefore(child, old);
+		return this.removeChild(old);
+	}
+	append(...nodes) { for (const n of nodes) this.appendChild(toNode(n)); }
+	prepend(...nodes) {
+		const first = this.firstChild;
+		for (const n of nodes) this.insertBefore(toNode(n), first);
+	}
+	replaceChildren(...nodes) {
+		for (const c of this.childNodes) c.parentNode = null;
+		this.childNodes = [];
+		this.append(...nodes);
+	}
+	before(...nodes) { if (this.parentNode) for (const n of nodes) this.parentNode.insertBefore(toNode(n), this); }
+	after(...nodes) {
+		if (!this.parentNode) return;
+		const next = this.nextSibling;
+		for (const n of nodes) this.parentNode.insertBefore(toNode(n), next);
+	}
+	replaceWith(...nodes) {
+		this.before(...nodes);
+		this.remove();
+	}
+	remove() { if (this.parentNode) this.parentNode.removeChild(this); }
+	contains(node) {
+		for (; node; node = node.parentNode) if (node === this) return true;
+		return false;
+	}
+	cloneNode(deep) {
+		const clone = this.clone();
+		if (deep) for (const c of this.childNodes) clone.appendChild(c.cloneNode(true));
+		return clone;
+	}
+	get textContent() { return this.childNodes.map((c) => c.textContent).join(""); }
+	set textContent(value) {
+		this.replaceChildren();
+		if (value !== "" && value != null) this.appendChild(new Text(value));
+	}
+	addEventListener() {}
+	removeEventListener() {}
+	dispatchEvent() { return true; }
+}
+
+class Text extends Node {
+	constructor(data) {
+		super(3);
+		this.data = String(data);
+	}
+	get nodeName() { return "#text"; }
+	get textContent() { return this.data; }
+	set textContent(value) { this.data = String(value); }
+	get nodeValue() { return this.data; }
+	get outerHTML() { return escapeText(this.data); }
+	clone() { return new Text(this.data); }
+}
+
+// HTML which was set as a string, it is serialized as is.
+class RawHTML extends Text {
+	get textContent() { return this.data.replace(/<[^>]*>/g, ""); }
+	get outerHTML() { return this.data; }
+	clone() { return new RawHTML(this.data); }
+}
+
+class Comment extends Node {
+	constructor(data) {
+		super(8);
+		this.data = String(data);
+	}
+	get nodeName() { return "#comment"; }
+	get textContent() { return ""; }
+	get outerHTML() { return "<!--" + this.data + "-->"; }
+	clone() { return new Comment(this.data); }
+}
+
+class DocumentFragment extends Node {
+	constructor() { super(11); }
+	get outerHTML() { return this.childNodes.map((c) => c.outerHTML).join(""); }
+	clone() { return new DocumentFragment(); }
+	querySelector(selector) { return Element.prototype.querySelector.call(this, selector); }
+	querySelectorAll(selector) { return Element.prototype.querySelectorAll.call(this, selector); }
+}
+
+const toNode = (n) => (n instanceof Node ? n : new Text(n));
+
+class ClassList {
+	constructor(element) { this.element = element; }
+	get tokens() { return (this.element.getAttribute("class") || "").split(/\s+/).filter(Boolean); }
+	set tokens(list) { this.element.setAttribute("class", list.join(" ")); }
+	get length() { return this.tokens.length; }
+	get value() { return this.tokens.join(" "); }
+	item(i) { return this.tokens[i] || null; }
+	contains(token) { return this.tokens.includes(token); }
+	add(...tokens) { this.tokens = [...new Set([...this.tokens, ...tokens])]; }
+	remove(...tokens) { this.tokens = this.tokens.filter((t) => !tokens.includes(t)); }
+	toggle(token, force) {
+		const has = this.contains(token);
+		if (force === undefined ? has : !force) {
+			this.remove(token);
+			return false;
+		}
+		this.add(token);
+		return true;
+	}
+	replace(token, next) {
+		if (!this.contains(token)) return false;
+		this.tokens = this.tokens.map((t) => (t === token ? next : t));
+		return true;
+	}
+	forEach(f) { this.tokens.forEach(f); }
+	toString() { return this.value; }
+}
+
+// Style declaration backed by the style attribute of the element.
+const styleOf = (element) => {
+	const read = () => {
+		const props = new Map();
+		for (const decl of (element.getAttribute("style") || "").split(";")) {
+			const i = decl.indexOf(":");
+			if (i > 0) props.set(decl.slice(0, i).trim(), decl.slice(i + 1).trim());
+		}
+		return props;
+	};
+	const write = (props) => {
+		const css = [...props].map(([k, v]) => k + ": " + v + ";").join(" ");
+		if (css) element.setAttribute("style", css);
+		else element.removeAttribute("style");
+	};
+	const methods = {
+		getPropertyValue: (name) => read().get(name) || "",
+		setProperty: (name, value) => {
+			const props = read();
+			if (value === "" || value == null) props.delete(name);
+			else props.set(name, String(value));
+			write(props);
+		},
+		removeProperty: (name) => {
+			const props = read();
+			const value = props.get(name) || "";
+			props.delete(name);
+			write(props);
+			return value;
+		},
+	};
+	return new Proxy({}, {
+		get(_, key) {
+			if (key in methods) return methods[key];
+			if (key === "cssText") return element.getAttribute("style") || "";
+			if (typeof key !== "string") return undefined;
+			return methods.getPropertyValue(kebab(key));
+		},
+		set(_, key, value) {
+			if (key === "cssText") element.setAttribute("style", value);
+			else methods.setProperty(kebab(String(key)), value);
+			return true;
+		},
+	});
+};
+
+// Simple selectors, such as div, #id, .class, [name=value] and combinations of these.
+// Lists of selectors are separated by commas, combinators are not supported.
+const matches = (element, selector) => selector.split(",").some((part) => {
+	const re = /([#.]?)([\w-]+)|\[([\w-]+)(?:=["']?([^"'\]]*)["']?)?\]|(\*)/g;
+	const s = part.trim();
+	if (!s) return false;
+	let m, length = 0;
+	while ((m = re.exec(s)) !== null) {
+		length += m[0].length;
+		if (m[5]) continue;
+		if (m[3] !== undefined) {
+			if (!element.hasAttribute(m[3])) return false;
+			if (m[4] !== undefined && element.getAttribute(m[3]) !== m[4]) return false;
+		} else if (m[1] === "#") {
+			if (element.id !== m[2]) return false;
+		} else if (m[1] === ".") {
+			if (!element.classList.contains(m[2])) return false;
+		} else if (element.localName !== m[2].toLowerCase()) {
+			return false;
+		}
+	}
+	return length === s.length;
+});
+
+class Element extends Node {
+	constructor(tag) {
+		super(1);
+		this.localName = String(tag).toLowerCase();
+		this.attrs = new Map();
+		this.style = styleOf(this);
+		this.classList = new ClassList(this);
+		this.dataset = new Proxy({}, {
+			get: (_, key) => this.getAttribute("data-" + kebab(String(key))) ?? undefined,
+			set: (_, key, value) => {
+				this.setAttribute("data-" + kebab(String(key)), value);
+				return true;
+			},
+		});
+	}
+	get tagName() { return this.localName.toUpperCase(); }
+	get nodeName() { return this.tagName; }
+	get attributes() { return [...this.attrs].map(([name, value]) => ({ name, value })); }
+	setAttribute(name, value) { this.attrs.set(String(name).toLowerCase(), String(value)); }
+	getAttribute(name) {
+		const value = this.attrs.get(String(name).toLowerCase());
+		return value === undefined ? null : value;
+	}
+	hasAttribute(name) { return this.attrs.has(String(name).toLowerCase()); }
+	removeAttribute(name) { this.attrs.delete(String(name).toLowerCase()); }
+	toggleAttribute(name, force) {
+		const has = this.hasAttribute(name);
+		if (force === undefined ? has : !force) {
+			this.removeAttribute(name);
+			return false;
+		}
+		this.setAttribute(name, "");
+		return true;
+	}
+	get id() { return this.getAttribute("id") || ""; }
+	set id(value) { this.setAttribute("id", value); }
+	get className() { return this.getAttribute("class") || ""; }
+	set className(value) { this.setAttribute("class", value); }
+	get value() { return this.getAttribute("value") || ""; }
+	set value(value) { this.setAttribute("value", value); }
+	get href() { return this.getAttribute("href") || ""; }
+	set href(value) { this.setAttribute("href", value); }
+	get innerHTML() { return this.childNodes.map((c) => c.outerHTML).join(""); }
+	set innerHTML(value) {
+		this.replaceChildren();
+		if (value !== "" && value != null) this.appendChild(new RawHTML(value));
+	}
+	get outerHTML() {
+		let html = "<" + this.localName;
+		for (const [name, value] of this.attrs) html += " " + name + '="' + escapeAttr(value) + '"';
+		html += ">";
+		if (VOID.has(this.localName)) return html;
+		return html + this.innerHTML + "</" + this.localName + ">";
+	}
+	get innerText() { return this.textContent; }
+	set innerText(value) { this.textContent = value; }
+	insertAdjacentHTML(position, html) { this.insertAdjacent(position, new RawHTML(html)); }
+	insertAdjacentText(position, text) { this.insertAdjacent(position, new Text(text)); }
+	insertAdjacentElement(position, element) {
+		this.insertAdjacent(position, element);
+		return element;
+	}
+	insertAdjacent(position, node) {
+		switch (String(position).toLowerCase()) {
+		case "beforebegin": this.before(node); break;
+		case "afterbegin": this.prepend(node); break;
+		case "beforeend": this.append(node); break;
+		case "afterend": this.after(node); break;
+		}
+	}
+	matches(selector) { return matches(this, selector); }
+	closest(selector) {
+		for (let e = this; e && e.nodeType === 1; e = e.parentNode) if (e.matches(selector)) return e;
+		return null;
+	}
+	querySelectorAll(selector) {
+		const found = [];
+		const walk = (node) => {
+			for (const c of node.childNodes) {
+				if (c.nodeType !== 1) continue;
+				if (c.matches(selector)) found.push(c);
+				walk(c);
+			}
+		};
+		walk(this);
+		return found;
+	}
+	querySelector(selector) { return this.querySelectorAll(selector)[0] || null; }
+	getElementsByTagName(tag) { return this.querySelectorAll(tag); }
+	getElementsByClassName(name) { return this.querySelectorAll("." + name.trim().split(/\s+/).join(".")); }
+	getBoundingClientRect() { return { x: 0, y: 0, top: 0, left: 0, right: 0, bottom: 0, width: 0, height: 0 }; }
+	getClientRects() { return []; }
+	get offsetWidth() { return 0; }
+	get offsetHeight() { return 0; }
+	get clientWidth() { return 0; }
+	get clientHeight() { return 0; }
+	get scrollWidth() { return 0; }
+	get scrollHeight() { return 0; }
+	focus() {}
+	blur() {}
+	click() {}
+	scrollTo() {}
+	scrollIntoView() {}
+	animate() { return { finished: Promise.resolve(), cancel() {}, finish() {}, play() {}, pause() {} }; }
+	clone() {
+		const clone = new Element(this.localName);
+		for (const [name, value] of this.attrs) clone.attrs.set(name, value);
+		return clone;
+	}
+}
+
+class Document extends Node {
+	constructor() {
+		super(9);
+		this.documentElement = new Element("html");
+		this.head = this.documentElement.appendChild(new Element("head"));
+		this.body = this.documentElement.appendChild(new Element("body"));
+		this.childNodes.push(this.documentElement);
+		this.documentElement.parentNode = this;
+		this.cookie = "";
+		this.readyState = "complete";
+		this.visibilityState = "visible";
+		this.hidden = false;
+	}
+	get activeElement() { return this.body; }
+	get defaultView() { return globalThis; }
+	get title() {
+		const title = this.head.querySelector("title");
+		return title ? title.textContent : "";
+	}
+	set title(value) {
+		let title = this.head.querySelector("title");
+		if (!title) title = this.head.appendChild(new Element("title"));
+		title.textContent = value;
+	}
+	createElement(tag) { return new Element(tag); }
+	createElementNS(ns, tag) { return new Element(tag); }
+	createTextNode(data) { return new Text(data); }
+	createComment(data) { return new Comment(data); }
+	createDocumentFragment() { return new DocumentFragment(); }
+	getElementById(id) { return this.documentElement.querySelector("#" + id); }
+	querySelector(selector) { return this.documentElement.matches(selector) ? this.documentElement : this.documentElement.querySelector(selector); }
+	querySelectorAll(selector) { return this.documentElement.querySelectorAll(selector); }
+	getElementsByTagName(tag) { return this.documentElement.getElementsByTagName(tag); }
+	getElementsByClassName(name) { return this.documentElement.getElementsByClassName(name); }
+}
+
+class Storage {
+	constructor() { this.items = new Map(); }
+	get length() { return this.items.size; }
+	key(i) { return [...this.items.keys()][i] ?? null; }
+	getItem(key) { return this.items.has(String(key)) ? this.items.get(String(key)) : null; }
+	setItem(key, value) { this.items.set(String(key), String(value)); }
+	removeItem(key) { this.items.delete(String(key)); }
+	clear() { this.items.clear(); }
+}
+
+class Observer {
+	observe() {}
+	unobserve() {}
+	disconnect() {}
+	takeRecords() { return []; }
+}
+
+const origin = process.env.JSEXT_PRERENDER_ORIGIN || "http://localhost";
+const location = {
+	assign(url) { setLocation(url); },
+	replace(url) { setLocation(url); },
+	reload() {},
+	toString() { return this.href; },
+};
+const setLocation = (url) => {
+	const u = new URL(String(url), location.href || origin + "/");
+	for (const key of ["href", "origin", "protocol", "host", "hostname", "port", "pathname", "search", "hash"]) location[key] = u[key];
+};
+setLocation(origin + "/");
+
+globalThis.window = globalThis;
+globalThis.self = globalThis;
+globalThis.document = new Document();
+globalThis.location = location;
+globalThis.history = {
+	length: 1,
+	state: null,
+	pushState(state, title, url) {
+		this.state = state;
+		if (url != null) setLocation(url);
+	},
+	replaceState(state, title, url) {
+		this.state = state;
+		if (url != null) setLocation(url);
+	},
+	back() {},
+	forward() {},
+	go() {},
+};
+// Base element of the application, such as #app, created in the body.
+const base = process.env.JSEXT_PRERENDER_BASE;
+if (base) {
+	const m = /^([\w-]*)((?:[#.][\w-]+)*)$/.exec(base.trim());
+	if (!m) throw new Error("prerender: unsupported base element selector " + base);
+	const element = document.body.appendChild(new Element(m[1] || "div"));
+	for (const [, kind, name] of m[2].matchAll(/([#.])([\w-]+)/g)) {
+		if (kind === "#") element.id = name;
+		else element.classList.add(name);
+	}
+}
+globalThis.localStorage = new Storage();
+globalThis.sessionStorage = new Storage();
+globalThis.Node = Node;
+globalThis.Element = Element;
+globalThis.HTMLElement = Element;
+globalThis.Text = Text;
+globalThis.Comment = Comment;
+globalThis.DocumentFragment = DocumentFragment;
+globalThis.IntersectionObserver = Observer;
+globalThis.ResizeObserver = Observer;
+globalThis.MutationObserver = Observer;
+globalThis.addEventListener = () => {};
+globalThis.removeEventListener = () => {};
+globalThis.dispatchEvent = () => true;
+globalThis.matchMedia = (media) => ({ media, matches: false, addEventListener() {}, removeEventListener() {}, addListener() {}, removeListener() {} });
+globalThis.getComputedStyle = (element) => element.style;
+globalThis.requestAnimationFrame = (f) => setTimeout(() => f(Date.now()), 16);
+globalThis.cancelAnimationFrame = (id) => clearTimeout(id);
+globalThis.requestIdleCallback = (f) => setTimeout(() => f({ didTimeout: false, timeRemaining: () => 0 }), 1);
+globalThis.cancelIdleCallback = (id) => clearTimeout(id);
+globalThis.scrollTo = () => {};
+globalThis.scrollX = 0;
+globalThis.scrollY = 0;
+globalThis.innerWidth = 1280;
+globalThis.innerHeight = 800;
+globalThis.devicePixelRatio = 1;
+globalThis.alert = () => {};
+globalThis.confirm = () => false;
+globalThis.prompt = () => null;
+if (globalThis.navigator === undefined) globalThis.navigator = {};
+for (const [key, value] of Object.entries({ onLine: true, language: "en", languages: ["en"], userAgent: "jsext-prerender" })) {
+	if (globalThis.navigator[key] === undefined) {
+		try {
+			Object.defineProperty(globalThis.navigator, key, { value, configurable: true });
+		} catch (e) {}
+	}
+}
+globalThis.` + Global + ` = true;
+`
@@ -0,0 +1,298 @@
+// Package prerender writes the static routes of an application to HTML files.
+//
+// The application is compiled to WebAssembly and ran in Node.js with a minimal DOM,
+// so the pages are rendered by the same components and jsext elements as in the browser.
+// In the browser, the application replaces the pre-rendered content when it renders the first page,
+// the pre-rendered nodes are not hydrated.
+package prerender
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"html"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Placeholders in the template.
+const (
+	// Replaced by the pre-rendered page, place it inside the base element of the application.
+	ContentPlaceholder = "<!--jsext-content-->"
+	// Replaced by the title of the page.
+	TitlePlaceholder = "<!--jsext-title-->"
+)
+
+// Template used when no template is given, the application is rendered in the body.
+const DefaultTemplate = `<!DOCTYPE html>
+<html lang="en">
+<head>
+	<meta charset="utf-8">
+	<meta name="viewport" content="width=device-width, initial-scale=1">
+	<title>` + TitlePlaceholder + `</title>
+	<script src="/wasm_exec.js"></script>
+	<script>
+		const go = new Go();
+		WebAssembly.instantiateStreaming(fetch("/main.wasm"), go.importObject).then((result) => go.run(result.instance));
+	</script>
+</head>
+<body>` + ContentPlaceholder + `</body>
+</html>
+`
+
+// Prefix of the comment placed before pre-rendered content, followed by the path of the page.
+const Marker = "jsext-prerendered:"
+
+// Global javascript variable which is set when the application is pre-rendered.
+const Global = "jsextPrerender"
+
+// Returned when the template does not contain the content placeholder.
+var ErrNoPlaceholder = errors.New("prerender: template does not contain " + ContentPlaceholder)
+
+// Page rendered by the application.
+type Page struct {
+	// Name of the route.
+	Name string `json:"name"`
+	// Path of the route.
+	Path string `json:"path"`
+	// Title of the document after the route was rendered.
+	Title string `json:"title"`
+	// HTML of the base element of the application.
+	HTML string `json:"html"`
+}
+
+// Write the page for the command which pre-renders the application, on a line of its own.
+func Emit(w io.Writer, p Page) error {
+	var b, err = json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "%s%s\n", Marker, b)
+	return err
+}
+
+// Read the pages written by the application.
+// Other output of the application is written to other, if it is not nil.
+func Decode(r io.Reader, other io.Writer) ([]Page, error) {
+	var pages []Page
+	var scanner = bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+	for scanner.Scan() {
+		var line = scanner.Bytes()
+		if !bytes.HasPrefix(line, []byte(Marker)) {
+			if other != nil {
+				fmt.Fprintf(other, "%s\n", line)
+			}
+			continue
+		}
+		var p Page
+		if err := json.Unmarshal(line[len(Marker):], &p); err != nil {
+			return pages, fmt.Errorf("prerender: decoding page: %w", err)
+		}
+		pages = append(pages, p)
+	}
+	return pages, scanner.Err()
+}
+
+// Options for pre-rendering.
+type Options struct {
+	// Directory to write the pages to, defaults to "public".
+	Dir string
+	// HTML of the page the content is placed in, usually the index.html of the application.
+	Template string
+	// Title of a page, defaults to the title of the document,
+	// or the route name with the first letter capitalized.
+	Title func(p Page) string
+	// Selector of the base element of the application, such as "#app".
+	// The element is created in the body, leave it empty for applications rendered in the body.
+	Base string
+	// Package of the application, defaults to the current directory.
+	Package string
+	// Node.js executable, defaults to "node".
+	Node string
+	// Output of the application, other than the pages, defaults to os.Stderr.
+	Output io.Writer
+}
+
+func (o *Options) SetDefaults() {
+	if o.Dir == "" {
+		o.Dir = "public"
+	}
+	if o.Template == "" {
+		o.Template = DefaultTemplate
+	}
+	if o.Title == nil {
+		o.Title = func(p Page) string {
+			if p.Title != "" || p.Name == "" {
+				return p.Title
+			}
+			return strings.ToUpper(p.Name[:1]) + p.Name[1:]
+		}
+	}
+	if o.Package == "" {
+		o.Package = "."
+	}
+	if o.Node == "" {
+		o.Node = "node"
+	}
+	if o.Output == nil {
+		o.Output = os.Stderr
+	}
+}
+
+// Render the page into the template.
+func Render(p Page, opts *Options) (string, error) {
+	var o Options
+	if opts != nil {
+		o = *opts
+	}
+	o.SetDefaults()
+	if !strings.Contains(o.Template, ContentPlaceholder) {
+		return "", ErrNoPlaceholder
+	}
+	var content = "<!--" + Marker + p.Path + "-->" + p.HTML
+	var page = strings.Replace(o.Template, ContentPlaceholder, content, 1)
+	return strings.ReplaceAll(page, TitlePlaceholder, html.EscapeString(o.Title(p))), nil
+}
+
+// Path of the file a page is written to.
+// The root is written to index.html, other paths to path/index.html.
+func FilePath(dir, path string) string {
+	return filepath.Join(dir, filepath.FromSlash(strings.Trim(path, "/")), "index.html")
+}
+
+// Write the pages to the directory of the options.
+// Returns the paths of the written files.
+func Write(pages []Page, opts *Options) ([]string, error) {
+	var o Options
+	if opts != nil {
+		o = *opts
+	}
+	o.SetDefaults()
+	var files []string
+	for _, p := range pages {
+		var page, err = Render(p, &o)
+		if err != nil {
+			return files, err
+		}
+		var file = FilePath(o.Dir, p.Path)
+		if err = os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			return files, err
+		}
+		if err = os.WriteFile(file, []byte(page), 0644); err != nil {
+			return files, err
+		}
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+// Compile the application to WebAssembly, and render its static routes in Node.js.
+func Pages(opts *Options) ([]Page, error) {
+	var o Options
+	if opts != nil {
+		o = *opts
+	}
+	o.SetDefaults()
+	var tmp, err = os.MkdirTemp("", "jsext-prerender")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmp)
+
+	var wasm = filepath.Join(tmp, "app.wasm")
+	var build = exec.Command("go", "build", "-o", wasm, o.Package)
+	build.Env = append(os.Environ(), "GOOS=js", "GOARCH=wasm")
+	build.Stdout = o.Output
+	build.Stderr = o.Output
+	if err = build.Run(); err != nil {
+		return nil, fmt.Errorf("prerender: building %s: %w", o.Package, err)
+	}
+	var dom = filepath.Join(tmp, "dom.js")
+	if err = os.WriteFile(dom, []byte(DOM), 0644); err != nil {
+		return nil, err
+	}
+	script, err := wasmExec()
+	if err != nil {
+		return nil, err
+	}
+
+	var stdout bytes.Buffer
+	var node = exec.Command(o.Node, "--require", dom, script, wasm)
+	node.Env = append(os.Environ(), "JSEXT_PRERENDER_BASE="+o.Base)
+	node.Stdout = &stdout
+	node.Stderr = o.Output
+	if err = node.Run(); err != nil {
+		return nil, fmt.Errorf("prerender: running the application: %w", err)
+	}
+	return Decode(&stdout, o.Output)
+}
+
+// Path of the script which runs WebAssembly binaries in Node.js, shipped with Go.
+func wasmExec() (string, error) {
+	var b, err = exec.Command("go", "env", "GOROOT").Output()
+	if err != nil {
+		return "", err
+	}
+	var root = strings.TrimSpace(string(b))
+	for _, dir := range []string{"lib/wasm", "misc/wasm"} {
+		var file = filepath.Join(root, dir, "wasm_exec_node.js")
+		if _, err := os.Stat(file); err == nil {
+			return file, nil
+		}
+	}
+	return "", errors.New("prerender: wasm_exec_node.js not found in " + root)
+}
+
+// Compile the application, render its static routes and write the pages.
+// Returns the paths of the written files.
+func Run(opts *Options) ([]string, error) {
+	var o Options
+	if opts != nil {
+		o = *opts
+	}
+	o.SetDefaults()
+	var pages, err = Pages(&o)
+	if err != nil {
+		return nil, err
+	}
+	return Write(pages, &o)
+}
+
+// Run a command which pre-renders the static routes of the application.
+//
+//	go run ./cmd/prerender -pkg . -base "#app" -dir public -template index.template.html
+//
+// Keep the template outside of the written pages, the root page is written to index.html.
+func Main() {
+	var opts Options
+	var template string
+	flag.StringVar(&opts.Dir, "dir", "public", "directory to write the pages to")
+	flag.StringVar(&template, "template", "", "HTML template containing "+ContentPlaceholder)
+	flag.StringVar(&opts.Base, "base", "", "selector of the base element of the application, such as #app")
+	flag.StringVar(&opts.Package, "pkg", ".", "package of the application")
+	flag.StringVar(&opts.Node, "node", "node", "Node.js executable")
+	flag.Parse()
+	if template != "" {
+		var b, err = os.ReadFile(template)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		opts.Template = string(b)
+	}
+	var files, err = Run(&opts)
+	for _, file := range files {
+		fmt.Println(file)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,94 @@
+package prerender_test
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Nigel2392/jsext-framework/prerender"
+)
+
+func TestWrite(t *testing.T) {
+	var pages = []prerender.Page{
+		{Name: "home", Path: "/", HTML: "<main><h1>Home</h1></main>"},
+		{Name: "install", Path: "/docs/install", Title: "Install <Go>", HTML: "<main><h1>Install</h1></main>"},
+	}
+	var dir = t.TempDir()
+	var files, err = prerender.Write(pages, &prerender.Options{Dir: dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var want = []string{
+		filepath.Join(dir, "index.html"),
+		filepath.Join(dir, "docs", "install", "index.html"),
+	}
+	if strings.Join(files, ",") != strings.Join(want, ",") {
+		t.Fatalf("got files %v, want %v", files, want)
+	}
+	for i, s := range [][]string{
+		{"<title>Home</title>", "<body><!--jsext-prerendered:/--><main><h1>Home</h1></main></body>"},
+		{"<title>Install &lt;Go&gt;</title>", "<body><!--jsext-prerendered:/docs/install--><main><h1>Install</h1></main></body>"},
+	} {
+		var b, _ = os.ReadFile(files[i])
+		for _, s := range s {
+			if !strings.Contains(string(b), s) {
+				t.Errorf("page does not contain %s:\n%s", s, b)
+			}
+		}
+	}
+}
+
+func TestTemplateWithoutPlaceholder(t *testing.T) {
+	if _, err := prerender.Render(prerender.Page{Path: "/"}, &prerender.Options{Template: "<html></html>"}); err != prerender.ErrNoPlaceholder {
+		t.Errorf("expected ErrNoPlaceholder, got %v", err)
+	}
+}
+
+func TestDecode(t *testing.T) {
+	var b bytes.Buffer
+	b.WriteString("starting\n")
+	prerender.Emit(&b, prerender.Page{Name: "home", Path: "/", HTML: "<p>line\nbreak</p>"})
+	b.WriteString("done\n")
+
+	var other bytes.Buffer
+	var pages, err = prerender.Decode(&b, &other)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pages) != 1 || pages[0].Path != "/" || pages[0].HTML != "<p>line\nbreak</p>" {
+		t.Errorf("got pages %+v", pages)
+	}
+	if other.String() != "starting\ndone\n" {
+		t.Errorf("got other output %q", other.String())
+	}
+}
+
+// Render the application in testdata/app, which uses jsext elements.
+func TestPages(t *testing.T) {
+	if testing.Short() {
+		t.Skip("compiles the application to WebAssembly")
+	}
+	if _, err := exec.LookPath("node"); err != nil {
+		t.Skip("Node.js is not installed")
+	}
+	var pages, err = prerender.Pages(&prerender.Options{Package: "./testdata/app", Base: "#app"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var want = []prerender.Page{
+		{Name: "home", Path: "/", HTML: `<main class="page"><h1>Home</h1><p>/</p><button>Start</button></main>`},
+		{Name: "docs", Path: "/docs", HTML: `<main class="page"><h1>Docs</h1><p>/docs</p><button>Start</button></main>`},
+		{Name: "install", Path: "/docs/install", HTML: `<main class="page"><h1>Install</h1><p>/docs/install</p><button>Start</button></main>`},
+	}
+	if len(pages) != len(want) {
+		t.Fatalf("got %d pages, want %d: %+v", len(pages), len(want), pages)
+	}
+	for i, p := range pages {
+		if p != want[i] {
+			t.Errorf("got page %+v, want %+v", p, want[i])
+		}
+	}
+}
@@ -0,0 +1,39 @@
+// Application pre-rendered by the tests of the prerender package.
+package main
+
+import (
+	"net/url"
+
+	"github.com/Nigel2392/jsext"
+	"github.com/Nigel2392/jsext-framework/app"
+	"github.com/Nigel2392/jsext-framework/prerender"
+	"github.com/Nigel2392/jsext-framework/router/vars"
+	"github.com/Nigel2392/jsext/elements"
+)
+
+var Application = app.App("#app")
+
+func page(title string) func(a *app.Application, v vars.Vars, u *url.URL) {
+	return func(a *app.Application, v vars.Vars, u *url.URL) {
+		var main = elements.Main().AttrClass("page")
+		main.H1(title)
+		main.P(u.Path)
+		main.Button("Start").AddEventListener("click", func(this jsext.Value, event jsext.Event) {})
+		a.Render(main)
+	}
+}
+
+func main() {
+	Application.Navbar = elements.Nav("Navbar")
+	Application.Register("home", "/", page("Home"))
+	var docs = Application.Register("docs", "/docs", page("Docs"))
+	docs.Register("install", "/install", Application.WrapURL(page("Install")))
+	// Routes with variables, or which opted out, are not pre-rendered.
+	Application.Register("user", "/users/<<id:int>>", page("User"))
+	Application.Register("private", "/private", page("Private")).SetMeta(app.PrerenderMeta, false)
+	Application.Register("empty", "/empty", nil)
+	if !app.Prerendering() {
+		panic("expected the application to be pre-rendered by " + prerender.Global)
+	}
+	Application.Run()
+}
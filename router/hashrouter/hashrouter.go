@@ -106,9 +106,22 @@ func (r *HashRouter) Match(hash string) (*routes.Route, bool) {
 }
 
 func (r *HashRouter) Handle(hash string) {
+	r.handle(hash, true)
+}
+
+// Handle the current hash again, without adding a history entry.
+func (r *HashRouter) Reload() {
+	var hash = js.Global().Get("window").Get("location").Get("hash").String()
+	if hash == "" {
+		hash = "#"
+	}
+	r.handle(hash, false)
+}
+
+func (r *HashRouter) handle(hash string, push bool) {
 	var rt, ok = r.Match(hash)
 	if !ok {
-		var err = rterr.NewError(404, "no route found for "+hash)
+		var err = rterr.NewError(404, rterr.NoRoute+hash)
 		if r.onErr == nil {
 			panic(err)
 		} else {
@@ -140,7 +153,9 @@ func (r *HashRouter) Handle(hash string) {
 	}
 
 	// Set the location
-	js.Global().Get("window").Get("history").Call("pushState", nil, "", hash)
+	if push {
+		js.Global().Get("window").Get("history").Call("pushState", nil, "", hash)
+	}
 }
 
 func (r *HashRouter) Redirect(hash string) {
@@ -1,9 +1,12 @@
 package router
 
 import (
+	"fmt"
 	"net/url"
+	"runtime/debug"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/Nigel2392/jsext-framework/router/routes"
 	"github.com/Nigel2392/jsext-framework/router/rterr"
@@ -21,6 +24,9 @@ type Router struct {
 	onPageChange      func(vars.Vars, *url.URL)
 	afterPageChange   func(vars.Vars, *url.URL)
 	middlewares       []func(vars.Vars, *url.URL, *routes.Route, rterr.ErrorThrower) bool
+	traceMiddleware   []func(index int, rt *routes.Route, took time.Duration, next bool)
+	traceHandler      []func(rt *routes.Route, took time.Duration)
+	onPanic           []func(rt *routes.Route, recovered any, stack []byte)
 }
 
 // Initialize a new router.
@@ -38,6 +44,27 @@ func (r *Router) Use(middleware func(vars.Vars, *url.URL, *routes.Route, rterr.E
 	r.middlewares = append(r.middlewares, middleware)
 }
 
+// Add a function to call after each middleware has run, with the time it took and if the next one was called.
+func (r *Router) TraceMiddleware(f func(index int, rt *routes.Route, took time.Duration, next bool)) {
+	r.traceMiddleware = append(r.traceMiddleware, f)
+}
+
+// Add a function to call after a route handler has returned, with the time it took.
+func (r *Router) TraceHandler(f func(rt *routes.Route, took time.Duration)) {
+	r.traceHandler = append(r.traceHandler, f)
+}
+
+// Recover panics in route handlers, and report them to the functions added.
+// The panic is then thrown as a 500 router error, instead of stopping the application.
+func (r *Router) OnPanic(f func(rt *routes.Route, recovered any, stack []byte)) {
+	r.onPanic = append(r.onPanic, f)
+}
+
+// The registered routes.
+func (r *Router) Routes() []*routes.Route {
+	return r.routes
+}
+
 // Decide what to do on errors.
 func (r *Router) OnError(cb func(err error)) {
 	r.onErr = cb
@@ -139,6 +166,81 @@ func (r *Router) Redirect(path string) {
 	r.HandlePath(path)
 }
 
+// Handle the url without changing the location, and wait for the handler of the route to return.
+// Reports false if no route matches the path, or a middleware stopped the request.
+func (r *Router) Serve(u *url.URL) bool {
+	var rt, vars, ok = r.Match(u.Path)
+	if !ok {
+		r.notFound(u)
+		return false
+	}
+	r.change(vars, u)
+	return r.serve(rt, vars, u)
+}
+
+func (r *Router) notFound(u *url.URL) {
+	var err = rterr.NewError(404, rterr.NoRoute+u.Path)
+	if r.onErr == nil {
+		panic(err)
+	}
+	r.onErr(error(err))
+}
+
+// Leave the last route, and call the page change function.
+func (r *Router) change(v vars.Vars, u *url.URL) {
+	if r.lastRoute != nil && r.lastRoute.OnLeave != nil {
+		r.lastRoute.OnLeave(v, u)
+	}
+	if r.onPageChange != nil {
+		r.onPageChange(v, u)
+	}
+}
+
+// Run the middlewares and the handler of the route.
+// Reports false if a middleware stopped the request.
+func (r *Router) serve(rt *routes.Route, v vars.Vars, u *url.URL) bool {
+	if rt.Callable == nil {
+		return true
+	}
+	for i, m := range r.middlewares {
+		var start = time.Now()
+		var next = m(v, u, rt, r)
+		var took = time.Since(start)
+		for _, f := range r.traceMiddleware {
+			f(i, rt, took, next)
+		}
+		if !next {
+			return false
+		}
+	}
+	if len(r.onPanic) > 0 {
+		defer r.recoverHandler(rt)
+	}
+	var start = time.Now()
+	rt.Callable(v, u)
+	var took = time.Since(start)
+	for _, f := range r.traceHandler {
+		f(rt, took)
+	}
+	if r.afterPageChange != nil {
+		r.afterPageChange(v, u)
+	}
+	return true
+}
+
+// Report a panic in the handler of the route, and throw it as a router error.
+func (r *Router) recoverHandler(rt *routes.Route) {
+	var rec = recover()
+	if rec == nil {
+		return
+	}
+	var stack = debug.Stack()
+	for _, f := range r.onPanic {
+		f(rt, rec, stack)
+	}
+	r.Error(rterr.ErrCodeInternal, fmt.Sprint(rec))
+}
+
 // Redirect to a route by name.
 func (r *Router) RedirectNamed(name string, varMap vars.Vars) {
 	var route = r.GetRoute(name)
@@ -0,0 +1,12 @@
+//go:build !js || !wasm
+// +build !js !wasm
+
+package router
+
+import "net/url"
+
+// Handle is the main router handler.
+// Outside of the browser the route is handled synchronously, and the location is not changed.
+func (r *Router) Handle(u *url.URL) {
+	r.Serve(u)
+}
@@ -3,8 +3,11 @@ package router_test
 import (
 	"net/url"
 	"testing"
+	"time"
 
 	"github.com/Nigel2392/jsext-framework/router"
+	"github.com/Nigel2392/jsext-framework/router/routes"
+	"github.com/Nigel2392/jsext-framework/router/rterr"
 	"github.com/Nigel2392/jsext-framework/router/vars"
 )
 
@@ -118,3 +121,67 @@ func TestRouter(t *testing.T) {
 	}
 
 }
+
+func TestServe(t *testing.T) {
+	var rt = router.NewRouter()
+	rt.SkipTrailingSlash()
+	var handled []string
+	rt.Register("post", "/post/<<post:int>>", func(v vars.Vars, u *url.URL) {
+		handled = append(handled, "post "+v.Get("post"))
+	})
+	rt.Register("private", "/private", func(v vars.Vars, u *url.URL) {
+		handled = append(handled, "private")
+	})
+	rt.Use(func(v vars.Vars, u *url.URL, r *routes.Route, t rterr.ErrorThrower) bool {
+		return r.Name != "private"
+	})
+	var errs []error
+	rt.OnError(func(err error) {
+		errs = append(errs, err)
+	})
+
+	if !rt.Serve(&url.URL{Path: "/post/1/"}) {
+		t.Error("expected /post/1 to be served")
+	}
+	if rt.Serve(&url.URL{Path: "/private"}) {
+		t.Error("expected the middleware to stop /private")
+	}
+	if rt.Serve(&url.URL{Path: "/missing"}) || len(errs) != 1 {
+		t.Errorf("expected a router error for /missing, got %v", errs)
+	}
+	// Outside of the browser, paths are handled synchronously.
+	rt.HandlePath("/post/2")
+	if len(handled) != 2 || handled[0] != "post 1" || handled[1] != "post 2" {
+		t.Errorf("got handled %v", handled)
+	}
+}
+
+func TestTraceHooks(t *testing.T) {
+	var rt = router.NewRouter()
+	rt.Register("panic", "/panic", func(v vars.Vars, u *url.URL) {
+		panic("failed")
+	})
+	rt.Register("home", "/", func(v vars.Vars, u *url.URL) {})
+	rt.Use(func(v vars.Vars, u *url.URL, r *routes.Route, t rterr.ErrorThrower) bool {
+		return true
+	})
+	rt.OnError(func(err error) {})
+	// Every hook which is added is called, not only the last one.
+	var calls = make(map[string]int)
+	for i := 0; i < 2; i++ {
+		rt.TraceMiddleware(func(index int, r *routes.Route, took time.Duration, next bool) {
+			calls["middleware"]++
+		})
+		rt.TraceHandler(func(r *routes.Route, took time.Duration) {
+			calls["handler"]++
+		})
+		rt.OnPanic(func(r *routes.Route, recovered any, stack []byte) {
+			calls["panic"]++
+		})
+	}
+	rt.Serve(&url.URL{Path: "/"})
+	rt.Serve(&url.URL{Path: "/panic"})
+	if calls["middleware"] != 4 || calls["handler"] != 2 || calls["panic"] != 2 {
+		t.Errorf("got calls %v", calls)
+	}
+}
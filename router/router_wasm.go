@@ -6,12 +6,15 @@ package router
 import (
 	"fmt"
 	"net/url"
+	"strconv"
 	"strings"
 
 	"github.com/Nigel2392/jsext"
+	"github.com/Nigel2392/jsext-framework/i18n"
 	"github.com/Nigel2392/jsext-framework/router/routes"
 	"github.com/Nigel2392/jsext-framework/router/rterr"
 	"github.com/Nigel2392/jsext-framework/router/vars"
+	"github.com/Nigel2392/jsext-framework/theme"
 )
 
 // Default router error to be displayed if one occurs.
@@ -24,25 +27,25 @@ func DefaultRouterErrorDisplay(err error) {
 	style.Set("type", "text/css")
 	style.Set("id", "jsext-style")
 	style.Set("innerHTML", `
-	.jsext-overlay { position: absolute; top: 0; left: 0; width: 100%; height: 100%; background-color: rgba(0,0,0,0.5); z-index: 1000; display: flex; justify-content: center; align-items: center; }
-	.jsext-modal { background-color: white; padding: 20px; border-radius: 5px; box-shadow: 0 0 10px rgba(0,0,0,0.5); }
+	.jsext-overlay { position: absolute; top: 0; left: 0; width: 100%; height: 100%; background-color: `+theme.Overlay+`; z-index: 1000; display: flex; justify-content: center; align-items: center; }
+	.jsext-modal { background-color: `+theme.Surface+`; color: `+theme.Text+`; padding: 20px; border-radius: 5px; box-shadow: 0 0 10px `+theme.Shadow+`; }
 	.jsext-modal h1 { margin-top: 5px; font-size: 25px; margin: 0; }
 	.jsext-modal p { margin-top: 5px; font-size: 20px; margin: 0; }
-	.jsext-modal button { margin-top: 5px; padding: 10px 20px; border: none; border-radius: 5px; background-color: #9200ff; color: white; font-size: 15px; cursor: pointer; }
-	.jsext-modal button:hover { background-color: #a200ff; }`)
+	.jsext-modal button { margin-top: 5px; padding: 10px 20px; border: none; border-radius: 5px; background-color: `+theme.Accent+`; color: `+theme.OnAccent+`; font-size: 15px; cursor: pointer; }
+	.jsext-modal button:hover { background-color: `+theme.AccentHover+`; }`)
 	var overlay = jsext.CreateElement("div")
 	overlay.ClassList().Add("jsext-overlay")
 	var modal = jsext.CreateElement("div")
 	modal.ClassList().Add("jsext-modal")
 	var title = jsext.CreateElement("h1")
 	title.ClassList().Add("jsext-modal-title")
-	title.InnerHTML("Error")
+	title.InnerHTML(i18n.T("router.error.title"))
 	var message = jsext.CreateElement("p")
 	message.ClassList().Add("jsext-modal-message")
-	message.InnerHTML(rtErr.Error())
+	message.InnerHTML(translateError(rtErr))
 	var button = jsext.CreateElement("button")
 	button.ClassList().Add("jsext-modal-button")
-	button.InnerHTML("Close")
+	button.InnerHTML(i18n.T("router.error.close"))
 	overlay.AddEventListener("click", func(t jsext.Value, event jsext.Event) {
 		event.PreventDefault()
 		overlay.Remove()
@@ -55,6 +58,20 @@ func DefaultRouterErrorDisplay(err error) {
 	jsext.Body.AppendChild(overlay)
 }
 
+// Translate the error for display, with the default translator.
+// The error itself is not translated, so its text does not depend on the locale.
+func translateError(err rterr.RouterError) string {
+	var message = err.Message
+	var key = "error." + strconv.Itoa(err.Code)
+	switch {
+	case strings.HasPrefix(message, rterr.NoRoute):
+		message = i18n.T("router.no_route", i18n.Args{"path": strings.TrimPrefix(message, rterr.NoRoute)})
+	case message == rterr.NewError(err.Code).Message && i18n.Default.Has(key):
+		message = i18n.T(key)
+	}
+	return i18n.T("error.format", i18n.Args{"code": strconv.Itoa(err.Code), "message": message})
+}
+
 // Get a route by index.
 func (r *Router) GetIndex(i int) *routes.Route {
 	return r.routes[i]
@@ -147,6 +164,21 @@ func (r *Router) Run() {
 // Handle is the main router handler.
 // This function is called by the router to match and handle a route.
 func (r *Router) Handle(u *url.URL) {
+	r.handle(u, true)
+}
+
+// Handle the current location again, without adding a history entry.
+// This renders the current page again, for example after the locale changed.
+func (r *Router) Reload() {
+	var u, err = url.Parse(jsext.Window.Get("location").Get("href").String())
+	if err != nil {
+		r.onErr(err)
+		return
+	}
+	r.handle(u, false)
+}
+
+func (r *Router) handle(u *url.URL, push bool) {
 	if len(r.routes) == 0 {
 		var style = jsext.CreateElement("style")
 		style.Set("type", "text/css")
@@ -187,34 +219,14 @@ func (r *Router) Handle(u *url.URL) {
 	go func() {
 		var rt, vars, ok = r.Match(u.Path)
 		if !ok {
-			var err = rterr.NewError(404, "no route found for path: "+u.Path)
-			if r.onErr == nil {
-				panic(err)
-			} else {
-				r.onErr(error(err))
-				return //false
-			}
-		}
-		if r.lastRoute != nil && r.lastRoute.OnLeave != nil {
-			r.lastRoute.OnLeave(vars, u)
-		}
-		if r.onPageChange != nil {
-			r.onPageChange(vars, u)
+			r.notFound(u)
+			return
 		}
-		if rt.Callable != nil {
-			go func() {
-				for _, m := range r.middlewares {
-					if !m(vars, u, rt, r) {
-						return
-					}
-				}
-				rt.Callable(vars, u)
-				if r.afterPageChange != nil {
-					r.afterPageChange(vars, u)
-				}
-			}()
+		r.change(vars, u)
+		go r.serve(rt, vars, u)
+		if push {
+			jsext.Window.Get("history").Call("pushState", nil, "", u.String())
 		}
-		jsext.Window.Get("history").Call("pushState", nil, "", u.String())
 		if r.nameToTitle {
 			jsext.Document.Set("title", simpleToTitle(rt.Name))
 		}
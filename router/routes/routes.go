@@ -60,6 +60,34 @@ type Route struct {
 	SkipTrailingSlash bool
 	// Children of the route.
 	Children []*Route
+	// Metadata of the route, read by middleware.
+	Meta map[string]interface{}
+
+	parent *Route
+}
+
+// Set metadata of the route, it is inherited by the children of the route.
+func (r *Route) SetMeta(key string, value interface{}) *Route {
+	if r.Meta == nil {
+		r.Meta = make(map[string]interface{})
+	}
+	r.Meta[key] = value
+	return r
+}
+
+// Get metadata of the route, or of the closest parent which has it.
+func (r *Route) GetMeta(key string) (interface{}, bool) {
+	for rt := r; rt != nil; rt = rt.parent {
+		if v, ok := rt.Meta[key]; ok {
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+// Parent of the route, nil for routes registered on the router.
+func (r *Route) Parent() *Route {
+	return r.parent
 }
 
 func (r *Route) String() string {
@@ -126,7 +154,7 @@ func (r *Route) Register(name, path string, callable func(v vars.Vars, u *url.UR
 	var showNameSlice = strings.Split(name, ":")
 	var showName = showNameSlice[len(showNameSlice)-1]
 
-	var route = &Route{Name: showName, Internal_name: name, Path: path, Callable: callable, SkipTrailingSlash: r.SkipTrailingSlash}
+	var route = &Route{Name: showName, Internal_name: name, Path: path, Callable: callable, SkipTrailingSlash: r.SkipTrailingSlash, parent: r}
 	r.Children = append(r.Children, route)
 	return route
 }
@@ -18,6 +18,10 @@ const (
 	ErrCodeServiceUnavailable = 503 // Service unavailable
 )
 
+// Message of the router error when no route matches the path, followed by the path.
+// Shared by the routers, so the error can be recognized and translated.
+const NoRoute = "no route found for path: "
+
 type ErrorThrower interface {
 	Error(code int, message string) RouterError
 	Throw(code int)
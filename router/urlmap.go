@@ -1,26 +1,116 @@
+//go:build js && wasm
+// +build js,wasm
+
 package router
 
 import (
 	"strings"
+	"sync"
+	"syscall/js"
 
 	"github.com/Nigel2392/jsext"
 	"github.com/Nigel2392/jsext/elements"
 )
 
+// Filters of the links in url maps, and the url maps they apply to.
+var (
+	urlFiltersMu sync.Mutex
+	urlFilters   []*func(key string) bool
+	urlMaps      []*URLs
+)
+
+// Hide the links of all url maps for which visible reports false, by the key of the link.
+// Call UpdateURLs when the result of visible changes, and remove to show the links again.
+func FilterURLs(visible func(key string) bool) (remove func()) {
+	var f = &visible
+	urlFiltersMu.Lock()
+	urlFilters = append(urlFilters, f)
+	urlFiltersMu.Unlock()
+	UpdateURLs()
+	return func() {
+		urlFiltersMu.Lock()
+		for i, filter := range urlFilters {
+			if filter == f {
+				urlFilters = append(urlFilters[:i], urlFilters[i+1:]...)
+				break
+			}
+		}
+		urlFiltersMu.Unlock()
+		UpdateURLs()
+	}
+}
+
+// Show or hide the links of all url maps, after the result of a filter changed.
+func UpdateURLs() {
+	urlFiltersMu.Lock()
+	var maps = append([]*URLs(nil), urlMaps...)
+	urlFiltersMu.Unlock()
+	for _, u := range maps {
+		for _, key := range u.Keys() {
+			u.filter(key)
+		}
+	}
+}
+
+// Check if the link passes all filters.
+func urlVisible(key string) bool {
+	urlFiltersMu.Lock()
+	var filters = append([]*func(string) bool(nil), urlFilters...)
+	urlFiltersMu.Unlock()
+	for _, f := range filters {
+		if !(*f)(key) {
+			return false
+		}
+	}
+	return true
+}
+
 // A url map element
 type URLs struct {
 	order []string
 	urls  map[string]*elements.Element
+	// Keys of the links which are hidden by a filter.
+	filtered map[string]bool
 	// mu    *sync.Mutex
 }
 
-// Create a new url map
+// Create a new url map,
+// links for which a filter of FilterURLs reports false are hidden.
+// The map is kept to update its links when the filters change, call Release when it is no longer used.
 func NewURLs() *URLs {
-	return &URLs{
-		urls:  make(map[string]*elements.Element),
-		order: make([]string, 0),
+	var u = &URLs{
+		urls:     make(map[string]*elements.Element),
+		order:    make([]string, 0),
+		filtered: make(map[string]bool),
 		// mu:    &sync.Mutex{},
 	}
+	urlFiltersMu.Lock()
+	urlMaps = append(urlMaps, u)
+	urlFiltersMu.Unlock()
+	return u
+}
+
+// Stop updating the links of the map when the filters change, so the map can be garbage collected.
+func (u *URLs) Release() {
+	urlFiltersMu.Lock()
+	defer urlFiltersMu.Unlock()
+	for i, m := range urlMaps {
+		if m == u {
+			urlMaps = append(urlMaps[:i:i], urlMaps[i+1:]...)
+			return
+		}
+	}
+}
+
+// Hide the link if a filter rejects it, or show it if it was hidden by a filter.
+// Links which were not hidden by a filter are left as they are.
+func (u *URLs) filter(key string) {
+	var hidden = !urlVisible(key)
+	if hidden == u.filtered[key] {
+		return
+	}
+	u.filtered[key] = hidden
+	u.SetHidden(hidden, key)
 }
 
 // Get a url element from the map
@@ -49,6 +139,7 @@ func (u *URLs) Set(key string, value *elements.Element, external ...bool) {
 	u.urls[key] = value
 	u.order = append(u.order, key)
 	// u.mu.Unlock()
+	u.filter(key)
 }
 
 func (u *URLs) SetRaw(key string, value *elements.Element) {
@@ -60,6 +151,7 @@ func (u *URLs) SetRaw(key string, value *elements.Element) {
 	u.urls[key] = value
 	u.order = append(u.order, key)
 	// u.mu.Unlock()
+	u.filter(key)
 }
 
 // Delete a url element from the map, and remove it from the DOM
@@ -72,6 +164,7 @@ func (u *URLs) Delete(key string) {
 	}
 	// u.mu.Lock()
 	delete(u.urls, key)
+	delete(u.filtered, key)
 	element.Remove()
 	for i, v := range u.order {
 		if v == key {
@@ -116,6 +209,36 @@ func (u *URLs) Show(display string, urlname ...string) {
 	}
 }
 
+// Hide or show a list of urls, without changing their other styles.
+// Unlike Show, the display of a shown url is not set, so the stylesheet decides.
+func (u *URLs) SetHidden(hidden bool, urlname ...string) {
+	for _, v := range urlname {
+		var url = u.Get(v)
+		if url == nil {
+			continue
+		}
+		var styles = make([]string, 0, len(url.Attributes_Semicolon["style"])+1)
+		for _, style := range url.Attributes_Semicolon["style"] {
+			if strings.ReplaceAll(style, " ", "") != "display:none" {
+				styles = append(styles, style)
+			}
+		}
+		if hidden {
+			styles = append(styles, "display:none")
+		}
+		url.Attributes_Semicolon["style"] = styles
+		var value = js.Value(url.JSExtElement())
+		if value.IsUndefined() {
+			continue
+		}
+		if hidden {
+			value.Get("style").Call("setProperty", "display", "none")
+		} else {
+			value.Get("style").Call("removeProperty", "display")
+		}
+	}
+}
+
 // Loop through all urls in order
 func (u *URLs) InOrder(reverse ...bool) []*elements.Element {
 	var ret = make([]*elements.Element, 0)
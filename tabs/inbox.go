@@ -0,0 +1,70 @@
+package tabs
+
+import "sync"
+
+// Inbox delivers the messages of a transport to the listener in the order they were received,
+// from a single goroutine.
+//
+// Pushing never blocks, so it is safe to push from javascript event handlers,
+// while the listener may block without holding up the event loop.
+type inbox struct {
+	f      func(Message)
+	queue  []Message
+	signal chan struct{}
+	done   chan struct{}
+	once   sync.Once
+	mu     sync.Mutex
+}
+
+func newInbox(f func(Message)) *inbox {
+	var in = &inbox{
+		f:      f,
+		signal: make(chan struct{}, 1),
+		done:   make(chan struct{}),
+	}
+	go in.run()
+	return in
+}
+
+func (in *inbox) push(msg Message) {
+	in.mu.Lock()
+	in.queue = append(in.queue, msg)
+	in.mu.Unlock()
+	select {
+	case in.signal <- struct{}{}:
+	default:
+	}
+}
+
+// Stop delivering messages, queued messages are dropped.
+func (in *inbox) close() {
+	in.once.Do(func() {
+		close(in.done)
+	})
+}
+
+func (in *inbox) run() {
+	for {
+		select {
+		case <-in.done:
+			return
+		case <-in.signal:
+		}
+		for {
+			in.mu.Lock()
+			if len(in.queue) == 0 {
+				in.mu.Unlock()
+				break
+			}
+			var msg = in.queue[0]
+			in.queue = in.queue[1:]
+			in.mu.Unlock()
+			select {
+			case <-in.done:
+				return
+			default:
+			}
+			in.f(msg)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package tabs
+
+import "sync"
+
+// Hub connecting memory transports, to test synchronization without a browser.
+type Hub struct {
+	listeners map[int]*inbox
+	nextID    int
+	mu        *sync.Mutex
+}
+
+// Initialize a new hub.
+func NewHub() *Hub {
+	return &Hub{
+		listeners: make(map[int]*inbox),
+		mu:        &sync.Mutex{},
+	}
+}
+
+// Create a transport connected to the hub, one per simulated tab.
+func (h *Hub) Transport() Transport {
+	return &memoryTransport{hub: h}
+}
+
+type memoryTransport struct {
+	hub    *Hub
+	ids    []int
+	closed bool
+}
+
+func (t *memoryTransport) Send(msg Message) error {
+	t.hub.mu.Lock()
+	if t.closed {
+		t.hub.mu.Unlock()
+		return nil
+	}
+	// Push while the hub is locked, so every tab receives the messages in the order they were sent.
+	for id, in := range t.hub.listeners {
+		if !t.owns(id) {
+			in.push(msg)
+		}
+	}
+	t.hub.mu.Unlock()
+	return nil
+}
+
+func (t *memoryTransport) Listen(f func(Message)) (stop func()) {
+	t.hub.mu.Lock()
+	var id = t.hub.nextID
+	t.hub.nextID++
+	var in = newInbox(f)
+	t.hub.listeners[id] = in
+	t.ids = append(t.ids, id)
+	t.hub.mu.Unlock()
+	return func() {
+		t.hub.mu.Lock()
+		delete(t.hub.listeners, id)
+		t.hub.mu.Unlock()
+		in.close()
+	}
+}
+
+func (t *memoryTransport) Close() error {
+	t.hub.mu.Lock()
+	t.closed = true
+	for _, id := range t.ids {
+		if in, ok := t.hub.listeners[id]; ok {
+			in.close()
+			delete(t.hub.listeners, id)
+		}
+	}
+	t.hub.mu.Unlock()
+	return nil
+}
+
+// Must be called with the hub locked.
+func (t *memoryTransport) owns(id int) bool {
+	for _, i := range t.ids {
+		if i == id {
+			return true
+		}
+	}
+	return false
+}
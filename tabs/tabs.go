@@ -0,0 +1,309 @@
+package tabs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Nigel2392/jsext-framework/helpers/hooks"
+)
+
+// Message types sent between tabs.
+const (
+	TypeData      = "data"
+	TypeEvent     = "event"
+	TypeHello     = "hello"
+	TypeHeartbeat = "heartbeat"
+	TypeBye       = "bye"
+)
+
+// Message sent between tabs.
+type Message struct {
+	Type  string          `json:"type"`
+	Tab   string          `json:"tab"`
+	Key   string          `json:"key,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// Transport to send messages to the other tabs.
+// Messages sent by a tab are not received by the same tab.
+type Transport interface {
+	Send(msg Message) error
+	// Call the function for every message received from other tabs.
+	Listen(f func(msg Message)) (stop func())
+	Close() error
+}
+
+// Options for tab synchronization.
+type Options struct {
+	// Interval at which the tab tells the other tabs it is still open.
+	Heartbeat time.Duration
+	// Time after which a tab which has not sent a heartbeat is considered closed.
+	Timeout time.Duration
+}
+
+func (o *Options) SetDefaults() {
+	if o.Heartbeat == 0 {
+		o.Heartbeat = time.Second
+	}
+	if o.Timeout == 0 {
+		o.Timeout = 3 * o.Heartbeat
+	}
+}
+
+// Tabs synchronizes data and events between the open tabs of the application,
+// and elects a single leader among the tabs.
+//
+// The oldest open tab is the leader.
+// Use RunAsLeader for background jobs which should only run in one tab, such as polling.
+type Tabs struct {
+	id        string
+	opts      Options
+	transport Transport
+	peers     map[string]time.Time
+	leader    bool
+	elected   bool
+	data      *hooks.Hooks[func(key string, value json.RawMessage)]
+	events    map[string]*hooks.Hooks[func(payload json.RawMessage)]
+	onLeader  *hooks.Hooks[func(leader bool)]
+	stop      func()
+	done      chan struct{}
+	mu        *sync.Mutex
+}
+
+// Start synchronizing with the other tabs over the transport.
+func New(transport Transport, opts *Options) *Tabs {
+	var t = &Tabs{
+		id:        fmt.Sprintf("%020d-%06d", time.Now().UnixNano(), rand.Intn(1000000)),
+		transport: transport,
+		peers:     make(map[string]time.Time),
+		data:      &hooks.Hooks[func(string, json.RawMessage)]{},
+		events:    make(map[string]*hooks.Hooks[func(json.RawMessage)]),
+		onLeader:  &hooks.Hooks[func(bool)]{},
+		done:      make(chan struct{}),
+		mu:        &sync.Mutex{},
+	}
+	if opts != nil {
+		t.opts = *opts
+	}
+	t.opts.SetDefaults()
+	t.stop = transport.Listen(t.receive)
+	transport.Send(Message{Type: TypeHello, Tab: t.id})
+	go t.run()
+	return t
+}
+
+// ID of this tab.
+// IDs of tabs opened later sort after the IDs of tabs opened earlier.
+func (t *Tabs) ID() string {
+	return t.id
+}
+
+// IDs of the other open tabs.
+func (t *Tabs) Peers() []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var peers = make([]string, 0, len(t.peers))
+	for id := range t.peers {
+		peers = append(peers, id)
+	}
+	sort.Strings(peers)
+	return peers
+}
+
+// Stop synchronizing, and tell the other tabs this tab is closed.
+func (t *Tabs) Close() error {
+	t.mu.Lock()
+	select {
+	case <-t.done:
+		t.mu.Unlock()
+		return nil
+	default:
+	}
+	close(t.done)
+	t.mu.Unlock()
+	t.stop()
+	t.transport.Send(Message{Type: TypeBye, Tab: t.id})
+	t.setLeader(false)
+	return t.transport.Close()
+}
+
+// Send a value to the other tabs.
+func (t *Tabs) Set(key string, value any) error {
+	var b, err = json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return t.transport.Send(Message{Type: TypeData, Tab: t.id, Key: key, Value: b})
+}
+
+// Call the function when another tab sends a value.
+// The returned function removes the listener.
+func (t *Tabs) OnData(f func(key string, value json.RawMessage)) (remove func()) {
+	return t.data.Add(f)
+}
+
+// Send an event to the other tabs.
+func (t *Tabs) Emit(event string, payload any) error {
+	var b, err = json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	return t.transport.Send(Message{Type: TypeEvent, Tab: t.id, Key: event, Value: b})
+}
+
+// Call the function when another tab emits the event.
+// The returned function removes the listener.
+func (t *Tabs) On(event string, f func(payload json.RawMessage)) (remove func()) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.events[event] == nil {
+		t.events[event] = &hooks.Hooks[func(json.RawMessage)]{}
+	}
+	return t.events[event].Add(f)
+}
+
+// Check if this tab is the leader.
+func (t *Tabs) IsLeader() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.leader
+}
+
+// Call the function when this tab becomes, or stops being, the leader.
+// The returned function removes the listener.
+func (t *Tabs) OnLeaderChange(f func(leader bool)) (remove func()) {
+	return t.onLeader.Add(f)
+}
+
+// Run the job while this tab is the leader.
+// The context is cancelled when the tab stops being the leader, or when it is closed.
+// The job is started again when the tab becomes the leader again.
+func (t *Tabs) RunAsLeader(job func(ctx context.Context)) (remove func()) {
+	var mu sync.Mutex
+	var cancel context.CancelFunc
+	var change = func(leader bool) {
+		mu.Lock()
+		defer mu.Unlock()
+		if cancel != nil {
+			cancel()
+			cancel = nil
+		}
+		if leader {
+			var ctx context.Context
+			ctx, cancel = context.WithCancel(context.Background())
+			go job(ctx)
+		}
+	}
+	var removeListener = t.OnLeaderChange(change)
+	if t.IsLeader() {
+		change(true)
+	}
+	return func() {
+		removeListener()
+		change(false)
+	}
+}
+
+func (t *Tabs) run() {
+	var ticker = time.NewTicker(t.opts.Heartbeat)
+	defer ticker.Stop()
+	// Wait for the other tabs to answer the hello before electing a leader.
+	var start = time.After(t.opts.Heartbeat)
+	for {
+		select {
+		case <-t.done:
+			return
+		case <-start:
+			t.mu.Lock()
+			t.elected = true
+			t.mu.Unlock()
+			t.elect()
+		case <-ticker.C:
+			t.transport.Send(Message{Type: TypeHeartbeat, Tab: t.id})
+			t.elect()
+		}
+	}
+}
+
+func (t *Tabs) receive(msg Message) {
+	if msg.Tab == t.id {
+		return
+	}
+	select {
+	case <-t.done:
+		return
+	default:
+	}
+	switch msg.Type {
+	case TypeHello, TypeHeartbeat:
+		t.mu.Lock()
+		var _, known = t.peers[msg.Tab]
+		t.peers[msg.Tab] = time.Now()
+		t.mu.Unlock()
+		if msg.Type == TypeHello {
+			t.transport.Send(Message{Type: TypeHeartbeat, Tab: t.id})
+		}
+		if !known {
+			t.elect()
+		}
+	case TypeBye:
+		t.mu.Lock()
+		delete(t.peers, msg.Tab)
+		t.mu.Unlock()
+		t.elect()
+	case TypeData:
+		for _, f := range t.data.List() {
+			f(msg.Key, msg.Value)
+		}
+	case TypeEvent:
+		t.mu.Lock()
+		var event = t.events[msg.Key]
+		t.mu.Unlock()
+		if event == nil {
+			return
+		}
+		for _, f := range event.List() {
+			f(msg.Value)
+		}
+	}
+}
+
+// Remove closed tabs, and check if this tab is the oldest open tab.
+func (t *Tabs) elect() {
+	t.mu.Lock()
+	if !t.elected {
+		t.mu.Unlock()
+		return
+	}
+	var leader = true
+	for id, seen := range t.peers {
+		if time.Since(seen) > t.opts.Timeout {
+			delete(t.peers, id)
+			continue
+		}
+		if id < t.id {
+			leader = false
+		}
+	}
+	t.mu.Unlock()
+	t.setLeader(leader)
+}
+
+func (t *Tabs) setLeader(leader bool) {
+	t.mu.Lock()
+	if t.leader == leader {
+		t.mu.Unlock()
+		return
+	}
+	t.leader = leader
+	var listeners = t.onLeader.List()
+	t.mu.Unlock()
+	for _, f := range listeners {
+		f(leader)
+	}
+}
@@ -0,0 +1,156 @@
+package tabs_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/Nigel2392/jsext-framework/tabs"
+)
+
+var opts = &tabs.Options{Heartbeat: 10 * time.Millisecond}
+
+// Wait until the condition is true, or fail after a second.
+func eventually(t *testing.T, cond func() bool) {
+	t.Helper()
+	var deadline = time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met in time")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestData(t *testing.T) {
+	var hub = tabs.NewHub()
+	var a = tabs.New(hub.Transport(), opts)
+	defer a.Close()
+	var b = tabs.New(hub.Transport(), opts)
+	defer b.Close()
+
+	var received = make(chan string, 1)
+	b.OnData(func(key string, value json.RawMessage) {
+		received <- key + "=" + string(value)
+	})
+	a.OnData(func(key string, value json.RawMessage) {
+		t.Error("tab received its own data")
+	})
+	if err := a.Set("user", "admin"); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case got := <-received:
+		if got != `user="admin"` {
+			t.Errorf("got %s", got)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("data not received")
+	}
+}
+
+func TestEvents(t *testing.T) {
+	var hub = tabs.NewHub()
+	var a = tabs.New(hub.Transport(), opts)
+	defer a.Close()
+	var b = tabs.New(hub.Transport(), opts)
+	defer b.Close()
+
+	var logout = make(chan struct{}, 1)
+	var remove = b.On("logout", func(payload json.RawMessage) {
+		logout <- struct{}{}
+	})
+	b.On("other", func(payload json.RawMessage) {
+		t.Error("wrong event received")
+	})
+	a.Emit("logout", nil)
+	select {
+	case <-logout:
+	case <-time.After(time.Second):
+		t.Fatal("event not received")
+	}
+	remove()
+	a.Emit("logout", nil)
+	select {
+	case <-logout:
+		t.Error("removed listener was called")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestEventOrder(t *testing.T) {
+	var hub = tabs.NewHub()
+	var a = tabs.New(hub.Transport(), opts)
+	defer a.Close()
+	var b = tabs.New(hub.Transport(), opts)
+	defer b.Close()
+
+	var received = make(chan int, 100)
+	b.On("count", func(payload json.RawMessage) {
+		var i int
+		json.Unmarshal(payload, &i)
+		received <- i
+	})
+	for i := 0; i < 100; i++ {
+		a.Emit("count", i)
+	}
+	for i := 0; i < 100; i++ {
+		select {
+		case got := <-received:
+			if got != i {
+				t.Fatalf("expected event %d, got %d", i, got)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("event %d not received", i)
+		}
+	}
+}
+
+func TestLeader(t *testing.T) {
+	var hub = tabs.NewHub()
+	var a = tabs.New(hub.Transport(), opts)
+	defer a.Close()
+	time.Sleep(time.Millisecond)
+	var b = tabs.New(hub.Transport(), opts)
+	defer b.Close()
+
+	eventually(t, func() bool { return a.IsLeader() && len(b.Peers()) == 1 })
+	time.Sleep(50 * time.Millisecond)
+	if b.IsLeader() {
+		t.Fatal("both tabs are the leader")
+	}
+
+	var running = make(chan context.Context, 1)
+	b.RunAsLeader(func(ctx context.Context) {
+		running <- ctx
+	})
+	a.Close()
+	var ctx context.Context
+	select {
+	case ctx = <-running:
+	case <-time.After(time.Second):
+		t.Fatal("job did not start on the new leader")
+	}
+	b.Close()
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("job not cancelled when the tab closed")
+	}
+}
+
+func TestLeaderTimeout(t *testing.T) {
+	var hub = tabs.NewHub()
+	var transport = hub.Transport()
+	var a = tabs.New(transport, opts)
+	time.Sleep(time.Millisecond)
+	var b = tabs.New(hub.Transport(), opts)
+	defer b.Close()
+	eventually(t, a.IsLeader)
+
+	// Disconnect the leader without saying goodbye, as a crashed tab would.
+	transport.Close()
+	eventually(t, b.IsLeader)
+	a.Close()
+}
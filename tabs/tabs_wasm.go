@@ -0,0 +1,148 @@
+//go:build js && wasm
+// +build js,wasm
+
+package tabs
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall/js"
+	"time"
+
+	"github.com/Nigel2392/jsext"
+)
+
+// Create a transport for the channel name.
+// Uses a BroadcastChannel, and falls back to storage events when it is not supported.
+func NewTransport(name string) Transport {
+	if jsext.Global.Get("BroadcastChannel").IsUndefined() {
+		return NewStorageTransport(name)
+	}
+	return NewBroadcastTransport(name)
+}
+
+// Transport over a BroadcastChannel.
+type BroadcastTransport struct {
+	channel js.Value
+}
+
+// Open the BroadcastChannel with the name.
+func NewBroadcastTransport(name string) *BroadcastTransport {
+	return &BroadcastTransport{channel: jsext.Global.Get("BroadcastChannel").New(name)}
+}
+
+func (t *BroadcastTransport) Send(msg Message) error {
+	var data, err = json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	t.channel.Call("postMessage", string(data))
+	return nil
+}
+
+func (t *BroadcastTransport) Listen(f func(Message)) (stop func()) {
+	var in = newInbox(f)
+	var onMessage = js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		var data = args[0].Get("data")
+		if data.Type() != js.TypeString {
+			return nil
+		}
+		var msg Message
+		if err := json.Unmarshal([]byte(data.String()), &msg); err == nil {
+			in.push(msg)
+		}
+		return nil
+	})
+	t.channel.Call("addEventListener", "message", onMessage)
+	return func() {
+		t.channel.Call("removeEventListener", "message", onMessage)
+		onMessage.Release()
+		in.close()
+	}
+}
+
+func (t *BroadcastTransport) Close() error {
+	t.channel.Call("close")
+	return nil
+}
+
+// Transport over storage events of localStorage, for browsers without BroadcastChannel.
+//
+// Messages are written to the key and removed right away,
+// the storage event is fired in the other tabs of the same origin.
+type StorageTransport struct {
+	key    string
+	nextID int
+	mu     sync.Mutex
+}
+
+// Create a storage transport writing to the key.
+func NewStorageTransport(key string) *StorageTransport {
+	return &StorageTransport{key: key}
+}
+
+func (t *StorageTransport) Send(msg Message) error {
+	var data, err = json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	// Prefix a unique id, storage events are not fired when the value does not change.
+	t.mu.Lock()
+	t.nextID++
+	var id = strconv.FormatInt(time.Now().UnixNano(), 36) + "-" + strconv.Itoa(t.nextID)
+	t.mu.Unlock()
+	var storage = jsext.Global.Get("localStorage")
+	storage.Call("setItem", t.key, id+" "+string(data))
+	storage.Call("removeItem", t.key)
+	return nil
+}
+
+func (t *StorageTransport) Listen(f func(Message)) (stop func()) {
+	var in = newInbox(f)
+	var onStorage = js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		var event = args[0]
+		if event.Get("key").String() != t.key {
+			return nil
+		}
+		var value = event.Get("newValue")
+		if value.IsNull() || value.IsUndefined() {
+			return nil
+		}
+		var data = value.String()
+		data = data[strings.IndexByte(data, ' ')+1:]
+		var msg Message
+		if err := json.Unmarshal([]byte(data), &msg); err == nil {
+			in.push(msg)
+		}
+		return nil
+	})
+	var window = js.Value(jsext.Window)
+	window.Call("addEventListener", "storage", onStorage)
+	return func() {
+		window.Call("removeEventListener", "storage", onStorage)
+		onStorage.Release()
+		in.close()
+	}
+}
+
+func (t *StorageTransport) Close() error {
+	return nil
+}
+
+// Close the tabs when the page is unloaded,
+// so that the other tabs elect a new leader right away.
+// The returned function stops listening.
+func CloseOnUnload(t *Tabs) (stop func()) {
+	var onUnload = js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		t.Close()
+		return nil
+	})
+	var window = js.Value(jsext.Window)
+	window.Call("addEventListener", "pagehide", onUnload)
+	return func() {
+		window.Call("removeEventListener", "pagehide", onUnload)
+		onUnload.Release()
+	}
+}
@@ -0,0 +1,95 @@
+package telemetry
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Replacement of redacted values.
+const Redacted = "[redacted]"
+
+// Default keys of which the values are redacted.
+var DefaultSensitiveKeys = []string{
+	"pass", "secret", "token", "auth", "key", "session", "cookie",
+	"email", "phone", "ssn", "card", "cvc", "iban",
+}
+
+var textScrubbers = []struct {
+	re   *regexp.Regexp
+	repl string
+}{
+	// JSON web tokens.
+	{regexp.MustCompile(`eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`), "[token]"},
+	{regexp.MustCompile(`(?i)(bearer|basic)\s+[A-Za-z0-9._~+/=-]+`), "$1 [token]"},
+	{regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`), "[email]"},
+	// Phone, card and account numbers, with optional separators.
+	{regexp.MustCompile(`\+?\d(?:[ -]?\d){8,}`), "[number]"},
+}
+
+// Remove emails, tokens and long numbers from text.
+func ScrubText(s string) string {
+	for _, scrubber := range textScrubbers {
+		s = scrubber.re.ReplaceAllString(s, scrubber.repl)
+	}
+	return s
+}
+
+// Check if a key is sensitive.
+func sensitive(key string, keys []string) bool {
+	key = strings.ToLower(key)
+	for _, k := range keys {
+		if strings.Contains(key, k) {
+			return true
+		}
+	}
+	return false
+}
+
+// Remove the user info and fragment of a URL, and redact sensitive query parameters.
+func ScrubURL(raw string, keys []string) string {
+	var u, err = url.Parse(raw)
+	if err != nil {
+		return ScrubText(raw)
+	}
+	u.User = nil
+	u.Fragment = ""
+	u.RawFragment = ""
+	if u.RawQuery != "" {
+		var query = u.Query()
+		for k := range query {
+			if sensitive(k, keys) {
+				query[k] = []string{Redacted}
+			}
+		}
+		u.RawQuery = query.Encode()
+	}
+	return ScrubText(u.String())
+}
+
+func (t *Telemetry) scrub(e *Event) {
+	var keys = t.opts.SensitiveKeys
+	if e.Kind == KindRequest {
+		e.Name = ScrubURL(strings.TrimPrefix(e.Name, e.Attrs["method"]+" "), keys)
+		if method := e.Attrs["method"]; method != "" {
+			e.Name = method + " " + e.Name
+		}
+	} else {
+		e.Name = ScrubText(e.Name)
+	}
+	for k, v := range e.Attrs {
+		switch {
+		case sensitive(strings.TrimPrefix(k, "var."), keys):
+			e.Attrs[k] = Redacted
+		case k == "url":
+			e.Attrs[k] = ScrubURL(v, keys)
+		case k == "stack":
+			// Stack traces contain addresses, which look like numbers.
+		default:
+			e.Attrs[k] = ScrubText(v)
+		}
+	}
+	if t.opts.Scrub != nil {
+		t.opts.Scrub(e)
+	}
+}
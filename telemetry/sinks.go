@@ -0,0 +1,104 @@
+package telemetry
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/Nigel2392/jsext-framework/client"
+)
+
+// Adapter to use a function as a sink.
+type SinkFunc func(ctx context.Context, events []Event) error
+
+func (f SinkFunc) Send(ctx context.Context, events []Event) error {
+	return f(ctx, events)
+}
+
+// Sink which keeps the events in memory, for tests.
+type Memory struct {
+	mu     sync.Mutex
+	events []Event
+}
+
+func NewMemory() *Memory {
+	return &Memory{}
+}
+
+func (m *Memory) Send(ctx context.Context, events []Event) error {
+	m.mu.Lock()
+	m.events = append(m.events, events...)
+	m.mu.Unlock()
+	return nil
+}
+
+// Events received by the sink.
+func (m *Memory) Events() []Event {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Event(nil), m.events...)
+}
+
+// Remove the received events.
+func (m *Memory) Reset() {
+	m.mu.Lock()
+	m.events = nil
+	m.mu.Unlock()
+}
+
+// Sink which writes the events as JSON lines.
+func Writer(w io.Writer) Sink {
+	var mu sync.Mutex
+	return SinkFunc(func(ctx context.Context, events []Event) error {
+		mu.Lock()
+		defer mu.Unlock()
+		var enc = json.NewEncoder(w)
+		for _, e := range events {
+			if err := enc.Encode(e); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+type skipKey struct{}
+
+// Context of requests which are not recorded by the interceptor,
+// used for the requests of the HTTP sink.
+func SkipContext(ctx context.Context) context.Context {
+	return context.WithValue(ctx, skipKey{}, true)
+}
+
+// Sink which posts the events to a URL as a JSON array.
+// If c is nil, a client without interceptors is used.
+func HTTP(c *client.Client, url string) Sink {
+	if c == nil {
+		c = client.New()
+	}
+	return SinkFunc(func(ctx context.Context, events []Event) error {
+		return c.Post(SkipContext(ctx), url, events, nil)
+	})
+}
+
+// Client interceptor which records the latency of requests.
+func (t *Telemetry) Interceptor() client.Interceptor {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return client.RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			if skip, _ := r.Context().Value(skipKey{}).(bool); skip || !t.sampled {
+				return next.RoundTrip(r)
+			}
+			var start = time.Now()
+			var resp, err = next.RoundTrip(r)
+			var status int
+			if resp != nil {
+				status = resp.StatusCode
+			}
+			t.Request(r.Method, r.URL.String(), status, time.Since(start), err)
+			return resp, err
+		})
+	}
+}
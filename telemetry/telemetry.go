@@ -0,0 +1,266 @@
+// Package telemetry records page views, timings, requests and errors, and sends them to a sink in batches.
+//
+// Sessions are sampled once, when the telemetry is created.
+// Events are scrubbed before they are queued: values of sensitive keys are redacted,
+// and emails, long numbers and tokens are removed from text.
+package telemetry
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"runtime/debug"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Nigel2392/jsext-framework/router/vars"
+)
+
+// Kinds of events.
+const (
+	KindNavigation = "navigation"
+	KindHandler    = "handler"
+	KindTiming     = "timing"
+	KindRequest    = "request"
+	KindError      = "error"
+	KindPanic      = "panic"
+)
+
+// Maximum length of a recorded stack trace.
+const maxStack = 4096
+
+// Event recorded by the telemetry.
+type Event struct {
+	Kind string `json:"kind"`
+	// Route name, timing name, request method and URL, or error message.
+	Name    string    `json:"name"`
+	Time    time.Time `json:"time"`
+	Session string    `json:"session"`
+	// Duration in milliseconds.
+	Duration float64           `json:"duration,omitempty"`
+	Attrs    map[string]string `json:"attrs,omitempty"`
+}
+
+// Sink receives batches of events.
+type Sink interface {
+	Send(ctx context.Context, events []Event) error
+}
+
+// Options for the telemetry.
+type Options struct {
+	// Fraction of the sessions which are recorded, from 0 to 1, see Rate. Defaults to 1.
+	SampleRate *float64
+	// Amount of events to queue before they are sent, defaults to 20.
+	BatchSize int
+	// Time to wait before sending a batch which is not full, defaults to 5 seconds.
+	FlushInterval time.Duration
+	// Keys of which the values are redacted, compared case-insensitively as substrings.
+	// Defaults to DefaultSensitiveKeys.
+	SensitiveKeys []string
+	// Additional scrubbing of events, after the default scrubbing.
+	Scrub func(e *Event)
+	// Called when a batch could not be sent, the batch is dropped.
+	OnError func(err error)
+}
+
+// Sample rate for the options, from 0 to 1.
+// A rate of 0 records no sessions.
+func Rate(rate float64) *float64 {
+	return &rate
+}
+
+func (o *Options) SetDefaults() {
+	if o.SampleRate == nil || *o.SampleRate > 1 {
+		o.SampleRate = Rate(1)
+	} else if *o.SampleRate < 0 {
+		o.SampleRate = Rate(0)
+	}
+	if o.BatchSize <= 0 {
+		o.BatchSize = 20
+	}
+	if o.FlushInterval <= 0 {
+		o.FlushInterval = 5 * time.Second
+	}
+	if o.SensitiveKeys == nil {
+		o.SensitiveKeys = DefaultSensitiveKeys
+	}
+	if o.OnError == nil {
+		o.OnError = func(err error) {}
+	}
+}
+
+// Telemetry records events and sends them to the sink in batches.
+//
+// Telemetry is safe for concurrent use.
+type Telemetry struct {
+	sink    Sink
+	opts    Options
+	session string
+	sampled bool
+	mu      sync.Mutex
+	queue   []Event
+	timer   *time.Timer
+	closed  bool
+}
+
+// Create new telemetry sending to the sink.
+func New(sink Sink, opts *Options) *Telemetry {
+	var o Options
+	if opts != nil {
+		o = *opts
+	}
+	o.SetDefaults()
+	var b [8]byte
+	rand.Read(b[:])
+	var sample = float64(binary.BigEndian.Uint64(b[:])>>11) / (1 << 53)
+	return &Telemetry{
+		sink:    sink,
+		opts:    o,
+		session: hex.EncodeToString(b[:]),
+		sampled: sample < *o.SampleRate,
+	}
+}
+
+// Random ID of the session.
+func (t *Telemetry) Session() string {
+	return t.session
+}
+
+// Check if the session is recorded.
+func (t *Telemetry) Sampled() bool {
+	return t.sampled
+}
+
+// Record an event, it is scrubbed and queued.
+func (t *Telemetry) Record(e Event) {
+	if !t.sampled {
+		return
+	}
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	e.Session = t.session
+	t.scrub(&e)
+
+	t.mu.Lock()
+	if t.closed {
+		t.mu.Unlock()
+		return
+	}
+	t.queue = append(t.queue, e)
+	var full = len(t.queue) >= t.opts.BatchSize
+	if !full && t.timer == nil {
+		t.timer = time.AfterFunc(t.opts.FlushInterval, func() {
+			t.Flush(context.Background())
+		})
+	}
+	t.mu.Unlock()
+	if full {
+		go t.Flush(context.Background())
+	}
+}
+
+// Record a navigation to a route.
+func (t *Telemetry) Navigation(route string, v vars.Vars, u *url.URL) {
+	var attrs = make(map[string]string, len(v)+1)
+	for k, val := range v {
+		attrs["var."+k] = val
+	}
+	if u != nil {
+		attrs["url"] = u.String()
+	}
+	t.Record(Event{Kind: KindNavigation, Name: route, Attrs: attrs})
+}
+
+// Record the time a route handler took.
+func (t *Telemetry) Handler(route string, took time.Duration) {
+	t.Record(Event{Kind: KindHandler, Name: route, Duration: millis(took)})
+}
+
+// Record a custom timing.
+func (t *Telemetry) Timing(name string, took time.Duration, attrs map[string]string) {
+	t.Record(Event{Kind: KindTiming, Name: name, Duration: millis(took), Attrs: attrs})
+}
+
+// Record a request, status is 0 if no response was received.
+func (t *Telemetry) Request(method, url string, status int, took time.Duration, err error) {
+	var attrs = map[string]string{"method": method, "url": url}
+	if status != 0 {
+		attrs["status"] = strconv.Itoa(status)
+	}
+	if err != nil {
+		attrs["error"] = err.Error()
+	}
+	t.Record(Event{Kind: KindRequest, Name: method + " " + url, Duration: millis(took), Attrs: attrs})
+}
+
+// Record an error.
+func (t *Telemetry) Error(err error) {
+	if err == nil {
+		return
+	}
+	t.Record(Event{Kind: KindError, Name: err.Error(), Attrs: map[string]string{"type": fmt.Sprintf("%T", err)}})
+}
+
+// Record a recovered panic, with the stack trace.
+func (t *Telemetry) Panic(where string, recovered any, stack []byte) {
+	if len(stack) > maxStack {
+		stack = stack[:maxStack]
+	}
+	t.Record(Event{Kind: KindPanic, Name: fmt.Sprint(recovered), Attrs: map[string]string{
+		"where": where,
+		"stack": string(stack),
+	}})
+}
+
+// Record a panic and panic again, use it with defer in goroutines:
+//
+//	go func() {
+//		defer t.Recover("sync")
+//		...
+//	}()
+func (t *Telemetry) Recover(where string) {
+	var rec = recover()
+	if rec == nil {
+		return
+	}
+	t.Panic(where, rec, debug.Stack())
+	t.Flush(context.Background())
+	panic(rec)
+}
+
+// Send the queued events to the sink.
+func (t *Telemetry) Flush(ctx context.Context) error {
+	t.mu.Lock()
+	if t.timer != nil {
+		t.timer.Stop()
+		t.timer = nil
+	}
+	var events = t.queue
+	t.queue = nil
+	t.mu.Unlock()
+	if len(events) == 0 {
+		return nil
+	}
+	var err = t.sink.Send(ctx, events)
+	if err != nil {
+		t.opts.OnError(err)
+	}
+	return err
+}
+
+// Flush the queued events, and stop recording.
+func (t *Telemetry) Close(ctx context.Context) error {
+	t.mu.Lock()
+	t.closed = true
+	t.mu.Unlock()
+	return t.Flush(ctx)
+}
+
+func millis(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
@@ -0,0 +1,91 @@
+//go:build js && wasm
+// +build js,wasm
+
+package telemetry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"syscall/js"
+
+	"github.com/Nigel2392/jsext"
+	"github.com/Nigel2392/jsext/console"
+)
+
+// Sink which logs the events to the javascript console.
+func Console() Sink {
+	return SinkFunc(func(ctx context.Context, events []Event) error {
+		for _, e := range events {
+			var b, _ = json.Marshal(e.Attrs)
+			var line = "[telemetry] " + e.Kind + " " + e.Name
+			if e.Duration > 0 {
+				line += " " + strconv.FormatFloat(e.Duration, 'f', 1, 64) + "ms"
+			}
+			console.Log(line, string(b))
+		}
+		return nil
+	})
+}
+
+// Sink which posts batches with navigator.sendBeacon, which keeps working while the page unloads.
+// Falls back to a fetch with keepalive when the beacon is not queued.
+type Beacon struct {
+	URL string
+}
+
+func NewBeacon(url string) *Beacon {
+	return &Beacon{URL: url}
+}
+
+func (b *Beacon) Send(ctx context.Context, events []Event) error {
+	var data, err = json.Marshal(events)
+	if err != nil {
+		return err
+	}
+	var global = js.Value(jsext.Global)
+	var navigator = global.Get("navigator")
+	if navigator.Get("sendBeacon").Truthy() {
+		var parts = global.Get("Array").New(string(data))
+		var blob = global.Get("Blob").New(parts, map[string]interface{}{"type": "application/json"})
+		if navigator.Call("sendBeacon", b.URL, blob).Bool() {
+			return nil
+		}
+	}
+	if !global.Get("fetch").Truthy() {
+		return errors.New("telemetry: beacon and fetch are not available")
+	}
+	global.Call("fetch", b.URL, map[string]interface{}{
+		"method":    "POST",
+		"body":      string(data),
+		"keepalive": true,
+		"headers":   map[string]interface{}{"Content-Type": "application/json"},
+	})
+	return nil
+}
+
+// Flush the queued events when the page is hidden, which may be the last chance before it is closed.
+// Beacon sinks are flushed immediately, other sinks in a goroutine.
+func FlushOnHide(t *Telemetry) (remove func()) {
+	var document = js.Value(jsext.Document)
+	var window = js.Value(jsext.Window)
+	var flush = js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		if args[0].Get("type").String() == "visibilitychange" && document.Get("visibilityState").String() != "hidden" {
+			return nil
+		}
+		if _, ok := t.sink.(*Beacon); ok {
+			t.Flush(context.Background())
+		} else {
+			go t.Flush(context.Background())
+		}
+		return nil
+	})
+	document.Call("addEventListener", "visibilitychange", flush)
+	window.Call("addEventListener", "pagehide", flush)
+	return func() {
+		document.Call("removeEventListener", "visibilitychange", flush)
+		window.Call("removeEventListener", "pagehide", flush)
+		flush.Release()
+	}
+}
@@ -0,0 +1,262 @@
+package theme
+
+import (
+	"sort"
+	"strings"
+)
+
+// Prefix of the CSS custom properties.
+const Prefix = "--jsext-"
+
+// References to the CSS custom properties of the theme.
+// Use these in CSS instead of literal colors, so components follow the active theme.
+// The values of the light theme are used as fallback, when no theme has been applied.
+const (
+	Background   = "var(--jsext-background, #ffffff)"
+	Surface      = "var(--jsext-surface, #ffffff)"
+	SurfaceHover = "var(--jsext-surface-hover, #f1f1f1)"
+	Text         = "var(--jsext-text, #333333)"
+	TextMuted    = "var(--jsext-text-muted, #575757)"
+	Border       = "var(--jsext-border, #cccccc)"
+	Primary      = "var(--jsext-primary, #007991)"
+	PrimaryHover = "var(--jsext-primary-hover, #00606f)"
+	OnPrimary    = "var(--jsext-on-primary, #ffffff)"
+	Accent       = "var(--jsext-accent, #9200ff)"
+	AccentHover  = "var(--jsext-accent-hover, #a200ff)"
+	OnAccent     = "var(--jsext-on-accent, #ffffff)"
+	Success      = "var(--jsext-success, #439a86)"
+	Warning      = "var(--jsext-warning, #ffa500)"
+	Danger       = "var(--jsext-danger, #ff0057)"
+	Info         = "var(--jsext-info, #007991)"
+	Overlay      = "var(--jsext-overlay, rgba(0,0,0,0.5))"
+	OnOverlay    = "var(--jsext-on-overlay, #ffffff)"
+	Shadow       = "var(--jsext-shadow, rgba(0,0,0,0.2))"
+)
+
+// Reference a custom property of the theme.
+//
+//	Var("brand") -> "var(--jsext-brand)"
+func Var(name string) string {
+	return "var(" + Prefix + name + ")"
+}
+
+// Theme holds the colors of the framework components.
+// The colors are emitted as CSS custom properties, see the references above.
+type Theme struct {
+	Name         string
+	Background   string
+	Surface      string
+	SurfaceHover string
+	Text         string
+	TextMuted    string
+	Border       string
+	Primary      string
+	PrimaryHover string
+	OnPrimary    string
+	Accent       string
+	AccentHover  string
+	OnAccent     string
+	Success      string
+	Warning      string
+	Danger       string
+	Info         string
+	Overlay      string
+	OnOverlay    string
+	Shadow       string
+	// Extra custom properties, the keys are prefixed with --jsext-.
+	Extra map[string]string
+}
+
+// Light theme, the default.
+var Light = &Theme{
+	Name:         "light",
+	Background:   "#ffffff",
+	Surface:      "#ffffff",
+	SurfaceHover: "#f1f1f1",
+	Text:         "#333333",
+	TextMuted:    "#575757",
+	Border:       "#cccccc",
+	Primary:      "#007991",
+	PrimaryHover: "#00606f",
+	OnPrimary:    "#ffffff",
+	Accent:       "#9200ff",
+	AccentHover:  "#a200ff",
+	OnAccent:     "#ffffff",
+	Success:      "#439a86",
+	Warning:      "#ffa500",
+	Danger:       "#ff0057",
+	Info:         "#007991",
+	Overlay:      "rgba(0,0,0,0.5)",
+	OnOverlay:    "#ffffff",
+	Shadow:       "rgba(0,0,0,0.2)",
+}
+
+// Dark theme.
+var Dark = &Theme{
+	Name:         "dark",
+	Background:   "#121212",
+	Surface:      "#1e1e1e",
+	SurfaceHover: "#2a2a2a",
+	Text:         "#e6e6e6",
+	TextMuted:    "#9d9d9d",
+	Border:       "#3a3a3a",
+	Primary:      "#2bb3cf",
+	PrimaryHover: "#4cc4dd",
+	OnPrimary:    "#0b1f24",
+	Accent:       "#b366ff",
+	AccentHover:  "#c285ff",
+	OnAccent:     "#140024",
+	Success:      "#5cc2a9",
+	Warning:      "#ffb733",
+	Danger:       "#ff4d85",
+	Info:         "#2bb3cf",
+	Overlay:      "rgba(0,0,0,0.7)",
+	OnOverlay:    "#ffffff",
+	Shadow:       "rgba(0,0,0,0.6)",
+}
+
+// High contrast theme.
+var HighContrast = &Theme{
+	Name:         "high-contrast",
+	Background:   "#000000",
+	Surface:      "#000000",
+	SurfaceHover: "#1a1a1a",
+	Text:         "#ffffff",
+	TextMuted:    "#ffffff",
+	Border:       "#ffffff",
+	Primary:      "#ffff00",
+	PrimaryHover: "#ffff66",
+	OnPrimary:    "#000000",
+	Accent:       "#00ffff",
+	AccentHover:  "#66ffff",
+	OnAccent:     "#000000",
+	Success:      "#00ff00",
+	Warning:      "#ffff00",
+	Danger:       "#ff3333",
+	Info:         "#00ffff",
+	Overlay:      "rgba(0,0,0,0.85)",
+	OnOverlay:    "#ffffff",
+	Shadow:       "rgba(255,255,255,0.4)",
+}
+
+// Copy the theme, so it can be changed without changing the original.
+func (t *Theme) Clone() *Theme {
+	var c = *t
+	if t.Extra != nil {
+		c.Extra = make(map[string]string, len(t.Extra))
+		for k, v := range t.Extra {
+			c.Extra[k] = v
+		}
+	}
+	return &c
+}
+
+// Custom properties of the theme, in a stable order.
+// Empty colors are left out.
+func (t *Theme) Properties() [][2]string {
+	var props = [][2]string{
+		{"background", t.Background},
+		{"surface", t.Surface},
+		{"surface-hover", t.SurfaceHover},
+		{"text", t.Text},
+		{"text-muted", t.TextMuted},
+		{"border", t.Border},
+		{"primary", t.Primary},
+		{"primary-hover", t.PrimaryHover},
+		{"on-primary", t.OnPrimary},
+		{"accent", t.Accent},
+		{"accent-hover", t.AccentHover},
+		{"on-accent", t.OnAccent},
+		{"success", t.Success},
+		{"warning", t.Warning},
+		{"danger", t.Danger},
+		{"info", t.Info},
+		{"overlay", t.Overlay},
+		{"on-overlay", t.OnOverlay},
+		{"shadow", t.Shadow},
+	}
+	var extra = make([]string, 0, len(t.Extra))
+	for k := range t.Extra {
+		extra = append(extra, k)
+	}
+	sort.Strings(extra)
+	for _, k := range extra {
+		props = append(props, [2]string{k, t.Extra[k]})
+	}
+	var out = props[:0]
+	for _, p := range props {
+		if p[1] != "" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// CSS rule declaring the custom properties of the theme on the selector.
+//
+//	:root { --jsext-background: #ffffff; ... }
+func (t *Theme) CSS(selector string) string {
+	var sb strings.Builder
+	sb.WriteString(selector)
+	sb.WriteString(" {")
+	for _, p := range t.Properties() {
+		sb.WriteString(" ")
+		sb.WriteString(Prefix)
+		sb.WriteString(p[0])
+		sb.WriteString(": ")
+		sb.WriteString(p[1])
+		sb.WriteString(";")
+	}
+	sb.WriteString(" color-scheme: ")
+	if t.isDark() {
+		sb.WriteString("dark")
+	} else {
+		sb.WriteString("light")
+	}
+	sb.WriteString("; }")
+	return sb.String()
+}
+
+// CSS which follows the prefers-color-scheme and prefers-contrast media queries of the browser.
+// The dark and high contrast themes are optional.
+func AutoCSS(light, dark, highContrast *Theme) string {
+	var sb strings.Builder
+	sb.WriteString(light.CSS(":root"))
+	if dark != nil {
+		sb.WriteString("\n@media (prefers-color-scheme: dark) { ")
+		sb.WriteString(dark.CSS(":root"))
+		sb.WriteString(" }")
+	}
+	if highContrast != nil {
+		sb.WriteString("\n@media (prefers-contrast: more), (forced-colors: active) { ")
+		sb.WriteString(highContrast.CSS(":root"))
+		sb.WriteString(" }")
+	}
+	return sb.String()
+}
+
+// Guess if the theme is dark, by the lightness of the background.
+func (t *Theme) isDark() bool {
+	var c = strings.TrimPrefix(strings.ToLower(t.Background), "#")
+	if len(c) == 3 {
+		c = string([]byte{c[0], c[0], c[1], c[1], c[2], c[2]})
+	}
+	if len(c) != 6 {
+		return false
+	}
+	var rgb [3]int
+	for i := range rgb {
+		for _, ch := range c[i*2 : i*2+2] {
+			rgb[i] *= 16
+			switch {
+			case ch >= '0' && ch <= '9':
+				rgb[i] += int(ch - '0')
+			case ch >= 'a' && ch <= 'f':
+				rgb[i] += int(ch-'a') + 10
+			default:
+				return false
+			}
+		}
+	}
+	return rgb[0]*299+rgb[1]*587+rgb[2]*114 < 128000
+}
@@ -0,0 +1,68 @@
+package theme_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Nigel2392/jsext-framework/theme"
+)
+
+// The references fall back to the light theme, so components look the same before a theme is applied.
+func TestFallbacksMatchLight(t *testing.T) {
+	var refs = map[string]string{
+		"background":    theme.Background,
+		"surface":       theme.Surface,
+		"surface-hover": theme.SurfaceHover,
+		"text":          theme.Text,
+		"text-muted":    theme.TextMuted,
+		"border":        theme.Border,
+		"primary":       theme.Primary,
+		"primary-hover": theme.PrimaryHover,
+		"on-primary":    theme.OnPrimary,
+		"accent":        theme.Accent,
+		"accent-hover":  theme.AccentHover,
+		"on-accent":     theme.OnAccent,
+		"success":       theme.Success,
+		"warning":       theme.Warning,
+		"danger":        theme.Danger,
+		"info":          theme.Info,
+		"overlay":       theme.Overlay,
+		"on-overlay":    theme.OnOverlay,
+		"shadow":        theme.Shadow,
+	}
+	var props = theme.Light.Properties()
+	if len(props) != len(refs) {
+		t.Errorf("expected %d properties, got %d", len(refs), len(props))
+	}
+	for _, p := range props {
+		var want = "var(" + theme.Prefix + p[0] + ", " + p[1] + ")"
+		if refs[p[0]] != want {
+			t.Errorf("%s: expected %q, got %q", p[0], want, refs[p[0]])
+		}
+	}
+}
+
+func TestCSS(t *testing.T) {
+	var dark = theme.Dark.Clone()
+	dark.Extra = map[string]string{"brand": "#123456"}
+	var css = dark.CSS(":root")
+	for _, want := range []string{":root {", "--jsext-background: #121212;", "--jsext-brand: #123456;", "color-scheme: dark;"} {
+		if !strings.Contains(css, want) {
+			t.Errorf("expected %q in %q", want, css)
+		}
+	}
+	if theme.Dark.Extra != nil {
+		t.Error("expected Clone not to change the original theme")
+	}
+	if !strings.Contains(theme.Light.CSS(":root"), "color-scheme: light;") {
+		t.Error("expected the light theme to use the light color scheme")
+	}
+
+	var auto = theme.AutoCSS(theme.Light, theme.Dark, nil)
+	if !strings.Contains(auto, "@media (prefers-color-scheme: dark)") {
+		t.Errorf("expected a dark media query in %q", auto)
+	}
+	if strings.Contains(auto, "prefers-contrast") {
+		t.Errorf("expected no contrast media query without a high contrast theme in %q", auto)
+	}
+}
@@ -0,0 +1,129 @@
+//go:build js && wasm
+// +build js,wasm
+
+package theme
+
+import (
+	"sync"
+	"syscall/js"
+
+	"github.com/Nigel2392/jsext"
+	"github.com/Nigel2392/jsext-framework/helpers/hooks"
+)
+
+// ID of the style element holding the theme's custom properties.
+const StyleID = "jsext-theme"
+
+var state = struct {
+	auto                     bool
+	current                  *Theme
+	light, dark, contrast    *Theme
+	listeners                hooks.Hooks[func(*Theme)]
+	darkQuery, contrastQuery js.Value
+	onMedia                  js.Func
+	mu                       *sync.Mutex
+}{
+	mu: &sync.Mutex{},
+}
+
+// Apply a theme.
+// The custom properties are replaced in place, so rendered components are restyled without rendering them again.
+func Apply(t *Theme) {
+	state.mu.Lock()
+	state.auto = false
+	state.current = t
+	state.mu.Unlock()
+	writeStyle(t.CSS(":root"))
+	jsext.Document.Get("documentElement").Call("setAttribute", "data-jsext-theme", t.Name)
+	notify(t)
+}
+
+// Follow the prefers-color-scheme and prefers-contrast settings of the browser.
+// The dark and high contrast themes are optional.
+func Auto(light, dark, highContrast *Theme) {
+	state.mu.Lock()
+	state.auto = true
+	state.light, state.dark, state.contrast = light, dark, highContrast
+	if state.onMedia.IsUndefined() {
+		state.darkQuery = js.Global().Call("matchMedia", "(prefers-color-scheme: dark)")
+		state.contrastQuery = js.Global().Call("matchMedia", "(prefers-contrast: more), (forced-colors: active)")
+		state.onMedia = js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+			state.mu.Lock()
+			if !state.auto {
+				state.mu.Unlock()
+				return nil
+			}
+			var t = resolve()
+			var changed = t != state.current
+			state.current = t
+			state.mu.Unlock()
+			if changed {
+				jsext.Document.Get("documentElement").Call("setAttribute", "data-jsext-theme", t.Name)
+				go notify(t)
+			}
+			return nil
+		})
+		state.darkQuery.Call("addEventListener", "change", state.onMedia)
+		state.contrastQuery.Call("addEventListener", "change", state.onMedia)
+	}
+	var t = resolve()
+	state.current = t
+	state.mu.Unlock()
+	writeStyle(AutoCSS(light, dark, highContrast))
+	jsext.Document.Get("documentElement").Call("setAttribute", "data-jsext-theme", t.Name)
+	notify(t)
+}
+
+// Check if a theme has been applied with Apply or Auto.
+func Applied() bool {
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	return state.current != nil
+}
+
+// The active theme.
+// When following the browser settings, this is the theme matching the current settings.
+// Returns the light theme if no theme has been applied.
+func Current() *Theme {
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	if state.current == nil {
+		return Light
+	}
+	return state.current
+}
+
+// Register a function to call when the active theme changes.
+// Only needed for content which can not use the custom properties, such as canvas drawings.
+// The returned function removes the listener.
+func OnChange(f func(*Theme)) (remove func()) {
+	return state.listeners.Add(f)
+}
+
+// Resolve the theme matching the media queries, state must be locked.
+func resolve() *Theme {
+	if state.contrast != nil && state.contrastQuery.Get("matches").Bool() {
+		return state.contrast
+	}
+	if state.dark != nil && state.darkQuery.Get("matches").Bool() {
+		return state.dark
+	}
+	return state.light
+}
+
+func notify(t *Theme) {
+	for _, f := range state.listeners.List() {
+		f(t)
+	}
+}
+
+// Write the CSS to the theme's style element, creating it if needed.
+func writeStyle(css string) {
+	var style = jsext.Document.Call("getElementById", StyleID)
+	if style.IsNull() || style.IsUndefined() {
+		style = jsext.Document.Call("createElement", "style")
+		style.Set("id", StyleID)
+		jsext.Document.Get("head").Call("appendChild", style)
+	}
+	style.Set("textContent", css)
+}
@@ -1,15 +1,10 @@
-//go:build js && wasm
-// +build js,wasm
-
 package tokens
 
 import (
-	"encoding/base64"
-	"errors"
-	"strings"
+	"context"
 	"time"
 
-	"github.com/Nigel2392/jsext/requester/decoder"
+	"github.com/Nigel2392/jsext-framework/tokens/jwt"
 )
 
 type JWTToken struct {
@@ -30,45 +25,29 @@ func (p Payload) Get(key string) interface{} {
 }
 
 func (p Payload) GetTime(key string) time.Time {
-	var t = p.Get(key)
-	if t == nil {
-		return time.Time{}
-	}
-	var tFloat, ok = t.(float64)
-	if !ok {
-		return time.Time{}
-	}
-	return time.Unix(int64(tFloat), 0)
+	return jwt.Claims(p).Time(key)
 }
 
 type Signature string
 
+// Decode a token into its parts, without verifying the signature.
+// Returns jwt.ErrMalformed if the token is not a valid JWT.
 func DecodeToken(token string) (JWTToken, error) {
-	var parts = strings.Split(token, ".")
-	if len(parts) != 3 {
-		return JWTToken{}, errors.New("invalid token")
-	}
-	header, err := base64.RawURLEncoding.DecodeString(parts[0])
-	if err != nil {
-		return JWTToken{}, err
-	}
-	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
-	if err != nil {
-		return JWTToken{}, err
-	}
-	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	var t, err = jwt.Parse(token)
 	if err != nil {
 		return JWTToken{}, err
 	}
-	var t = JWTToken{
-		Header:    Header{},
-		Payload:   Payload{},
-		Signature: Signature(signature),
-	}
+	return JWTToken{
+		Header: Header{
+			Alg: t.Header.Alg,
+			Typ: t.Header.Typ,
+		},
+		Payload:   Payload(t.Claims),
+		Signature: Signature(t.Signature),
+	}, nil
+}
 
-	var m = decoder.ParseBytes(header).Value().(map[string]interface{})
-	t.Header.Alg = m["alg"].(string)
-	t.Header.Typ = m["typ"].(string)
-	t.Payload = decoder.ParseBytes(payload).Value().(map[string]interface{})
-	return t, nil
+// Verify the signature and claims of the access token.
+func (t *Token) Verify(ctx context.Context, v *jwt.Verifier) (*jwt.Token, error) {
+	return v.Verify(ctx, t.AccessToken)
 }
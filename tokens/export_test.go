@@ -0,0 +1,7 @@
+package tokens
+
+// Exported for the tests of the package.
+var (
+	EncodeSaveToken = encodeSaveToken
+	DecodeSaveToken = decodeSaveToken
+)
@@ -0,0 +1,235 @@
+// Package guard protects routes with rules on the claims of the token.
+//
+// Rules are stored in the metadata of a route, and apply to its children as well:
+//
+//	guard.Protect(adminRoute, guard.RequireRole("admin"))
+//
+// Unauthenticated users are redirected to the login URL with a next parameter,
+// authenticated users who do not satisfy the rules get a 403 router error.
+package guard
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/Nigel2392/jsext-framework/router/routes"
+	"github.com/Nigel2392/jsext-framework/router/rterr"
+	"github.com/Nigel2392/jsext-framework/router/vars"
+	"github.com/Nigel2392/jsext-framework/tokens/jwt"
+)
+
+// Key of the rules in the metadata of a route.
+const MetaKey = "guard"
+
+// Rule an authenticated user must satisfy to open a route.
+type Rule func(g *Guard, claims jwt.Claims) bool
+
+// Only require the user to be authenticated.
+func RequireAuth(g *Guard, claims jwt.Claims) bool {
+	return true
+}
+
+// Require the user to have any of the roles.
+func RequireRole(roles ...string) Rule {
+	return func(g *Guard, claims jwt.Claims) bool {
+		var has = g.list(claims, g.opts.RolesClaim)
+		for _, role := range roles {
+			if contains(has, role) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Require the user to have all of the permissions.
+func RequirePermission(permissions ...string) Rule {
+	return func(g *Guard, claims jwt.Claims) bool {
+		var has = g.list(claims, g.opts.PermissionsClaim)
+		for _, permission := range permissions {
+			if !contains(has, permission) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// Protect a route and its children with rules, all rules must be satisfied.
+// Without rules, the route only requires authentication.
+// Rules of a child are added to the rules of its parents.
+func Protect(rt *routes.Route, rules ...Rule) *routes.Route {
+	if len(rules) == 0 {
+		rules = []Rule{RequireAuth}
+	}
+	return rt.SetMeta(MetaKey, rules)
+}
+
+// Rules of the route and of all its parents.
+func Rules(rt *routes.Route) []Rule {
+	var rules []Rule
+	for ; rt != nil; rt = rt.Parent() {
+		if r, ok := rt.Meta[MetaKey].([]Rule); ok {
+			rules = append(rules, r...)
+		}
+	}
+	return rules
+}
+
+// Options for the guard.
+type Options struct {
+	// Claims of the authenticated user, nil if the user is not authenticated.
+	Claims func() jwt.Claims
+	// Redirect to a URL, unauthenticated users are redirected to the login URL.
+	Redirect func(url string)
+	// URL of the login route, defaults to "/login".
+	// The URL of the protected route is added as the next query parameter.
+	LoginURL string
+	// Claim with the roles of the user, defaults to "roles".
+	// Nested claims are separated by dots, for example "realm_access.roles".
+	RolesClaim string
+	// Claim with the permissions of the user, defaults to "permissions".
+	PermissionsClaim string
+}
+
+func (o *Options) SetDefaults() {
+	if o.Claims == nil {
+		o.Claims = func() jwt.Claims { return nil }
+	}
+	if o.Redirect == nil {
+		o.Redirect = func(url string) {}
+	}
+	if o.LoginURL == "" {
+		o.LoginURL = "/login"
+	}
+	if o.RolesClaim == "" {
+		o.RolesClaim = "roles"
+	}
+	if o.PermissionsClaim == "" {
+		o.PermissionsClaim = "permissions"
+	}
+}
+
+// Result of checking a route.
+type Result int
+
+const (
+	// The route is not protected, or the user satisfies its rules.
+	Allowed Result = iota
+	// The user is not authenticated.
+	Unauthenticated
+	// The user does not satisfy the rules of the route.
+	Forbidden
+)
+
+// Guard checks the rules of routes against the claims of the user.
+type Guard struct {
+	opts Options
+}
+
+func New(opts *Options) *Guard {
+	var o Options
+	if opts != nil {
+		o = *opts
+	}
+	o.SetDefaults()
+	return &Guard{opts: o}
+}
+
+// Check if the current user may open the route.
+func (g *Guard) Check(rt *routes.Route) Result {
+	if rt == nil {
+		return Allowed
+	}
+	var rules = Rules(rt)
+	if len(rules) == 0 {
+		return Allowed
+	}
+	var claims = g.opts.Claims()
+	if claims == nil {
+		return Unauthenticated
+	}
+	for _, rule := range rules {
+		if !rule(g, claims) {
+			return Forbidden
+		}
+	}
+	return Allowed
+}
+
+// Check if the current user has any of the roles.
+func (g *Guard) HasRole(roles ...string) bool {
+	var claims = g.opts.Claims()
+	return claims != nil && RequireRole(roles...)(g, claims)
+}
+
+// Check if the current user has all of the permissions.
+func (g *Guard) HasPermission(permissions ...string) bool {
+	var claims = g.opts.Claims()
+	return claims != nil && RequirePermission(permissions...)(g, claims)
+}
+
+// URL of the login route, with the URL to return to as the next query parameter.
+func (g *Guard) LoginURL(next *url.URL) string {
+	var login, err = url.Parse(g.opts.LoginURL)
+	if err != nil || next == nil {
+		return g.opts.LoginURL
+	}
+	var q = login.Query()
+	q.Set("next", next.RequestURI())
+	login.RawQuery = q.Encode()
+	return login.String()
+}
+
+// Router middleware which redirects unauthenticated users to the login URL,
+// and throws a 403 for users who do not satisfy the rules of the route.
+func (g *Guard) Middleware() func(vars.Vars, *url.URL, *routes.Route, rterr.ErrorThrower) bool {
+	return func(v vars.Vars, u *url.URL, rt *routes.Route, t rterr.ErrorThrower) bool {
+		switch g.Check(rt) {
+		case Unauthenticated:
+			g.opts.Redirect(g.LoginURL(u))
+			return false
+		case Forbidden:
+			t.Throw(rterr.ErrCodeForbidden)
+			return false
+		}
+		return true
+	}
+}
+
+// Get a claim as a list of strings.
+// The claim can be a list, or a string of space separated values like the OAuth2 scope claim.
+func (g *Guard) list(claims jwt.Claims, path string) []string {
+	var value interface{} = map[string]interface{}(claims)
+	for _, key := range strings.Split(path, ".") {
+		var m, ok = value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		value = m[key]
+	}
+	switch v := value.(type) {
+	case string:
+		return strings.Fields(v)
+	case []string:
+		return v
+	case []interface{}:
+		var list = make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				list = append(list, s)
+			}
+		}
+		return list
+	}
+	return nil
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,129 @@
+package guard_test
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/Nigel2392/jsext-framework/router"
+	"github.com/Nigel2392/jsext-framework/router/routes"
+	"github.com/Nigel2392/jsext-framework/router/rterr"
+	"github.com/Nigel2392/jsext-framework/router/vars"
+	"github.com/Nigel2392/jsext-framework/tokens/guard"
+	"github.com/Nigel2392/jsext-framework/tokens/jwt"
+)
+
+type thrower struct {
+	code int
+}
+
+func (t *thrower) Error(code int, message string) rterr.RouterError {
+	t.code = code
+	return rterr.NewError(code, message)
+}
+
+func (t *thrower) Throw(code int) {
+	t.code = code
+}
+
+func TestGuard(t *testing.T) {
+	var claims jwt.Claims
+	var g = guard.New(&guard.Options{
+		Claims:     func() jwt.Claims { return claims },
+		RolesClaim: "realm_access.roles",
+	})
+
+	var rt = router.NewRouter()
+	var noop = func(v vars.Vars, u *url.URL) {}
+	var home = rt.Register("home", "/", noop)
+	var profile = guard.Protect(rt.Register("profile", "/profile", noop))
+	var admin = guard.Protect(rt.Register("admin", "/admin", noop), guard.RequireRole("admin", "staff"))
+	var users = admin.Register("users", "users", noop)
+	var edit = guard.Protect(users.Register("edit", "edit", noop), guard.RequireRole("admin"), guard.RequirePermission("users.view", "users.edit"))
+
+	var tests = []struct {
+		claims jwt.Claims
+		want   [5]guard.Result
+	}{
+		{nil, [5]guard.Result{guard.Allowed, guard.Unauthenticated, guard.Unauthenticated, guard.Unauthenticated, guard.Unauthenticated}},
+		{jwt.Claims{}, [5]guard.Result{guard.Allowed, guard.Allowed, guard.Forbidden, guard.Forbidden, guard.Forbidden}},
+		{jwt.Claims{
+			"realm_access": map[string]interface{}{"roles": []interface{}{"staff"}},
+			"permissions":  "users.view users.edit",
+		}, [5]guard.Result{guard.Allowed, guard.Allowed, guard.Allowed, guard.Allowed, guard.Forbidden}},
+		{jwt.Claims{
+			"realm_access": map[string]interface{}{"roles": []interface{}{"admin"}},
+			"permissions":  []interface{}{"users.view"},
+		}, [5]guard.Result{guard.Allowed, guard.Allowed, guard.Allowed, guard.Allowed, guard.Forbidden}},
+		{jwt.Claims{
+			"realm_access": map[string]interface{}{"roles": []interface{}{"admin"}},
+			"permissions":  []interface{}{"users.view", "users.edit"},
+		}, [5]guard.Result{guard.Allowed, guard.Allowed, guard.Allowed, guard.Allowed, guard.Allowed}},
+	}
+	for i, test := range tests {
+		claims = test.claims
+		for j, route := range []*routes.Route{home, profile, admin, users, edit} {
+			if got := g.Check(route); got != test.want[j] {
+				t.Fatalf("%d: %s: expected %d, got %d", i, route.Name, test.want[j], got)
+			}
+		}
+	}
+}
+
+func TestInheritedRules(t *testing.T) {
+	var claims jwt.Claims
+	var g = guard.New(&guard.Options{
+		Claims: func() jwt.Claims { return claims },
+	})
+
+	var rt = router.NewRouter()
+	var noop = func(v vars.Vars, u *url.URL) {}
+	var admin = guard.Protect(rt.Register("admin", "/admin", noop), guard.RequireRole("admin"))
+	var settings = guard.Protect(admin.Register("settings", "settings", noop), guard.RequirePermission("settings.edit"))
+	if n := len(guard.Rules(settings)); n != 2 {
+		t.Fatalf("expected the rules of the parent and the child, got %d", n)
+	}
+
+	var tests = []struct {
+		claims jwt.Claims
+		want   guard.Result
+	}{
+		{jwt.Claims{"permissions": "settings.edit"}, guard.Forbidden},
+		{jwt.Claims{"roles": []interface{}{"admin"}}, guard.Forbidden},
+		{jwt.Claims{"roles": []interface{}{"admin"}, "permissions": "settings.edit"}, guard.Allowed},
+	}
+	for i, test := range tests {
+		claims = test.claims
+		if got := g.Check(settings); got != test.want {
+			t.Errorf("%d: expected %d, got %d", i, test.want, got)
+		}
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	var claims jwt.Claims
+	var redirected string
+	var g = guard.New(&guard.Options{
+		Claims:   func() jwt.Claims { return claims },
+		Redirect: func(url string) { redirected = url },
+		LoginURL: "/accounts/login?theme=dark",
+	})
+	var rt = router.NewRouter()
+	var admin = guard.Protect(rt.Register("admin", "/admin", nil), guard.RequireRole("admin"))
+	var mw = g.Middleware()
+	var u, _ = url.Parse("/admin?tab=users")
+
+	var th = &thrower{}
+	if mw(nil, u, admin, th) || redirected != "/accounts/login?next=%2Fadmin%3Ftab%3Dusers&theme=dark" {
+		t.Fatalf("expected a redirect to the login url, got %q", redirected)
+	}
+
+	claims = jwt.Claims{"roles": []interface{}{"user"}}
+	if mw(nil, u, admin, th) || th.code != rterr.ErrCodeForbidden {
+		t.Fatalf("expected a 403, got %d", th.code)
+	}
+
+	claims = jwt.Claims{"roles": []interface{}{"admin"}}
+	if !mw(nil, u, admin, th) || !g.HasRole("admin") || g.HasPermission("users.edit") {
+		t.Fatal("expected admin to be allowed")
+	}
+}
@@ -0,0 +1,28 @@
+//go:build js && wasm
+// +build js,wasm
+
+package guard
+
+import (
+	"github.com/Nigel2392/jsext-framework/tokens"
+	"github.com/Nigel2392/jsext-framework/tokens/jwt"
+)
+
+// Claims of the token returned by token, nil while the user is not logged in or the refresh token has expired.
+// The claims are read from the access token when it is a JWT, and from the data of the token otherwise.
+// The token is looked up on every call, pass tokens.Current to follow the active session.
+func TokenClaims(token func() *tokens.Token) func() jwt.Claims {
+	return func() jwt.Claims {
+		var t = token()
+		if t == nil || t.AccessToken == "" || t.IsRefreshExpired() {
+			return nil
+		}
+		if decoded, err := tokens.DecodeToken(t.AccessToken); err == nil {
+			return jwt.Claims(decoded.Payload)
+		}
+		if t.Data == nil {
+			return jwt.Claims{}
+		}
+		return jwt.Claims(t.Data)
+	}
+}
@@ -0,0 +1,264 @@
+// Package idle ends the session of a user after a period of inactivity.
+//
+// The tracker warns shortly before the session times out, so the user can choose to stay signed in.
+// Activity in one tab keeps the session alive in all tabs when the tracker is given the tabs of the application.
+package idle
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/Nigel2392/jsext-framework/tabs"
+)
+
+// Event sent to the other tabs when the user was active.
+const Event = "idle:activity"
+
+// Maximum interval at which activity is sent to the other tabs.
+const broadcastInterval = 5 * time.Second
+
+// Options for the idle tracker.
+type Options struct {
+	// Time without activity after which the session times out, defaults to 15 minutes.
+	Timeout time.Duration
+	// Time before the timeout at which the user is warned, defaults to a minute.
+	Warning time.Duration
+	// Called when the user should be warned, with the time left before the timeout.
+	OnWarning func(remaining time.Duration)
+	// Called when the user was active again after being warned.
+	OnActive func()
+	// Called when the session timed out.
+	OnTimeout func()
+	// Tabs to share the activity of the user with.
+	Tabs *tabs.Tabs
+}
+
+func (o *Options) SetDefaults() {
+	if o.Timeout == 0 {
+		o.Timeout = 15 * time.Minute
+	}
+	if o.Warning == 0 {
+		o.Warning = time.Minute
+	}
+	if o.Warning > o.Timeout {
+		o.Warning = o.Timeout
+	}
+	if o.OnWarning == nil {
+		o.OnWarning = func(remaining time.Duration) {}
+	}
+	if o.OnActive == nil {
+		o.OnActive = func() {}
+	}
+	if o.OnTimeout == nil {
+		o.OnTimeout = func() {}
+	}
+}
+
+// Tracker times out the session when the user has not been active for the timeout.
+//
+// Activity after a timeout starts a new idle period.
+type Tracker struct {
+	opts      Options
+	mu        sync.Mutex
+	last      time.Time
+	sent      time.Time
+	warned    bool
+	timedOut  bool
+	stopped   bool
+	timer     *time.Timer
+	flush     *time.Timer
+	removeTab func()
+}
+
+// Create a new tracker, the idle period starts immediately.
+func New(opts *Options) *Tracker {
+	var o Options
+	if opts != nil {
+		o = *opts
+	}
+	o.SetDefaults()
+	var t = &Tracker{opts: o, last: time.Now()}
+	if o.Tabs != nil {
+		t.removeTab = o.Tabs.On(Event, t.receive)
+	}
+	t.mu.Lock()
+	t.schedule()
+	t.mu.Unlock()
+	return t
+}
+
+// Record activity of the user, and share it with the other tabs.
+func (t *Tracker) Activity() {
+	t.activity(false)
+}
+
+// Keep the session alive after the user was warned,
+// the activity is shared with the other tabs immediately.
+func (t *Tracker) StaySignedIn() {
+	t.activity(true)
+}
+
+// Time of the last activity of the user in any of the tabs.
+func (t *Tracker) LastActivity() time.Time {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.last
+}
+
+// Time left before the session times out.
+func (t *Tracker) Remaining() time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.timedOut {
+		return 0
+	}
+	var remaining = time.Until(t.last.Add(t.opts.Timeout))
+	if remaining < 0 {
+		return 0
+	}
+	return remaining
+}
+
+// Stop tracking, the session will no longer time out.
+func (t *Tracker) Stop() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.stopped {
+		return
+	}
+	t.stopped = true
+	t.stopTimers()
+	if t.removeTab != nil {
+		t.removeTab()
+	}
+}
+
+func (t *Tracker) activity(now bool) {
+	t.mu.Lock()
+	if t.stopped {
+		t.mu.Unlock()
+		return
+	}
+	var warned = t.warned
+	t.last = time.Now()
+	t.warned = false
+	t.timedOut = false
+	if now || warned {
+		t.send()
+	} else {
+		t.throttle()
+	}
+	t.schedule()
+	t.mu.Unlock()
+	if warned {
+		t.opts.OnActive()
+	}
+}
+
+// Activity received from another tab.
+func (t *Tracker) receive(payload json.RawMessage) {
+	var ms int64
+	if err := json.Unmarshal(payload, &ms); err != nil {
+		return
+	}
+	var last = time.UnixMilli(ms)
+	t.mu.Lock()
+	if t.stopped || !last.After(t.last) {
+		t.mu.Unlock()
+		return
+	}
+	var warned = t.warned
+	t.last = last
+	t.warned = false
+	t.timedOut = false
+	t.schedule()
+	t.mu.Unlock()
+	if warned {
+		t.opts.OnActive()
+	}
+}
+
+// Send the last activity to the other tabs, the lock must be held.
+func (t *Tracker) send() {
+	if t.flush != nil {
+		t.flush.Stop()
+		t.flush = nil
+	}
+	if t.opts.Tabs == nil {
+		return
+	}
+	t.sent = time.Now()
+	t.opts.Tabs.Emit(Event, t.last.UnixMilli())
+}
+
+// Send the last activity at most once per interval, the lock must be held.
+// Activity within the interval is sent when the interval ends.
+func (t *Tracker) throttle() {
+	if t.opts.Tabs == nil || t.flush != nil {
+		return
+	}
+	var interval = broadcastInterval
+	if interval > t.opts.Timeout/10 {
+		interval = t.opts.Timeout / 10
+	}
+	var wait = interval - time.Since(t.sent)
+	if wait <= 0 {
+		t.send()
+		return
+	}
+	t.flush = time.AfterFunc(wait, func() {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		if !t.stopped {
+			t.send()
+		}
+	})
+}
+
+// Schedule the next warning or timeout, the lock must be held.
+func (t *Tracker) schedule() {
+	if t.timer != nil {
+		t.timer.Stop()
+	}
+	var deadline = t.last.Add(t.opts.Timeout)
+	if !t.warned {
+		deadline = deadline.Add(-t.opts.Warning)
+	}
+	t.timer = time.AfterFunc(time.Until(deadline), t.check)
+}
+
+func (t *Tracker) check() {
+	t.mu.Lock()
+	if t.stopped || t.timedOut {
+		t.mu.Unlock()
+		return
+	}
+	var remaining = time.Until(t.last.Add(t.opts.Timeout))
+	switch {
+	case remaining <= 0:
+		t.timedOut = true
+		t.warned = false
+		t.stopTimers()
+		t.mu.Unlock()
+		t.opts.OnTimeout()
+	case !t.warned && remaining <= t.opts.Warning:
+		t.warned = true
+		t.schedule()
+		t.mu.Unlock()
+		t.opts.OnWarning(remaining)
+	default:
+		t.schedule()
+		t.mu.Unlock()
+	}
+}
+
+func (t *Tracker) stopTimers() {
+	if t.timer != nil {
+		t.timer.Stop()
+	}
+	if t.flush != nil {
+		t.flush.Stop()
+		t.flush = nil
+	}
+}
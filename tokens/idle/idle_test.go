@@ -0,0 +1,96 @@
+package idle_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Nigel2392/jsext-framework/tabs"
+	"github.com/Nigel2392/jsext-framework/tokens/idle"
+)
+
+func TestTimeout(t *testing.T) {
+	var warned = make(chan time.Duration, 1)
+	var active = make(chan struct{}, 1)
+	var timedOut = make(chan struct{}, 1)
+	var tracker = idle.New(&idle.Options{
+		Timeout:   100 * time.Millisecond,
+		Warning:   50 * time.Millisecond,
+		OnWarning: func(remaining time.Duration) { warned <- remaining },
+		OnActive:  func() { active <- struct{}{} },
+		OnTimeout: func() { timedOut <- struct{}{} },
+	})
+	defer tracker.Stop()
+
+	select {
+	case remaining := <-warned:
+		if remaining > 50*time.Millisecond {
+			t.Errorf("warned with %s remaining", remaining)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("not warned")
+	}
+	tracker.StaySignedIn()
+	select {
+	case <-active:
+	case <-time.After(time.Second):
+		t.Fatal("staying signed in did not end the warning")
+	}
+	if tracker.Remaining() <= 50*time.Millisecond {
+		t.Errorf("remaining %s after staying signed in", tracker.Remaining())
+	}
+	select {
+	case <-timedOut:
+	case <-time.After(time.Second):
+		t.Fatal("session did not time out")
+	}
+	if tracker.Remaining() != 0 {
+		t.Errorf("remaining %s after the timeout", tracker.Remaining())
+	}
+}
+
+func TestTabs(t *testing.T) {
+	var hub = tabs.NewHub()
+	var opts = &tabs.Options{Heartbeat: 10 * time.Millisecond}
+	var a = tabs.New(hub.Transport(), opts)
+	defer a.Close()
+	var b = tabs.New(hub.Transport(), opts)
+	defer b.Close()
+
+	var timedOut = make(chan string, 2)
+	var trackerA = idle.New(&idle.Options{
+		Timeout:   200 * time.Millisecond,
+		Warning:   20 * time.Millisecond,
+		Tabs:      a,
+		OnTimeout: func() { timedOut <- "a" },
+	})
+	defer trackerA.Stop()
+	var trackerB = idle.New(&idle.Options{
+		Timeout:   200 * time.Millisecond,
+		Warning:   20 * time.Millisecond,
+		Tabs:      b,
+		OnTimeout: func() { timedOut <- "b" },
+	})
+	defer trackerB.Stop()
+
+	// Keep the user active in the first tab for longer than the timeout.
+	var start = time.Now()
+	for time.Since(start) < 300*time.Millisecond {
+		trackerA.Activity()
+		time.Sleep(10 * time.Millisecond)
+	}
+	select {
+	case tab := <-timedOut:
+		t.Fatalf("tab %s timed out while the user was active", tab)
+	default:
+	}
+	if !trackerB.LastActivity().After(start) {
+		t.Error("activity was not shared with the other tab")
+	}
+	for _, tab := range []string{"a", "b"} {
+		select {
+		case <-timedOut:
+		case <-time.After(time.Second):
+			t.Fatalf("tab %s did not time out", tab)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+//go:build js && wasm
+// +build js,wasm
+
+package idle
+
+import (
+	"math"
+	"sync"
+	"syscall/js"
+	"time"
+
+	"github.com/Nigel2392/jsext"
+	"github.com/Nigel2392/jsext-framework/components/misc"
+	"github.com/Nigel2392/jsext-framework/i18n"
+	"github.com/Nigel2392/jsext-framework/tabs"
+	"github.com/Nigel2392/jsext-framework/tokens"
+	"github.com/Nigel2392/jsext/elements"
+)
+
+// Events on the window which count as activity of the user.
+var ActivityEvents = []string{"pointerdown", "pointermove", "keydown", "wheel", "touchstart", "scroll"}
+
+// Prefix for the classes of the warning modal.
+const ModalPrefix = "jsext-idle-"
+
+// Record activity of the user on the page, and when the page becomes visible again.
+// Events are handled at most once per second.
+func Track(t *Tracker) (remove func()) {
+	var document = js.Value(jsext.Document)
+	var window = js.Value(jsext.Window)
+	var mu sync.Mutex
+	var last time.Time
+	var onActivity = js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		if args[0].Get("type").String() == "visibilitychange" && document.Get("visibilityState").String() != "visible" {
+			return nil
+		}
+		mu.Lock()
+		if time.Since(last) < time.Second {
+			mu.Unlock()
+			return nil
+		}
+		last = time.Now()
+		mu.Unlock()
+		go t.Activity()
+		return nil
+	})
+	var passive = map[string]interface{}{"capture": true, "passive": true}
+	for _, event := range ActivityEvents {
+		window.Call("addEventListener", event, onActivity, passive)
+	}
+	document.Call("addEventListener", "visibilitychange", onActivity)
+	return func() {
+		for _, event := range ActivityEvents {
+			window.Call("removeEventListener", event, onActivity, passive)
+		}
+		document.Call("removeEventListener", "visibilitychange", onActivity)
+		onActivity.Release()
+	}
+}
+
+// Log out when the session times out.
+// With tabs, only the leader sends the logout request, the other tabs reset their token.
+func Logout(token func() *tokens.Token, tb *tabs.Tabs) func() {
+	return func() {
+		var t = token()
+		if t == nil || t.AccessToken == "" {
+			return
+		}
+		if tb != nil && !tb.IsLeader() {
+			t.Reset()
+			return
+		}
+		if err := t.Logout(); err != nil && t.AccessToken != "" {
+			t.Reset()
+		}
+	}
+}
+
+// Modal warning the user before the session times out,
+// with a countdown and a button to stay signed in.
+type Modal struct {
+	modal   *misc.Modal
+	message *elements.Element
+	mu      sync.Mutex
+	stop    chan struct{}
+}
+
+// Create the warning modal.
+// Stay is called when the user chooses to stay signed in, logout when the user chooses to sign out.
+func NewModal(stay, logout func()) *Modal {
+	var m = &Modal{}
+	var header = elements.H3(i18n.T("idle.title")).AttrClass(ModalPrefix + "title")
+	m.message = elements.P().AttrClass(ModalPrefix + "message")
+	var footer = elements.Div().AttrClass(ModalPrefix + "actions")
+	footer.Button(i18n.T("idle.logout")).AttrClass(ModalPrefix+"logout").AddEventListener("click", func(this jsext.Value, event jsext.Event) {
+		event.PreventDefault()
+		m.Hide()
+		go logout()
+	})
+	footer.Button(i18n.T("idle.stay")).AttrClass(ModalPrefix+"stay").AddEventListener("click", func(this jsext.Value, event jsext.Event) {
+		event.PreventDefault()
+		m.Hide()
+		go stay()
+	})
+	m.modal = misc.CreateModal(misc.ModalOptions{
+		Header:      header,
+		Body:        m.message,
+		Footer:      footer,
+		Width:       "400px",
+		ClassPrefix: ModalPrefix,
+	})
+	return m
+}
+
+// Show the modal, counting down from the remaining time.
+func (m *Modal) Show(remaining time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.stop != nil {
+		return
+	}
+	var deadline = time.Now().Add(remaining)
+	m.setMessage(remaining)
+	m.modal.Show()
+	var stop = make(chan struct{})
+	m.stop = stop
+	go func() {
+		var ticker = time.NewTicker(time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				m.setMessage(time.Until(deadline))
+			}
+		}
+	}()
+}
+
+// Hide the modal.
+func (m *Modal) Hide() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.stop == nil {
+		return
+	}
+	close(m.stop)
+	m.stop = nil
+	m.modal.Hide()
+}
+
+func (m *Modal) setMessage(remaining time.Duration) {
+	var seconds = int(math.Ceil(remaining.Seconds()))
+	if seconds < 0 {
+		seconds = 0
+	}
+	m.message.InnerText(i18n.T("idle.message", i18n.Args{"count": seconds}))
+}
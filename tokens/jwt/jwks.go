@@ -0,0 +1,157 @@
+package jwt
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/Nigel2392/jsext-framework/client"
+)
+
+// Key of a JWKS document.
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg,omitempty"`
+	Use string `json:"use,omitempty"`
+	// RSA
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+	// EC
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+	// Symmetric
+	K string `json:"k,omitempty"`
+}
+
+// Public key of the JWK.
+func (k JWK) Key() (interface{}, error) {
+	switch k.Kty {
+	case "RSA":
+		var n, err1 = base64.RawURLEncoding.DecodeString(k.N)
+		var e, err2 = base64.RawURLEncoding.DecodeString(k.E)
+		if err1 != nil || err2 != nil || len(n) == 0 || len(e) == 0 || len(e) > 4 {
+			return nil, errors.New("jwt: invalid RSA key " + k.Kid)
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+	case "EC":
+		if k.Crv != "P-256" {
+			return nil, errors.New("jwt: unsupported curve " + k.Crv)
+		}
+		var x, err1 = base64.RawURLEncoding.DecodeString(k.X)
+		var y, err2 = base64.RawURLEncoding.DecodeString(k.Y)
+		if err1 != nil || err2 != nil {
+			return nil, errors.New("jwt: invalid EC key " + k.Kid)
+		}
+		var pub = &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		if !pub.Curve.IsOnCurve(pub.X, pub.Y) {
+			return nil, errors.New("jwt: invalid EC key " + k.Kid)
+		}
+		return pub, nil
+	case "oct":
+		var secret, err = base64.RawURLEncoding.DecodeString(k.K)
+		if err != nil {
+			return nil, errors.New("jwt: invalid symmetric key " + k.Kid)
+		}
+		return secret, nil
+	}
+	return nil, errors.New("jwt: unsupported key type " + k.Kty)
+}
+
+// Parse the keys of a JWKS document, keyed by key ID.
+// Keys which are not supported, or not used for signatures, are skipped.
+func ParseJWKS(data []byte) (map[string]interface{}, error) {
+	var doc struct {
+		Keys []JWK `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	var keys = make(map[string]interface{}, len(doc.Keys))
+	for _, jwk := range doc.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		if key, err := jwk.Key(); err == nil {
+			keys[jwk.Kid] = key
+		}
+	}
+	return keys, nil
+}
+
+// Key set of a JWKS document served over HTTP.
+//
+// The document is fetched when a key ID is not known, so keys which are rotated in are found.
+// Fetches are limited to one per MinRefresh.
+type JWKS struct {
+	// Minimum time between fetches of the document, defaults to 5 minutes.
+	MinRefresh time.Duration
+	client     *client.Client
+	url        string
+	mu         sync.Mutex
+	keys       map[string]interface{}
+	fetched    time.Time
+}
+
+// Create a key set for the JWKS document at the URL.
+// If c is nil, a client without interceptors is used.
+func NewJWKS(c *client.Client, url string) *JWKS {
+	if c == nil {
+		c = client.New()
+	}
+	return &JWKS{MinRefresh: 5 * time.Minute, client: c, url: url}
+}
+
+func (j *JWKS) Key(ctx context.Context, kid, alg string) (interface{}, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if key, ok := j.lookup(kid); ok {
+		return key, nil
+	}
+	if j.keys != nil && time.Since(j.fetched) < j.MinRefresh {
+		return nil, ErrKeyNotFound
+	}
+	if err := j.fetch(ctx); err != nil {
+		return nil, err
+	}
+	if key, ok := j.lookup(kid); ok {
+		return key, nil
+	}
+	return nil, ErrKeyNotFound
+}
+
+func (j *JWKS) lookup(kid string) (interface{}, bool) {
+	if key, ok := j.keys[kid]; ok {
+		return key, true
+	}
+	// Documents with a single key are often used without key IDs.
+	if kid == "" && len(j.keys) == 1 {
+		for _, key := range j.keys {
+			return key, true
+		}
+	}
+	return nil, false
+}
+
+// Fetch the document, the keys are replaced so rotated out keys are removed.
+func (j *JWKS) fetch(ctx context.Context) error {
+	var raw json.RawMessage
+	if err := j.client.Get(ctx, j.url, &raw); err != nil {
+		return err
+	}
+	var keys, err = ParseJWKS(raw)
+	if err != nil {
+		return err
+	}
+	j.keys = keys
+	j.fetched = time.Now()
+	return nil
+}
@@ -0,0 +1,138 @@
+// Package jwt parses and verifies JSON web tokens.
+//
+// Signatures are verified with HS256, RS256 or ES256, using a static key or the keys of a JWKS document.
+// The exp, nbf, iss and aud claims are validated after the signature.
+package jwt
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+var (
+	// Returned when a token is not three base64 encoded parts with a JSON header and payload.
+	ErrMalformed = errors.New("jwt: malformed token")
+	// Returned when the algorithm of a token is not supported or not allowed.
+	ErrUnsupportedAlg = errors.New("jwt: unsupported algorithm")
+	// Returned when the signature of a token does not match.
+	ErrInvalidSignature = errors.New("jwt: invalid signature")
+	// Returned when no key is found for the key ID of a token.
+	ErrKeyNotFound = errors.New("jwt: key not found")
+
+	// Claim errors, wrapped in a *ClaimError.
+	ErrExpired         = errors.New("token is expired")
+	ErrNotYetValid     = errors.New("token is not valid yet")
+	ErrInvalidIssuer   = errors.New("invalid issuer")
+	ErrInvalidAudience = errors.New("invalid audience")
+)
+
+// Error for a claim which failed validation.
+// Check the reason with errors.Is, for example errors.Is(err, jwt.ErrExpired).
+type ClaimError struct {
+	Claim string
+	Err   error
+}
+
+func (e *ClaimError) Error() string {
+	return "jwt: " + e.Claim + ": " + e.Err.Error()
+}
+
+func (e *ClaimError) Unwrap() error {
+	return e.Err
+}
+
+// Header of a token.
+type Header struct {
+	Alg string `json:"alg"`
+	Typ string `json:"typ,omitempty"`
+	Kid string `json:"kid,omitempty"`
+}
+
+// Claims of a token.
+type Claims map[string]interface{}
+
+// Get a string claim.
+func (c Claims) String(key string) string {
+	var s, _ = c[key].(string)
+	return s
+}
+
+// Get a numeric date claim, such as exp.
+// Returns the zero time if the claim is missing or not a number.
+func (c Claims) Time(key string) time.Time {
+	switch v := c[key].(type) {
+	case float64:
+		return time.Unix(int64(v), 0)
+	case json.Number:
+		var f, err = v.Float64()
+		if err == nil {
+			return time.Unix(int64(f), 0)
+		}
+	}
+	return time.Time{}
+}
+
+// Get the audience claim, which can be a string or a list of strings.
+func (c Claims) Audience() []string {
+	switch v := c["aud"].(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		var aud = make([]string, 0, len(v))
+		for _, a := range v {
+			if s, ok := a.(string); ok {
+				aud = append(aud, s)
+			}
+		}
+		return aud
+	}
+	return nil
+}
+
+// Parsed token.
+type Token struct {
+	Header    Header
+	Claims    Claims
+	Signature []byte
+	// Header and payload, as signed.
+	signed string
+}
+
+// Parse a token without verifying it.
+func Parse(token string) (*Token, error) {
+	var parts = strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrMalformed
+	}
+	var t = &Token{signed: parts[0] + "." + parts[1]}
+	if err := decodePart(parts[0], &t.Header); err != nil {
+		return nil, err
+	}
+	if t.Header.Alg == "" {
+		return nil, ErrMalformed
+	}
+	if err := decodePart(parts[1], &t.Claims); err != nil {
+		return nil, err
+	}
+	var err error
+	if t.Signature, err = base64.RawURLEncoding.DecodeString(parts[2]); err != nil {
+		return nil, ErrMalformed
+	}
+	return t, nil
+}
+
+func decodePart(part string, v any) error {
+	var b, err = base64.RawURLEncoding.DecodeString(part)
+	if err != nil {
+		return ErrMalformed
+	}
+	var dec = json.NewDecoder(bytes.NewReader(b))
+	if dec.Decode(v) != nil {
+		return ErrMalformed
+	}
+	return nil
+}
@@ -0,0 +1,188 @@
+package jwt_test
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Nigel2392/jsext-framework/tokens/jwt"
+)
+
+var b64 = base64.RawURLEncoding
+
+func sign(t *testing.T, header jwt.Header, claims jwt.Claims, key interface{}) string {
+	var h, _ = json.Marshal(header)
+	var c, _ = json.Marshal(claims)
+	var signed = b64.EncodeToString(h) + "." + b64.EncodeToString(c)
+	var sum = sha256.Sum256([]byte(signed))
+	var sig []byte
+	switch key := key.(type) {
+	case []byte:
+		var mac = hmac.New(sha256.New, key)
+		mac.Write([]byte(signed))
+		sig = mac.Sum(nil)
+	case *rsa.PrivateKey:
+		var err error
+		if sig, err = rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, sum[:]); err != nil {
+			t.Fatal(err)
+		}
+	case *ecdsa.PrivateKey:
+		var r, s, err = ecdsa.Sign(rand.Reader, key, sum[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		sig = make([]byte, 64)
+		r.FillBytes(sig[:32])
+		s.FillBytes(sig[32:])
+	}
+	return signed + "." + b64.EncodeToString(sig)
+}
+
+func TestVerify(t *testing.T) {
+	var rsaKey, _ = rsa.GenerateKey(rand.Reader, 2048)
+	var ecKey, _ = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	var secret = []byte("secret")
+	var claims = jwt.Claims{"sub": "1", "exp": float64(time.Now().Add(time.Hour).Unix())}
+
+	var tests = []struct {
+		alg    string
+		sign   interface{}
+		verify interface{}
+	}{
+		{jwt.HS256, secret, secret},
+		{jwt.RS256, rsaKey, &rsaKey.PublicKey},
+		{jwt.ES256, ecKey, &ecKey.PublicKey},
+	}
+	for _, test := range tests {
+		var token = sign(t, jwt.Header{Alg: test.alg, Typ: "JWT"}, claims, test.sign)
+		var v = jwt.NewVerifier(jwt.StaticKey(test.verify), nil)
+		var parsed, err = v.Verify(context.Background(), token)
+		if err != nil {
+			t.Fatalf("%s: %v", test.alg, err)
+		}
+		if parsed.Claims.String("sub") != "1" {
+			t.Fatalf("%s: unexpected claims %v", test.alg, parsed.Claims)
+		}
+		if _, err = v.Verify(context.Background(), token[:len(token)-4]+"AAAA"); err != jwt.ErrInvalidSignature {
+			t.Fatalf("%s: expected invalid signature, got %v", test.alg, err)
+		}
+	}
+
+	// A HS256 token signed with the public key must not verify against an RSA key.
+	var pub, _ = json.Marshal(rsaKey.PublicKey)
+	var token = sign(t, jwt.Header{Alg: jwt.HS256}, claims, pub)
+	if _, err := jwt.NewVerifier(jwt.StaticKey(&rsaKey.PublicKey), nil).Verify(context.Background(), token); err == nil {
+		t.Fatal("expected HS256 token to be rejected for an RSA key")
+	}
+	token = sign(t, jwt.Header{Alg: "none"}, claims, nil)
+	if _, err := jwt.NewVerifier(jwt.StaticKey(secret), nil).Verify(context.Background(), token); err != jwt.ErrUnsupportedAlg {
+		t.Fatalf("expected unsupported algorithm, got %v", err)
+	}
+}
+
+func TestClaims(t *testing.T) {
+	var secret = []byte("secret")
+	var now = time.Now()
+	var v = jwt.NewVerifier(jwt.StaticKey(secret), &jwt.Options{
+		Issuer:   "https://auth.example.com",
+		Audience: "app",
+		Leeway:   time.Minute,
+	})
+	var tests = []struct {
+		claims jwt.Claims
+		err    error
+	}{
+		{jwt.Claims{"iss": "https://auth.example.com", "aud": []string{"other", "app"}, "exp": float64(now.Add(-30 * time.Second).Unix())}, nil},
+		{jwt.Claims{"iss": "https://auth.example.com", "aud": "app", "exp": float64(now.Add(-2 * time.Minute).Unix())}, jwt.ErrExpired},
+		{jwt.Claims{"iss": "https://auth.example.com", "aud": "app", "nbf": float64(now.Add(2 * time.Minute).Unix())}, jwt.ErrNotYetValid},
+		{jwt.Claims{"iss": "https://evil.example.com", "aud": "app"}, jwt.ErrInvalidIssuer},
+		{jwt.Claims{"iss": "https://auth.example.com", "aud": "other"}, jwt.ErrInvalidAudience},
+	}
+	for i, test := range tests {
+		var _, err = v.Verify(context.Background(), sign(t, jwt.Header{Alg: jwt.HS256}, test.claims, secret))
+		if !errors.Is(err, test.err) || (err == nil) != (test.err == nil) {
+			t.Fatalf("%d: expected %v, got %v", i, test.err, err)
+		}
+		var claimErr *jwt.ClaimError
+		if test.err != nil && !errors.As(err, &claimErr) {
+			t.Fatalf("%d: expected a claim error, got %T", i, err)
+		}
+	}
+}
+
+func TestParseMalformed(t *testing.T) {
+	var header = func(s string) string { return b64.EncodeToString([]byte(s)) }
+	var payload = header(`{"sub": "1"}`)
+	for _, token := range []string{
+		"",
+		"a.b",
+		header(`{"typ": "JWT"}`) + "." + payload + ".",
+		header(`{"alg": 1}`) + "." + payload + ".",
+		header(`[]`) + "." + payload + ".",
+		header(`{"alg": "HS256"}`) + "." + header(`"sub"`) + ".",
+		header(`{"alg": "HS256"}`) + ".%%%.",
+	} {
+		if _, err := jwt.Parse(token); err != jwt.ErrMalformed {
+			t.Fatalf("%q: expected malformed, got %v", token, err)
+		}
+	}
+}
+
+func TestJWKSRotation(t *testing.T) {
+	var key1, _ = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	var key2, _ = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	var jwk = func(kid string, key *ecdsa.PrivateKey) map[string]string {
+		return map[string]string{
+			"kty": "EC", "crv": "P-256", "kid": kid, "use": "sig",
+			"x": b64.EncodeToString(key.X.FillBytes(make([]byte, 32))),
+			"y": b64.EncodeToString(key.Y.FillBytes(make([]byte, 32))),
+		}
+	}
+	var mu sync.Mutex
+	var served = []map[string]string{jwk("1", key1)}
+	var fetches int
+	var server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		fetches++
+		json.NewEncoder(w).Encode(map[string]any{"keys": served})
+	}))
+	defer server.Close()
+
+	var keys = jwt.NewJWKS(nil, server.URL)
+	var v = jwt.NewVerifier(keys, nil)
+	var claims = jwt.Claims{"sub": "1"}
+	if _, err := v.Verify(context.Background(), sign(t, jwt.Header{Alg: jwt.ES256, Kid: "1"}, claims, key1)); err != nil {
+		t.Fatal(err)
+	}
+
+	// Unknown key IDs do not refetch the document within MinRefresh.
+	var rotated = sign(t, jwt.Header{Alg: jwt.ES256, Kid: "2"}, claims, key2)
+	mu.Lock()
+	served = []map[string]string{jwk("2", key2)}
+	mu.Unlock()
+	if _, err := v.Verify(context.Background(), rotated); err != jwt.ErrKeyNotFound {
+		t.Fatalf("expected key not found, got %v", err)
+	}
+
+	keys.MinRefresh = 0
+	if _, err := v.Verify(context.Background(), rotated); err != nil {
+		t.Fatal(err)
+	}
+	if fetches != 2 {
+		t.Fatalf("expected 2 fetches, got %d", fetches)
+	}
+}
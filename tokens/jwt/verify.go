@@ -0,0 +1,174 @@
+package jwt
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"math/big"
+	"time"
+)
+
+// Supported algorithms.
+const (
+	HS256 = "HS256"
+	RS256 = "RS256"
+	ES256 = "ES256"
+)
+
+// KeySet finds the key to verify a token with.
+// Keys are []byte for HS256, *rsa.PublicKey for RS256 and *ecdsa.PublicKey for ES256.
+type KeySet interface {
+	Key(ctx context.Context, kid, alg string) (interface{}, error)
+}
+
+// Adapter to use a function as a key set.
+type KeySetFunc func(ctx context.Context, kid, alg string) (interface{}, error)
+
+func (f KeySetFunc) Key(ctx context.Context, kid, alg string) (interface{}, error) {
+	return f(ctx, kid, alg)
+}
+
+// Key set of a single key, used for every key ID.
+func StaticKey(key interface{}) KeySet {
+	return KeySetFunc(func(ctx context.Context, kid, alg string) (interface{}, error) {
+		return key, nil
+	})
+}
+
+// Options for the verifier.
+type Options struct {
+	// Required issuer, not checked if empty.
+	Issuer string
+	// Required audience, not checked if empty.
+	Audience string
+	// Tolerance for the clocks of the issuer and the client differing, defaults to 30 seconds.
+	Leeway time.Duration
+	// Allowed algorithms, defaults to HS256, RS256 and ES256.
+	Algorithms []string
+	// Current time, defaults to time.Now.
+	Now func() time.Time
+}
+
+func (o *Options) SetDefaults() {
+	if o.Leeway == 0 {
+		o.Leeway = 30 * time.Second
+	}
+	if o.Algorithms == nil {
+		o.Algorithms = []string{HS256, RS256, ES256}
+	}
+	if o.Now == nil {
+		o.Now = time.Now
+	}
+}
+
+// Verifier checks the signature and claims of tokens.
+type Verifier struct {
+	keys KeySet
+	opts Options
+}
+
+func NewVerifier(keys KeySet, opts *Options) *Verifier {
+	var o Options
+	if opts != nil {
+		o = *opts
+	}
+	o.SetDefaults()
+	return &Verifier{keys: keys, opts: o}
+}
+
+// Parse and verify a token.
+func (v *Verifier) Verify(ctx context.Context, token string) (*Token, error) {
+	var t, err = Parse(token)
+	if err != nil {
+		return nil, err
+	}
+	if !v.allowed(t.Header.Alg) {
+		return nil, ErrUnsupportedAlg
+	}
+	key, err := v.keys.Key(ctx, t.Header.Kid, t.Header.Alg)
+	if err != nil {
+		return nil, err
+	}
+	if err := verifySignature(t.Header.Alg, key, []byte(t.signed), t.Signature); err != nil {
+		return nil, err
+	}
+	if err := v.Validate(t.Claims); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// Validate the exp, nbf, iss and aud claims.
+func (v *Verifier) Validate(c Claims) error {
+	var now = v.opts.Now()
+	if exp := c.Time("exp"); !exp.IsZero() && now.After(exp.Add(v.opts.Leeway)) {
+		return &ClaimError{Claim: "exp", Err: ErrExpired}
+	}
+	if nbf := c.Time("nbf"); !nbf.IsZero() && now.Before(nbf.Add(-v.opts.Leeway)) {
+		return &ClaimError{Claim: "nbf", Err: ErrNotYetValid}
+	}
+	if v.opts.Issuer != "" && c.String("iss") != v.opts.Issuer {
+		return &ClaimError{Claim: "iss", Err: ErrInvalidIssuer}
+	}
+	if v.opts.Audience != "" && !contains(c.Audience(), v.opts.Audience) {
+		return &ClaimError{Claim: "aud", Err: ErrInvalidAudience}
+	}
+	return nil
+}
+
+func (v *Verifier) allowed(alg string) bool {
+	return contains(v.opts.Algorithms, alg)
+}
+
+// Verify a signature, the key must be of the type of the algorithm.
+func verifySignature(alg string, key interface{}, signed, sig []byte) error {
+	var sum = sha256.Sum256(signed)
+	switch alg {
+	case HS256:
+		var secret, ok = key.([]byte)
+		if !ok {
+			return ErrKeyNotFound
+		}
+		var mac = hmac.New(sha256.New, secret)
+		mac.Write(signed)
+		if !hmac.Equal(mac.Sum(nil), sig) {
+			return ErrInvalidSignature
+		}
+	case RS256:
+		var pub, ok = key.(*rsa.PublicKey)
+		if !ok {
+			return ErrKeyNotFound
+		}
+		if rsa.VerifyPKCS1v15(pub, crypto.SHA256, sum[:], sig) != nil {
+			return ErrInvalidSignature
+		}
+	case ES256:
+		var pub, ok = key.(*ecdsa.PublicKey)
+		if !ok {
+			return ErrKeyNotFound
+		}
+		if len(sig) != 64 {
+			return ErrInvalidSignature
+		}
+		var r = new(big.Int).SetBytes(sig[:32])
+		var s = new(big.Int).SetBytes(sig[32:])
+		if !ecdsa.Verify(pub, sum[:], r, s) {
+			return ErrInvalidSignature
+		}
+	default:
+		return ErrUnsupportedAlg
+	}
+	return nil
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,290 @@
+// Package oauth implements the OAuth2 authorization code flow with PKCE, and the nonce of OpenID Connect.
+//
+// The flow starts by redirecting the user to the authorization endpoint with AuthCodeURL.
+// The authorization server redirects back to the callback route with a code,
+// which is exchanged for tokens at the token endpoint with Exchange.
+// The state, nonce and PKCE verifier are kept in a PendingStore between the redirects.
+package oauth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/Nigel2392/jsext-framework/client"
+	"github.com/Nigel2392/jsext-framework/tokens/jwt"
+)
+
+var (
+	// Returned when the state of a callback is missing, or was not created by this flow.
+	ErrInvalidState = errors.New("oauth: invalid state")
+	// Returned when the nonce of the ID token does not match the nonce of the flow.
+	ErrInvalidNonce = errors.New("oauth: invalid nonce")
+	// Returned when a callback has no code.
+	ErrNoCode = errors.New("oauth: no code")
+)
+
+// Error returned by the authorization server, in a callback or from the token endpoint.
+type Error struct {
+	Code        string `json:"error"`
+	Description string `json:"error_description"`
+	URI         string `json:"error_uri"`
+}
+
+func (e *Error) Error() string {
+	if e.Description != "" {
+		return "oauth: " + e.Code + ": " + e.Description
+	}
+	return "oauth: " + e.Code
+}
+
+// Options for the flow.
+type Options struct {
+	ClientID string
+	// Authorization endpoint the user is redirected to.
+	AuthorizeURL string
+	// Token endpoint the code is exchanged at.
+	TokenURL string
+	// Callback URL the authorization server redirects back to.
+	RedirectURL string
+	// Scopes to request, include "openid" for an ID token.
+	Scopes []string
+	// Additional parameters for the authorization endpoint, such as "audience" or "prompt".
+	Params url.Values
+	// Verifier for the ID token, its audience should be the client ID.
+	// If nil, the claims of the ID token are not verified.
+	IDTokenVerifier *jwt.Verifier
+	// Client for the token endpoint, defaults to a client without interceptors.
+	Client *client.Client
+	// Store for the state of flows in progress.
+	// Defaults to a SessionStore in the browser, and a memory store elsewhere.
+	Store PendingStore
+}
+
+func (o *Options) SetDefaults() {
+	if o.Client == nil {
+		o.Client = client.New()
+	}
+	if o.Store == nil {
+		o.Store = newDefaultStore()
+	}
+}
+
+// Flow in progress, kept between the redirect to the authorization server and the callback.
+type Pending struct {
+	State    string `json:"state"`
+	Nonce    string `json:"nonce"`
+	Verifier string `json:"verifier"`
+	// URL to return to after logging in.
+	Next string `json:"next,omitempty"`
+}
+
+// Response of the token endpoint.
+type TokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	TokenType    string `json:"token_type"`
+	// Lifetime of the access token in seconds.
+	ExpiresIn int    `json:"expires_in"`
+	IDToken   string `json:"id_token"`
+	Scope     string `json:"scope"`
+	// Claims of the ID token, only verified with Options.IDTokenVerifier.
+	Claims map[string]interface{} `json:"-"`
+	// URL to return to, as passed to AuthCodeURL.
+	Next string `json:"-"`
+}
+
+// Flow for the authorization code grant with PKCE.
+type Flow struct {
+	opts Options
+}
+
+func New(opts *Options) *Flow {
+	var o Options
+	if opts != nil {
+		o = *opts
+	}
+	o.SetDefaults()
+	return &Flow{opts: o}
+}
+
+// Create a URL to redirect the user to, to log in at the authorization server.
+// The state, nonce and verifier are saved in the store, next is returned by Exchange.
+func (f *Flow) AuthCodeURL(next string) (string, error) {
+	var p = Pending{
+		State:    randomString(),
+		Nonce:    randomString(),
+		Verifier: NewVerifier(),
+		Next:     next,
+	}
+	if err := f.opts.Store.Save(p); err != nil {
+		return "", err
+	}
+	var u, err = url.Parse(f.opts.AuthorizeURL)
+	if err != nil {
+		return "", err
+	}
+	var q = u.Query()
+	for k, v := range f.opts.Params {
+		q[k] = v
+	}
+	q.Set("response_type", "code")
+	q.Set("client_id", f.opts.ClientID)
+	if f.opts.RedirectURL != "" {
+		q.Set("redirect_uri", f.opts.RedirectURL)
+	}
+	if len(f.opts.Scopes) > 0 {
+		q.Set("scope", strings.Join(f.opts.Scopes, " "))
+	}
+	q.Set("state", p.State)
+	q.Set("nonce", p.Nonce)
+	q.Set("code_challenge", Challenge(p.Verifier))
+	q.Set("code_challenge_method", "S256")
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
+// Exchange the code of a callback for tokens.
+// The query is the query of the callback URL, its state must have been created by AuthCodeURL.
+// A state can only be exchanged once.
+func (f *Flow) Exchange(ctx context.Context, query url.Values) (*TokenResponse, error) {
+	var state = query.Get("state")
+	if state == "" {
+		return nil, ErrInvalidState
+	}
+	var p, err = f.opts.Store.Take(state)
+	if err != nil {
+		return nil, err
+	}
+	if query.Get("error") != "" {
+		return nil, &Error{
+			Code:        query.Get("error"),
+			Description: query.Get("error_description"),
+			URI:         query.Get("error_uri"),
+		}
+	}
+	var code = query.Get("code")
+	if code == "" {
+		return nil, ErrNoCode
+	}
+	var form = url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"client_id":     {f.opts.ClientID},
+		"code_verifier": {p.Verifier},
+	}
+	if f.opts.RedirectURL != "" {
+		form.Set("redirect_uri", f.opts.RedirectURL)
+	}
+	resp, err := f.token(ctx, form)
+	if err != nil {
+		return nil, err
+	}
+	if resp.IDToken != "" {
+		if resp.Claims, err = f.idClaims(ctx, resp.IDToken); err != nil {
+			return nil, err
+		}
+		if nonce, _ := resp.Claims["nonce"].(string); nonce != p.Nonce {
+			return nil, ErrInvalidNonce
+		}
+	}
+	resp.Next = p.Next
+	return resp, nil
+}
+
+// Get new tokens with a refresh token.
+// Servers which do not rotate refresh tokens omit it, the given refresh token is returned instead.
+func (f *Flow) Refresh(ctx context.Context, refreshToken string) (*TokenResponse, error) {
+	var resp, err = f.token(ctx, url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {refreshToken},
+		"client_id":     {f.opts.ClientID},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if resp.RefreshToken == "" {
+		resp.RefreshToken = refreshToken
+	}
+	return resp, nil
+}
+
+// Post a form to the token endpoint.
+func (f *Flow) token(ctx context.Context, form url.Values) (*TokenResponse, error) {
+	var r, err = f.opts.Client.NewRequest(ctx, http.MethodPost, f.opts.TokenURL, form.Encode())
+	if err != nil {
+		return nil, err
+	}
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	r.Header.Set("Accept", "application/json")
+	resp, err := f.opts.Client.Do(r)
+	if err != nil {
+		return nil, tokenError(err)
+	}
+	if resp.StatusCode >= 400 {
+		return nil, tokenError(client.NewError(resp))
+	}
+	defer resp.Body.Close()
+	var tokens TokenResponse
+	if err := json.NewDecoder(resp.Body).Decode(&tokens); err != nil {
+		return nil, err
+	}
+	if tokens.AccessToken == "" {
+		return nil, errors.New("oauth: no access token in response")
+	}
+	return &tokens, nil
+}
+
+// Turn errors of the token endpoint into an *Error.
+func tokenError(err error) error {
+	var e *client.Error
+	if !errors.As(err, &e) {
+		return err
+	}
+	var oauthErr Error
+	if json.Unmarshal(e.Body, &oauthErr) != nil || oauthErr.Code == "" {
+		return err
+	}
+	return &oauthErr
+}
+
+// Create a new PKCE code verifier.
+func NewVerifier() string {
+	return randomString() + randomString()
+}
+
+// S256 code challenge of a PKCE code verifier.
+func Challenge(verifier string) string {
+	var sum = sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// Random URL safe string of 22 characters.
+func randomString() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic("oauth: cannot read random bytes: " + err.Error())
+	}
+	return base64.RawURLEncoding.EncodeToString(b[:])
+}
+
+// Claims of the ID token, verified if the flow has a verifier.
+func (f *Flow) idClaims(ctx context.Context, idToken string) (map[string]interface{}, error) {
+	var token *jwt.Token
+	var err error
+	if f.opts.IDTokenVerifier != nil {
+		token, err = f.opts.IDTokenVerifier.Verify(ctx, idToken)
+	} else {
+		token, err = jwt.Parse(idToken)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return token.Claims, nil
+}
@@ -0,0 +1,140 @@
+package oauth_test
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/Nigel2392/jsext-framework/tokens/jwt"
+	"github.com/Nigel2392/jsext-framework/tokens/oauth"
+)
+
+// Stand-in authorization server, which issues tokens for codes with the challenge of the authorize URL.
+type server struct {
+	*httptest.Server
+	challenge string
+	nonce     string
+}
+
+func newServer(t *testing.T) *server {
+	var s = &server{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		w.Header().Set("Content-Type", "application/json")
+		if r.Form.Get("grant_type") == "refresh_token" {
+			json.NewEncoder(w).Encode(map[string]any{"access_token": "access-2", "expires_in": 60})
+			return
+		}
+		if r.Form.Get("code") != "code" || oauth.Challenge(r.Form.Get("code_verifier")) != s.challenge {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error": "invalid_grant", "error_description": "bad code"}`))
+			return
+		}
+		var claims, _ = json.Marshal(map[string]any{"sub": "1", "nonce": s.nonce})
+		json.NewEncoder(w).Encode(map[string]any{
+			"access_token":  "access",
+			"refresh_token": "refresh",
+			"expires_in":    300,
+			"id_token":      "eyJhbGciOiJIUzI1NiJ9." + base64.RawURLEncoding.EncodeToString(claims) + ".sig",
+		})
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *server) authorize(t *testing.T, f *oauth.Flow, next string) url.Values {
+	var authURL, err = f.AuthCodeURL(next)
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, _ := url.Parse(authURL)
+	var q = u.Query()
+	if q.Get("code_challenge_method") != "S256" || q.Get("client_id") != "app" || q.Get("scope") != "openid profile" {
+		t.Fatalf("unexpected authorize url %s", authURL)
+	}
+	s.challenge = q.Get("code_challenge")
+	if s.nonce == "" {
+		s.nonce = q.Get("nonce")
+	}
+	return url.Values{"code": {"code"}, "state": {q.Get("state")}}
+}
+
+func newFlow(s *server, verifier *jwt.Verifier) *oauth.Flow {
+	return oauth.New(&oauth.Options{
+		ClientID:        "app",
+		AuthorizeURL:    "https://auth.example.com/authorize",
+		TokenURL:        s.URL,
+		RedirectURL:     "https://app.example.com/callback",
+		Scopes:          []string{"openid", "profile"},
+		IDTokenVerifier: verifier,
+	})
+}
+
+func TestExchange(t *testing.T) {
+	var s = newServer(t)
+	var f = newFlow(s, nil)
+	var callback = s.authorize(t, f, "/profile")
+	var resp, err = f.Exchange(context.Background(), callback)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.AccessToken != "access" || resp.RefreshToken != "refresh" || resp.ExpiresIn != 300 {
+		t.Fatalf("unexpected response %+v", resp)
+	}
+	if resp.Next != "/profile" || resp.Claims["sub"] != "1" {
+		t.Fatalf("unexpected next %q or claims %v", resp.Next, resp.Claims)
+	}
+	// A state can only be used once.
+	if _, err = f.Exchange(context.Background(), callback); err != oauth.ErrInvalidState {
+		t.Fatalf("expected invalid state, got %v", err)
+	}
+
+	refreshed, err := f.Refresh(context.Background(), "refresh")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if refreshed.AccessToken != "access-2" || refreshed.RefreshToken != "refresh" {
+		t.Fatalf("unexpected refresh response %+v", refreshed)
+	}
+}
+
+func TestExchangeErrors(t *testing.T) {
+	var s = newServer(t)
+	var f = newFlow(s, nil)
+
+	if _, err := f.Exchange(context.Background(), url.Values{"code": {"code"}, "state": {"unknown"}}); err != oauth.ErrInvalidState {
+		t.Fatalf("expected invalid state, got %v", err)
+	}
+
+	var callback = s.authorize(t, f, "")
+	callback.Set("code", "wrong")
+	var _, err = f.Exchange(context.Background(), callback)
+	var oauthErr *oauth.Error
+	if !errors.As(err, &oauthErr) || oauthErr.Code != "invalid_grant" || oauthErr.Description != "bad code" {
+		t.Fatalf("expected invalid_grant error, got %v", err)
+	}
+
+	callback = s.authorize(t, f, "")
+	callback = url.Values{"error": {"access_denied"}, "state": {callback.Get("state")}}
+	if _, err = f.Exchange(context.Background(), callback); !errors.As(err, &oauthErr) || oauthErr.Code != "access_denied" {
+		t.Fatalf("expected access_denied error, got %v", err)
+	}
+
+	// The server keeps returning the nonce of the first flow.
+	callback = s.authorize(t, f, "")
+	if _, err = f.Exchange(context.Background(), callback); err != oauth.ErrInvalidNonce {
+		t.Fatalf("expected invalid nonce, got %v", err)
+	}
+
+	// The ID token is not signed with the secret.
+	f = newFlow(s, jwt.NewVerifier(jwt.StaticKey([]byte("secret")), &jwt.Options{Audience: "app"}))
+	callback = s.authorize(t, f, "")
+	if _, err = f.Exchange(context.Background(), callback); err != jwt.ErrInvalidSignature {
+		t.Fatalf("expected invalid signature, got %v", err)
+	}
+}
@@ -0,0 +1,109 @@
+//go:build js && wasm
+// +build js,wasm
+
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"syscall/js"
+	"time"
+
+	"github.com/Nigel2392/jsext"
+	"github.com/Nigel2392/jsext-framework/router/vars"
+	"github.com/Nigel2392/jsext-framework/tokens"
+)
+
+func init() {
+	newDefaultStore = func() PendingStore {
+		return SessionStore("jsext_oauth_")
+	}
+}
+
+// Store which keeps flows in sessionStorage, so they survive the redirect to the authorization server.
+// Keys are the prefix followed by the state.
+func SessionStore(prefix string) PendingStore {
+	return sessionStore(prefix)
+}
+
+type sessionStore string
+
+func (s sessionStore) Save(p Pending) error {
+	var b, err = json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	js.Value(jsext.Global).Get("sessionStorage").Call("setItem", string(s)+p.State, string(b))
+	return nil
+}
+
+func (s sessionStore) Take(state string) (Pending, error) {
+	var storage = js.Value(jsext.Global).Get("sessionStorage")
+	var item = storage.Call("getItem", string(s)+state)
+	if item.IsNull() || item.IsUndefined() {
+		return Pending{}, ErrInvalidState
+	}
+	storage.Call("removeItem", string(s)+state)
+	var p Pending
+	if err := json.Unmarshal([]byte(item.String()), &p); err != nil || p.State != state {
+		return Pending{}, ErrInvalidState
+	}
+	return p, nil
+}
+
+// Redirect the user to the authorization server to log in.
+// After logging in, the callback returns next.
+func (f *Flow) Login(next string) error {
+	var u, err = f.AuthCodeURL(next)
+	if err != nil {
+		return err
+	}
+	js.Value(jsext.Window).Get("location").Call("assign", u)
+	return nil
+}
+
+// Populate the token from a token response.
+// The token is initialized with the claims of the ID token as data, and refreshed through the flow.
+// The expires_in of the response is used as the access timeout of opaque tokens.
+func (f *Flow) Populate(t *tokens.Token, resp *TokenResponse) {
+	if resp.ExpiresIn > 0 {
+		t.SetAccessTimeout(time.Duration(resp.ExpiresIn) * time.Second)
+	}
+	t.RefreshWith(func(ctx context.Context, t *tokens.Token) error {
+		var _, refresh = t.Tokens()
+		var resp, err = f.Refresh(ctx, refresh)
+		if err != nil {
+			return err
+		}
+		if resp.ExpiresIn > 0 {
+			t.SetAccessTimeout(time.Duration(resp.ExpiresIn) * time.Second)
+		}
+		// Servers which do not rotate refresh tokens leave it out, the current one is kept.
+		t.SetTokens(resp.AccessToken, resp.RefreshToken)
+		return nil
+	})
+	var data = make(map[string]interface{}, len(resp.Claims))
+	for k, v := range resp.Claims {
+		data[k] = v
+	}
+	t.Init(resp.AccessToken, resp.RefreshToken, data)
+}
+
+// Route handler for the callback URL.
+// Exchanges the code, populates the token and calls done with the URL passed to Login.
+//
+//	rt.Register("oauth-callback", "/oauth/callback", flow.Callback(tokens.AuthToken, func(next string, err error) {
+//		...
+//	}))
+func (f *Flow) Callback(t *tokens.Token, done func(next string, err error)) func(v vars.Vars, u *url.URL) {
+	return func(v vars.Vars, u *url.URL) {
+		var resp, err = f.Exchange(context.Background(), u.Query())
+		if err != nil {
+			done("", err)
+			return
+		}
+		f.Populate(t, resp)
+		done(resp.Next, nil)
+	}
+}
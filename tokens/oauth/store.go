@@ -0,0 +1,44 @@
+package oauth
+
+import "sync"
+
+// PendingStore keeps flows in progress between the redirects.
+type PendingStore interface {
+	Save(p Pending) error
+	// Load and delete the flow with the state, returns ErrInvalidState if there is none.
+	Take(state string) (Pending, error)
+}
+
+// Store used when the options do not have a store.
+var newDefaultStore = func() PendingStore {
+	return NewMemoryStore()
+}
+
+// Store which keeps flows in memory.
+// In the browser the page is reloaded by the redirect, use SessionStore instead.
+type MemoryStore struct {
+	mu      sync.Mutex
+	pending map[string]Pending
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{pending: make(map[string]Pending)}
+}
+
+func (m *MemoryStore) Save(p Pending) error {
+	m.mu.Lock()
+	m.pending[p.State] = p
+	m.mu.Unlock()
+	return nil
+}
+
+func (m *MemoryStore) Take(state string) (Pending, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var p, ok = m.pending[state]
+	if !ok {
+		return Pending{}, ErrInvalidState
+	}
+	delete(m.pending, state)
+	return p, nil
+}
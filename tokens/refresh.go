@@ -0,0 +1,196 @@
+package tokens
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/Nigel2392/jsext-framework/client"
+	"github.com/Nigel2392/jsext-framework/tokens/schema"
+)
+
+// Returned when the token cannot be refreshed, because there is no refresh token or it has expired.
+var ErrSessionExpired = errors.New("tokens: session expired")
+
+// Refresh in flight, shared by all callers.
+type refreshCall struct {
+	done chan struct{}
+	err  error
+}
+
+// Callback for when the session expires: the server rejected the refresh token after it answered 401.
+// The token is reset before the callback is called.
+func (t *Token) OnSessionExpired(f func()) {
+	t.onSessionExpired = f
+}
+
+// Refresh the token with a function instead of posting the refresh token to the refresh URL.
+// The function should update the access and refresh tokens with SetTokens, the OnUpdate callback is called after it returns.
+// The context has the values of the context of the first caller, it is not cancelled with that caller as the refresh is shared.
+func (t *Token) RefreshWith(f func(ctx context.Context, t *Token) error) {
+	t.refreshWith = f
+}
+
+// Refresh the access token with the refresh token.
+// Concurrent calls share a single request to the refresh URL.
+func (t *Token) Refresh() error {
+	return t.RefreshContext(context.Background())
+}
+
+// Refresh the access token with the refresh token, see Token.Refresh.
+// The shared refresh is detached from the contexts of the callers, so it is not cancelled with the first caller.
+// Returns the error of the context if it is done before the refresh finished.
+func (t *Token) RefreshContext(ctx context.Context) error {
+	t.mu.Lock()
+	var c = t.refreshing
+	if c == nil {
+		c = &refreshCall{done: make(chan struct{})}
+		t.refreshing = c
+		go t.refresh(client.Detach(ctx), c)
+	}
+	t.mu.Unlock()
+	select {
+	case <-c.done:
+		return c.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (t *Token) refresh(ctx context.Context, c *refreshCall) {
+	var _, refresh, _ = t.current()
+	if refresh == "" || t.IsRefreshExpired() {
+		c.err = ErrSessionExpired
+	} else if c.err = t.update(ctx); c.err == nil {
+		if access, _, _ := t.current(); access == "" {
+			c.err = ErrSessionExpired
+		}
+	}
+	t.mu.Lock()
+	if t.refreshing == c {
+		t.refreshing = nil
+	}
+	t.mu.Unlock()
+	close(c.done)
+}
+
+func (t *Token) update(ctx context.Context) error {
+	if t.refreshWith != nil {
+		if err := t.refreshWith(ctx, t); err != nil {
+			return err
+		}
+		t.updated()
+		return nil
+	}
+	return t.UpdateContext(ctx)
+}
+
+// Reset the token and call the session expired callback,
+// unless the token was already replaced since the request with the given access token was made.
+func (t *Token) expireSession(used string) {
+	if access, _, _ := t.current(); access != used {
+		return
+	}
+	t.Reset()
+	if t.onSessionExpired != nil {
+		t.onSessionExpired()
+	}
+}
+
+// Interceptor which sets the Bearer header to the access token.
+//
+// When the server answers 401, the token is refreshed once across all concurrent requests,
+// and the request is sent again with the new access token.
+// If the refresh token is rejected, the token is reset, OnSessionExpired is called and the 401 is returned.
+func (t *Token) Interceptor() client.Interceptor {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return client.RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			var used, _, _ = t.current()
+			if used == "" || r.Header.Get("Authorization") != "" {
+				return next.RoundTrip(r)
+			}
+			var resp, err = next.RoundTrip(t.authorize(r, used))
+			if !unauthorized(resp, err) {
+				return resp, err
+			}
+			var body io.ReadCloser
+			if r.Body != nil && r.Body != http.NoBody {
+				if r.GetBody == nil {
+					return resp, err
+				}
+				var bodyErr error
+				if body, bodyErr = r.GetBody(); bodyErr != nil {
+					return resp, err
+				}
+			}
+			// Another request may have refreshed the token already.
+			var access, _, _ = t.current()
+			if access == used {
+				if refreshErr := t.RefreshContext(r.Context()); refreshErr != nil {
+					if rejected(refreshErr) {
+						t.expireSession(used)
+					}
+					return resp, err
+				}
+				access, _, _ = t.current()
+			}
+			if access == "" {
+				return resp, err
+			}
+			if resp != nil {
+				io.Copy(io.Discard, resp.Body)
+				resp.Body.Close()
+			}
+			var retry = t.authorize(r, access)
+			if body != nil {
+				retry.Body = body
+			}
+			return next.RoundTrip(retry)
+		})
+	}
+}
+
+// Interceptor which authorizes requests with the token returned by the function when the request is made,
+// such as Current. Requests are sent as is while it returns nil.
+func Authorize(token func() *Token) client.Interceptor {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return client.RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			var t = token()
+			if t == nil {
+				return next.RoundTrip(r)
+			}
+			return t.Interceptor()(next).RoundTrip(r)
+		})
+	}
+}
+
+// Client which authenticates requests with the token, see Token.Interceptor.
+// The token interceptor is added after the given interceptors.
+func (t *Token) HTTPClient(interceptors ...client.Interceptor) *client.Client {
+	return client.New(append(interceptors, t.Interceptor())...)
+}
+
+func (t *Token) authorize(r *http.Request, access string) *http.Request {
+	r = r.Clone(r.Context())
+	r.Header.Set("Authorization", "Bearer "+access)
+	return r
+}
+
+// Whether the refresh token was rejected, instead of the refresh failing because of the network or a server error.
+func rejected(err error) bool {
+	var e *schema.Error
+	if errors.As(err, &e) {
+		return e.Status < http.StatusInternalServerError
+	}
+	return errors.Is(err, ErrSessionExpired) || errors.Is(err, schema.ErrNoAccessToken)
+}
+
+// Check for a 401 response, or a 401 error decoded by client.DecodeErrors.
+func unauthorized(resp *http.Response, err error) bool {
+	var e *client.Error
+	if errors.As(err, &e) {
+		return e.StatusCode == http.StatusUnauthorized
+	}
+	return err == nil && resp != nil && resp.StatusCode == http.StatusUnauthorized
+}
@@ -0,0 +1,286 @@
+// Package schema maps the requests and responses of an authentication API.
+//
+// Fields are addressed with dot separated paths, such as "data.tokens.access",
+// so tokens can be read from nested responses and written into nested requests.
+// Error responses are decoded into an *Error, which understands Django REST framework errors,
+// maps of field errors and RFC 7807 problem details.
+package schema
+
+import (
+	"encoding/json"
+	"errors"
+	"mime"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Kind of request made to the authentication API.
+type Kind string
+
+const (
+	Login    Kind = "login"
+	Register Kind = "register"
+	Refresh  Kind = "refresh"
+	Logout   Kind = "logout"
+)
+
+// Returned when a response does not have an access token.
+var ErrNoAccessToken = errors.New("schema: no access token in response")
+
+// Schema of an authentication API.
+type Schema struct {
+	// Path of the access token in responses.
+	AccessPath string
+	// Path of the refresh token in responses.
+	RefreshPath string
+	// Path of the refresh token in refresh and logout requests, defaults to RefreshPath.
+	RefreshField string
+	// Path of the user data in login and register responses.
+	// If empty, the data is the response without the tokens.
+	DataPath string
+	// Path of an error message, which makes any response an error.
+	ErrorPath string
+	// Build the body of a request from the data passed to login or register,
+	// or the refresh token for refresh and logout requests.
+	// Defaults to sending the data as is.
+	Body func(kind Kind, data map[string]interface{}) map[string]interface{}
+}
+
+// Schema of an API with the tokens and error message at the top level of the responses.
+func New(accessPath, refreshPath, errorPath string) *Schema {
+	return &Schema{
+		AccessPath:  accessPath,
+		RefreshPath: refreshPath,
+		ErrorPath:   errorPath,
+	}
+}
+
+// Tokens and data read from a response.
+type Result struct {
+	Access string
+	// Empty if the response does not have a refresh token,
+	// for example when refresh tokens are not rotated.
+	Refresh string
+	Data    map[string]interface{}
+}
+
+// Body of a login or register request.
+func (s *Schema) Request(kind Kind, data map[string]interface{}) map[string]interface{} {
+	if s.Body != nil {
+		return s.Body(kind, data)
+	}
+	return data
+}
+
+// Body of a refresh or logout request.
+func (s *Schema) RefreshRequest(kind Kind, refreshToken string) map[string]interface{} {
+	var field = s.RefreshField
+	if field == "" {
+		field = s.RefreshPath
+	}
+	var body = make(map[string]interface{})
+	Assign(body, field, refreshToken)
+	return s.Request(kind, body)
+}
+
+// Read the tokens and data from a response.
+// Returns an *Error for error responses.
+func (s *Schema) Response(status int, contentType string, body []byte) (*Result, error) {
+	if err := s.Error(status, contentType, body); err != nil {
+		return nil, err
+	}
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(body, &decoded); err != nil {
+		return nil, err
+	}
+	var r = &Result{}
+	var ok bool
+	if r.Access, ok = lookupString(decoded, s.AccessPath); !ok || r.Access == "" {
+		return nil, ErrNoAccessToken
+	}
+	r.Refresh, _ = lookupString(decoded, s.RefreshPath)
+	if s.DataPath != "" {
+		var data, _ = Lookup(decoded, s.DataPath)
+		r.Data, _ = data.(map[string]interface{})
+	} else {
+		r.Data = decoded
+		Delete(r.Data, s.AccessPath)
+		Delete(r.Data, s.RefreshPath)
+	}
+	if r.Data == nil {
+		r.Data = make(map[string]interface{})
+	}
+	return r, nil
+}
+
+// Check a response for an error.
+// Responses with a status of 400 or higher, or with a message at the error path, are errors.
+func (s *Schema) Error(status int, contentType string, body []byte) error {
+	var decoded map[string]interface{}
+	json.Unmarshal(body, &decoded)
+	var message, hasMessage = lookupString(decoded, s.ErrorPath)
+	if status < 400 && (!hasMessage || message == "") {
+		return nil
+	}
+	var e = ParseError(status, contentType, body)
+	if hasMessage && message != "" {
+		e.Message = message
+	}
+	return e
+}
+
+// Error returned by the authentication API.
+type Error struct {
+	// Status code of the response.
+	Status int
+	// Message of the error, from the "detail", "message", "error", "title" or "non_field_errors" fields.
+	Message string
+	// Errors of the fields of the request, as returned by Django REST framework and similar APIs.
+	Fields map[string][]string
+	// Problem details of RFC 7807 responses.
+	Type     string
+	Title    string
+	Instance string
+	// Decoded body of the response, nil if it is not a JSON object.
+	Body map[string]interface{}
+}
+
+func (e *Error) Error() string {
+	var msg = e.Message
+	if msg == "" && e.Status != 0 {
+		msg = "request failed with status " + strconv.Itoa(e.Status)
+	}
+	if len(e.Fields) == 0 {
+		return msg
+	}
+	var names = make([]string, 0, len(e.Fields))
+	for name := range e.Fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var parts = make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, name+": "+strings.Join(e.Fields[name], " "))
+	}
+	if msg == "" {
+		return strings.Join(parts, ", ")
+	}
+	return msg + " (" + strings.Join(parts, ", ") + ")"
+}
+
+// First error of a field, empty if the field has no errors.
+func (e *Error) Field(name string) string {
+	if errs := e.Fields[name]; len(errs) > 0 {
+		return errs[0]
+	}
+	return ""
+}
+
+// Decode an error response.
+func ParseError(status int, contentType string, body []byte) *Error {
+	var e = &Error{Status: status}
+	if json.Unmarshal(body, &e.Body) != nil {
+		e.Body = nil
+		e.Message = strings.TrimSpace(string(body))
+		if len(e.Message) > 200 || strings.HasPrefix(e.Message, "<") {
+			e.Message = ""
+		}
+		return e
+	}
+	var mediaType, _, _ = mime.ParseMediaType(contentType)
+	var problem = mediaType == "application/problem+json"
+	for key, value := range e.Body {
+		switch key {
+		case "detail", "message", "error", "title", "non_field_errors", "type", "instance", "status", "code", "messages":
+			continue
+		}
+		// Problem details can have extension members, which are not field errors.
+		if errs := messages(value); len(errs) > 0 && !problem {
+			if e.Fields == nil {
+				e.Fields = make(map[string][]string)
+			}
+			e.Fields[key] = errs
+		}
+	}
+	e.Type, _ = e.Body["type"].(string)
+	e.Title, _ = e.Body["title"].(string)
+	e.Instance, _ = e.Body["instance"].(string)
+	for _, key := range []string{"detail", "message", "error", "title", "non_field_errors"} {
+		if msgs := messages(e.Body[key]); len(msgs) > 0 {
+			e.Message = strings.Join(msgs, " ")
+			break
+		}
+	}
+	return e
+}
+
+// Messages of a field error, which is a string or a list of strings.
+func messages(v interface{}) []string {
+	switch v := v.(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		var msgs = make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				msgs = append(msgs, s)
+			}
+		}
+		return msgs
+	}
+	return nil
+}
+
+// Look up the value at a dot separated path.
+func Lookup(v interface{}, path string) (interface{}, bool) {
+	if path == "" {
+		return nil, false
+	}
+	for _, key := range strings.Split(path, ".") {
+		var m, ok = v.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if v, ok = m[key]; !ok {
+			return nil, false
+		}
+	}
+	return v, true
+}
+
+func lookupString(v interface{}, path string) (string, bool) {
+	var value, ok = Lookup(v, path)
+	if !ok {
+		return "", false
+	}
+	var s, isString = value.(string)
+	return s, isString
+}
+
+// Set the value at a dot separated path, creating the objects in between.
+func Assign(m map[string]interface{}, path string, value interface{}) {
+	var keys = strings.Split(path, ".")
+	for _, key := range keys[:len(keys)-1] {
+		var next, ok = m[key].(map[string]interface{})
+		if !ok {
+			next = make(map[string]interface{})
+			m[key] = next
+		}
+		m = next
+	}
+	m[keys[len(keys)-1]] = value
+}
+
+// Delete the value at a dot separated path.
+func Delete(m map[string]interface{}, path string) {
+	var keys = strings.Split(path, ".")
+	for _, key := range keys[:len(keys)-1] {
+		var next, ok = m[key].(map[string]interface{})
+		if !ok {
+			return
+		}
+		m = next
+	}
+	delete(m, keys[len(keys)-1])
+}
@@ -0,0 +1,102 @@
+package schema_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/Nigel2392/jsext-framework/tokens/schema"
+)
+
+func TestResponse(t *testing.T) {
+	var s = &schema.Schema{
+		AccessPath:  "data.tokens.access",
+		RefreshPath: "data.tokens.refresh",
+		DataPath:    "data.user",
+	}
+	var r, err = s.Response(200, "application/json", []byte(`{"data": {"tokens": {"access": "a", "refresh": "r"}, "user": {"id": 1}}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Access != "a" || r.Refresh != "r" || r.Data["id"] != float64(1) {
+		t.Fatalf("unexpected result %+v", r)
+	}
+
+	if _, err = s.Response(200, "application/json", []byte(`{"data": {"tokens": {"access": 1}}}`)); err != schema.ErrNoAccessToken {
+		t.Fatalf("expected no access token, got %v", err)
+	}
+
+	// Without a data path, the data is the response without the tokens.
+	s = schema.New("access", "refresh", "detail")
+	r, err = s.Response(200, "application/json", []byte(`{"access": "a", "refresh": "r", "username": "admin"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(r.Data, map[string]interface{}{"username": "admin"}) {
+		t.Fatalf("unexpected data %v", r.Data)
+	}
+
+	// A message at the error path is an error, regardless of the status.
+	_, err = s.Response(200, "application/json", []byte(`{"detail": "Invalid credentials"}`))
+	var e *schema.Error
+	if !errors.As(err, &e) || e.Message != "Invalid credentials" {
+		t.Fatalf("expected an error, got %v", err)
+	}
+}
+
+func TestRequest(t *testing.T) {
+	var s = &schema.Schema{
+		RefreshPath:  "refresh",
+		RefreshField: "auth.refresh_token",
+		Body: func(kind schema.Kind, data map[string]interface{}) map[string]interface{} {
+			return map[string]interface{}{"kind": string(kind), "payload": data}
+		},
+	}
+	var body = s.RefreshRequest(schema.Refresh, "r")
+	var want = map[string]interface{}{
+		"kind":    "refresh",
+		"payload": map[string]interface{}{"auth": map[string]interface{}{"refresh_token": "r"}},
+	}
+	if !reflect.DeepEqual(body, want) {
+		t.Fatalf("unexpected body %v", body)
+	}
+}
+
+func TestParseError(t *testing.T) {
+	var tests = []struct {
+		contentType string
+		body        string
+		want        schema.Error
+	}{
+		{"application/json", `{"detail": "Authentication credentials were not provided."}`, schema.Error{
+			Message: "Authentication credentials were not provided.",
+		}},
+		{"application/json", `{"detail": "Token is invalid", "code": "token_not_valid", "messages": [{"message": "expired"}]}`, schema.Error{
+			Message: "Token is invalid",
+		}},
+		{"application/json", `{"email": ["Enter a valid email address."], "password": "Too short.", "non_field_errors": ["Passwords do not match."]}`, schema.Error{
+			Message: "Passwords do not match.",
+			Fields:  map[string][]string{"email": {"Enter a valid email address."}, "password": {"Too short."}},
+		}},
+		{"application/problem+json; charset=utf-8", `{"type": "https://example.com/probs/locked", "title": "Account locked", "detail": "Too many attempts.", "instance": "/login", "retry": "60"}`, schema.Error{
+			Message:  "Too many attempts.",
+			Type:     "https://example.com/probs/locked",
+			Title:    "Account locked",
+			Instance: "/login",
+		}},
+		{"text/html", `<html>Bad gateway</html>`, schema.Error{}},
+	}
+	for i, test := range tests {
+		var e = schema.ParseError(400, test.contentType, []byte(test.body))
+		e.Body = nil
+		test.want.Status = 400
+		if !reflect.DeepEqual(*e, test.want) {
+			t.Fatalf("%d: expected %+v, got %+v", i, test.want, *e)
+		}
+	}
+
+	var e = schema.ParseError(400, "application/json", []byte(`{"email": ["Enter a valid email address."], "detail": "Invalid data"}`))
+	if e.Error() != "Invalid data (email: Enter a valid email address.)" || e.Field("email") != "Enter a valid email address." {
+		t.Fatalf("unexpected error %q", e.Error())
+	}
+}
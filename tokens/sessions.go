@@ -0,0 +1,260 @@
+package tokens
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/Nigel2392/jsext-framework/client"
+)
+
+// Returned when switching to a session which was not added.
+var ErrNoSession = errors.New("tokens: no such session")
+
+// Options for the sessions.
+type SessionOptions struct {
+	// Create the token of a session, defaults to a copy of the settings of AuthToken when the sessions are created.
+	New func(name string) *Token
+	// Store of the token of a session, defaults to a cookie named JSEXT_token_<name> in the browser,
+	// and a memory store elsewhere.
+	Store func(name string) TokenStore
+	// Key in localStorage of the name of the active session, defaults to "JSEXT_session".
+	// Outside of the browser, the name is kept in memory.
+	ActiveKey string
+}
+
+// Store of the token of a session, a cookie store in the browser.
+var newSessionStore = func(name string) TokenStore {
+	return NewMemoryStore()
+}
+
+// Storage of the name of the active session, localStorage in the browser.
+var newActiveStore = func(key string) activeStore {
+	return memoryActive(key)
+}
+
+// Remembers the name of the active session between page loads.
+type activeStore interface {
+	get() string
+	set(name string)
+	delete()
+}
+
+// Names of the active sessions outside of the browser, by key, kept as long as the process runs.
+var activeNames = struct {
+	sync.Mutex
+	names map[string]string
+}{names: make(map[string]string)}
+
+type memoryActive string
+
+func (m memoryActive) get() string {
+	activeNames.Lock()
+	defer activeNames.Unlock()
+	return activeNames.names[string(m)]
+}
+
+func (m memoryActive) set(name string) {
+	activeNames.Lock()
+	activeNames.names[string(m)] = name
+	activeNames.Unlock()
+}
+
+func (m memoryActive) delete() {
+	activeNames.Lock()
+	delete(activeNames.names, string(m))
+	activeNames.Unlock()
+}
+
+func (o *SessionOptions) SetDefaults() {
+	if o.New == nil {
+		// AuthToken is replaced by the active session, and unset when all sessions are removed.
+		var settings = AuthToken
+		o.New = func(name string) *Token {
+			var t = settings
+			if t == nil {
+				t = AuthToken
+			}
+			if t == nil {
+				panic("tokens: SessionOptions.New is required when AuthToken is not set")
+			}
+			return t.Copy()
+		}
+	}
+	if o.Store == nil {
+		o.Store = newSessionStore
+	}
+	if o.ActiveKey == "" {
+		o.ActiveKey = "JSEXT_session"
+	}
+}
+
+// Sessions holds several named tokens, such as a user account and an impersonated account,
+// or the accounts of two tenants. One of the sessions is active, and set as AuthToken.
+//
+// Every session is saved in its own store whenever it is initialized or updated,
+// and restored when it is added after a page load.
+type Sessions struct {
+	opts     SessionOptions
+	mu       sync.Mutex
+	tokens   map[string]*Token
+	names    []string
+	active   string
+	restore  string
+	storage  activeStore
+	onSwitch []func(name string, t *Token)
+}
+
+func NewSessions(opts *SessionOptions) *Sessions {
+	var o SessionOptions
+	if opts != nil {
+		o = *opts
+	}
+	o.SetDefaults()
+	var s = &Sessions{
+		opts:    o,
+		tokens:  make(map[string]*Token),
+		storage: newActiveStore(o.ActiveKey),
+	}
+	s.restore = s.storage.get()
+	return s
+}
+
+// Add a session, or get it if it was already added.
+// The token of the session is restored from its store.
+//
+// The first session added becomes active,
+// until the session which was active before the page loaded is added.
+func (s *Sessions) Add(name string) *Token {
+	s.mu.Lock()
+	if t, ok := s.tokens[name]; ok {
+		s.mu.Unlock()
+		return t
+	}
+	var t = s.opts.New(name)
+	t.SetStore(s.opts.Store(name))
+	t.AutoSave(true)
+	if err := t.Load(); err == nil && t.AccessToken != "" {
+		t.RunManager()
+	}
+	s.tokens[name] = t
+	s.names = append(s.names, name)
+	var activate = s.active == "" || name == s.restore
+	// Until the session which was active before the page loaded is added, it is still remembered.
+	var remember = s.restore == "" || name == s.restore
+	s.mu.Unlock()
+	if activate {
+		s.activate(name, remember)
+	}
+	return t
+}
+
+// Get the token of a session, nil if the session was not added.
+func (s *Sessions) Get(name string) *Token {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.tokens[name]
+}
+
+// Names of the sessions, in the order they were added.
+func (s *Sessions) Names() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.names...)
+}
+
+// Name of the active session.
+func (s *Sessions) ActiveName() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.active
+}
+
+// Token of the active session, nil if no session was added.
+func (s *Sessions) Active() *Token {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.tokens[s.active]
+}
+
+// Make the session active, and set its token as AuthToken.
+// The active session is remembered between page loads.
+func (s *Sessions) Switch(name string) error {
+	return s.activate(name, true)
+}
+
+func (s *Sessions) activate(name string, remember bool) error {
+	s.mu.Lock()
+	var t, ok = s.tokens[name]
+	if !ok {
+		s.mu.Unlock()
+		return ErrNoSession
+	}
+	s.active = name
+	if remember {
+		s.restore = ""
+	}
+	AuthToken = t
+	var onSwitch = append([]func(string, *Token){}, s.onSwitch...)
+	s.mu.Unlock()
+	if remember {
+		s.storage.set(name)
+	}
+	for _, f := range onSwitch {
+		f(name, t)
+	}
+	return nil
+}
+
+// Reset the token of the session, deleting it from its store, and remove the session.
+// If the session was active, the first remaining session becomes active.
+// AuthToken is set to nil when the last session is removed.
+func (s *Sessions) Remove(name string) {
+	s.mu.Lock()
+	var t, ok = s.tokens[name]
+	if !ok {
+		s.mu.Unlock()
+		return
+	}
+	delete(s.tokens, name)
+	for i, n := range s.names {
+		if n == name {
+			s.names = append(s.names[:i], s.names[i+1:]...)
+			break
+		}
+	}
+	var next string
+	if s.active == name {
+		s.active = ""
+		if len(s.names) > 0 {
+			next = s.names[0]
+		} else if AuthToken == t {
+			AuthToken = nil
+		}
+	}
+	s.mu.Unlock()
+	t.Reset()
+	if next != "" {
+		s.Switch(next)
+	} else if s.ActiveName() == "" {
+		s.storage.delete()
+	}
+}
+
+// Function to be ran when the active session changes.
+func (s *Sessions) OnSwitch(f func(name string, t *Token)) {
+	s.mu.Lock()
+	s.onSwitch = append(s.onSwitch, f)
+	s.mu.Unlock()
+}
+
+// Interceptor which authorizes requests with the token of the session which is active when the request is made.
+// Use the interceptor or HTTP client of a token to always use the same session.
+func (s *Sessions) Interceptor() client.Interceptor {
+	return Authorize(s.Active)
+}
+
+// HTTP client which authorizes requests with the token of the active session.
+// The session interceptor is added after the given interceptors.
+func (s *Sessions) HTTPClient(interceptors ...client.Interceptor) *client.Client {
+	return client.New(append(interceptors, s.Interceptor())...)
+}
@@ -0,0 +1,162 @@
+package tokens_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Nigel2392/jsext-framework/tokens"
+)
+
+// Sessions with memory stores, which are kept like cookies between "page loads".
+func newSessions(t *testing.T, stores map[string]*tokens.MemoryStore) *tokens.Sessions {
+	var authToken = tokens.AuthToken
+	t.Cleanup(func() { tokens.AuthToken = authToken })
+	var s = tokens.NewSessions(&tokens.SessionOptions{
+		New: func(name string) *tokens.Token {
+			return tokens.NewToken(time.Hour, time.Minute, "access", "refresh", "detail")
+		},
+		Store: func(name string) tokens.TokenStore {
+			if stores[name] == nil {
+				stores[name] = tokens.NewMemoryStore()
+			}
+			return stores[name]
+		},
+		ActiveKey: t.Name(),
+	})
+	// Removing every session also forgets the active session.
+	t.Cleanup(func() {
+		for _, name := range s.Names() {
+			s.Remove(name)
+		}
+	})
+	return s
+}
+
+func TestSessions(t *testing.T) {
+	var s = newSessions(t, make(map[string]*tokens.MemoryStore))
+	var user = s.Add("user")
+	var admin = s.Add("admin")
+	if s.Add("user") != user || s.Get("admin") != admin || s.Get("other") != nil {
+		t.Fatal("expected the added sessions")
+	}
+	if got := s.Names(); !reflect.DeepEqual(got, []string{"user", "admin"}) {
+		t.Fatalf("got names %v", got)
+	}
+	// The first session becomes active.
+	if s.ActiveName() != "user" || s.Active() != user || tokens.AuthToken != user || tokens.Current() != user {
+		t.Fatalf("expected user to be active, got %q", s.ActiveName())
+	}
+
+	var switched []string
+	s.OnSwitch(func(name string, tok *tokens.Token) {
+		if tok != s.Get(name) {
+			t.Errorf("switched to %q with another token", name)
+		}
+		switched = append(switched, name)
+	})
+	if err := s.Switch("admin"); err != nil {
+		t.Fatal(err)
+	}
+	if s.Active() != admin || tokens.Current() != admin {
+		t.Fatal("expected admin to be active")
+	}
+	if err := s.Switch("other"); !errors.Is(err, tokens.ErrNoSession) {
+		t.Fatalf("got %v, expected ErrNoSession", err)
+	}
+
+	admin.AccessToken = "access"
+	s.Remove("admin")
+	if admin.AccessToken != "" || s.Get("admin") != nil {
+		t.Error("expected the removed session to be reset")
+	}
+	if s.ActiveName() != "user" || tokens.Current() != user {
+		t.Errorf("expected user to be active after removing admin, got %q", s.ActiveName())
+	}
+	if !reflect.DeepEqual(switched, []string{"admin", "user"}) {
+		t.Errorf("switched to %v", switched)
+	}
+	s.Remove("user")
+	if s.ActiveName() != "" || s.Active() != nil || tokens.AuthToken != nil || tokens.Current() != nil {
+		t.Errorf("expected no active session, got %q", s.ActiveName())
+	}
+}
+
+func TestSessionsDefaultNew(t *testing.T) {
+	var authToken = tokens.AuthToken
+	t.Cleanup(func() { tokens.AuthToken = authToken })
+	tokens.AuthToken = tokens.NewToken(time.Hour, time.Minute, "access", "refresh", "detail")
+	var s = tokens.NewSessions(&tokens.SessionOptions{
+		Store:     func(name string) tokens.TokenStore { return tokens.NewMemoryStore() },
+		ActiveKey: t.Name(),
+	})
+	s.Add("user")
+	s.Remove("user")
+	// Sessions are still created with the settings of the original AuthToken.
+	if user := s.Add("user"); user == nil || tokens.AuthToken != user || user.AccessTimeout != time.Minute {
+		t.Fatal("expected the session to be added again")
+	}
+	s.Remove("user")
+}
+
+func TestSessionsRestore(t *testing.T) {
+	var stores = make(map[string]*tokens.MemoryStore)
+	var s = newSessions(t, stores)
+	s.Add("user")
+	var admin = s.Add("admin")
+	admin.AccessToken = "access-admin"
+	admin.RefreshToken = "refresh-admin"
+	admin.LastUpdate = time.Now()
+	if err := admin.Save(); err != nil {
+		t.Fatal(err)
+	}
+	s.Switch("admin")
+
+	// After a reload, the active session becomes active again once it is added, with its saved token.
+	s = newSessions(t, stores)
+	s.Add("user")
+	if s.ActiveName() != "user" {
+		t.Fatalf("expected user to be active until admin is added, got %q", s.ActiveName())
+	}
+	admin = s.Add("admin")
+	if s.ActiveName() != "admin" || admin.AccessToken != "access-admin" || admin.RefreshToken != "refresh-admin" {
+		t.Fatalf("got %q with %q %q", s.ActiveName(), admin.AccessToken, admin.RefreshToken)
+	}
+}
+
+func TestSessionsInterceptor(t *testing.T) {
+	var srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Authorization", r.Header.Get("Authorization"))
+	}))
+	defer srv.Close()
+
+	var s = newSessions(t, make(map[string]*tokens.MemoryStore))
+	var c = &http.Client{Transport: s.Interceptor()(srv.Client().Transport)}
+	var get = func() string {
+		var resp, err = c.Get(srv.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp.Header.Get("X-Authorization")
+	}
+	if got := get(); got != "" {
+		t.Errorf("sent %q without a session", got)
+	}
+	for _, name := range []string{"user", "admin"} {
+		var tok = s.Add(name)
+		tok.AccessToken = "access-" + name
+		tok.RefreshToken = "refresh-" + name
+		tok.LastUpdate = time.Now()
+	}
+	if got := get(); got != "Bearer access-user" {
+		t.Errorf("sent %q for user", got)
+	}
+	s.Switch("admin")
+	if got := get(); got != "Bearer access-admin" {
+		t.Errorf("sent %q after switching to admin", got)
+	}
+}
@@ -0,0 +1,38 @@
+//go:build js && wasm
+// +build js,wasm
+
+package tokens
+
+import "syscall/js"
+
+func init() {
+	newSessionStore = func(name string) TokenStore {
+		return NewCookieStore(&CookieOptions{Name: JSEXT_token + "_" + name})
+	}
+	newActiveStore = func(key string) activeStore {
+		return storageActive{LocalStorage(key)}
+	}
+}
+
+// Name of the active session in localStorage.
+type storageActive struct {
+	*StorageStore
+}
+
+func (s storageActive) get() string {
+	var name string
+	s.do(func(storage js.Value) {
+		if item := storage.Call("getItem", s.key); item.Type() == js.TypeString {
+			name = item.String()
+		}
+	})
+	return name
+}
+
+func (s storageActive) set(name string) {
+	s.call("setItem", s.key, name)
+}
+
+func (s storageActive) delete() {
+	s.Delete()
+}
@@ -0,0 +1,110 @@
+package tokens
+
+import (
+	"encoding/base64"
+	"errors"
+	"sync"
+	"time"
+)
+
+// Returned when a store does not have a token, or the stored token has expired.
+var ErrNoToken = errors.New("tokens: no stored token")
+
+// TokenStore persists tokens between page loads.
+type TokenStore interface {
+	// Save the token, it may be discarded after expires.
+	// An expiry of zero keeps the token until it is deleted, cookies until the browser closes.
+	Save(token SaveToken, expires time.Duration) error
+	// Load the token, returns ErrNoToken if there is none.
+	Load() (SaveToken, error)
+	// Delete the token.
+	Delete() error
+}
+
+// Store of tokens without a store set, a cookie store in the browser.
+var newDefaultStore = func() TokenStore {
+	return NewMemoryStore()
+}
+
+// Encode a token as a base64 string.
+func encodeSaveToken(token SaveToken) (string, error) {
+	var b, err = token.MarshalMsg(nil)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// Decode a token encoded with encodeSaveToken.
+func decodeSaveToken(s string) (SaveToken, error) {
+	var token SaveToken
+	var b, err = base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return token, err
+	}
+	left, err := token.UnmarshalMsg(b)
+	if err != nil {
+		return token, err
+	}
+	if len(left) > 0 {
+		return token, errors.New("left over bytes")
+	}
+	return token, nil
+}
+
+// Store which keeps the token in memory, it is lost when the page reloads.
+type MemoryStore struct {
+	mu    sync.Mutex
+	token *SaveToken
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{}
+}
+
+func (m *MemoryStore) Save(token SaveToken, expires time.Duration) error {
+	token.Expires = expiresAt(expires)
+	token.Data = copyData(token.Data)
+	m.mu.Lock()
+	m.token = &token
+	m.mu.Unlock()
+	return nil
+}
+
+func (m *MemoryStore) Load() (SaveToken, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.token == nil || m.token.expired() {
+		m.token = nil
+		return SaveToken{}, ErrNoToken
+	}
+	var token = *m.token
+	token.Data = copyData(token.Data)
+	return token, nil
+}
+
+func (m *MemoryStore) Delete() error {
+	m.mu.Lock()
+	m.token = nil
+	m.mu.Unlock()
+	return nil
+}
+
+// Time a saved token expires, the zero time if it does not expire.
+func expiresAt(expires time.Duration) time.Time {
+	if expires == 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(expires)
+}
+
+func copyData(data map[string]interface{}) map[string]interface{} {
+	if data == nil {
+		return nil
+	}
+	var m = make(map[string]interface{}, len(data))
+	for k, v := range data {
+		m[k] = v
+	}
+	return m
+}
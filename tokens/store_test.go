@@ -0,0 +1,106 @@
+package tokens_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Nigel2392/jsext-framework/tokens"
+)
+
+func saveToken() tokens.SaveToken {
+	return tokens.SaveToken{
+		AccessToken:  "access",
+		RefreshToken: "refresh",
+		LastUpdate:   time.Now().Truncate(time.Millisecond),
+		Data: map[string]interface{}{
+			"user":  "admin",
+			"id":    float64(5),
+			"admin": true,
+			"roles": []interface{}{"editor", "viewer"},
+			"org":   map[string]interface{}{"name": "acme"},
+		},
+	}
+}
+
+func TestEncodeSaveToken(t *testing.T) {
+	var tests = []struct {
+		name  string
+		token tokens.SaveToken
+	}{
+		{"empty", tokens.SaveToken{}},
+		{"without data", tokens.SaveToken{AccessToken: "access", RefreshToken: "refresh", LastUpdate: time.Now()}},
+		{"with data", saveToken()},
+		{"with expiry", func() tokens.SaveToken {
+			var token = saveToken()
+			token.Expires = time.Now().Add(time.Hour)
+			return token
+		}()},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var s, err = tokens.EncodeSaveToken(test.token)
+			if err != nil {
+				t.Fatal(err)
+			}
+			decoded, err := tokens.DecodeSaveToken(s)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if decoded.AccessToken != test.token.AccessToken || decoded.RefreshToken != test.token.RefreshToken {
+				t.Errorf("got tokens %q %q", decoded.AccessToken, decoded.RefreshToken)
+			}
+			if !decoded.LastUpdate.Equal(test.token.LastUpdate) || !decoded.Expires.Equal(test.token.Expires) {
+				t.Errorf("got times %v %v, want %v %v", decoded.LastUpdate, decoded.Expires, test.token.LastUpdate, test.token.Expires)
+			}
+			if len(test.token.Data) > 0 && !reflect.DeepEqual(decoded.Data, test.token.Data) {
+				t.Errorf("got data %#v, want %#v", decoded.Data, test.token.Data)
+			}
+			if len(test.token.Data) == 0 && len(decoded.Data) != 0 {
+				t.Errorf("got data %#v, want none", decoded.Data)
+			}
+		})
+	}
+}
+
+func TestDecodeSaveTokenErrors(t *testing.T) {
+	var s, _ = tokens.EncodeSaveToken(saveToken())
+	for _, value := range []string{"not base64!", s[:len(s)/2], s + "AA"} {
+		if _, err := tokens.DecodeSaveToken(value); err == nil {
+			t.Errorf("expected an error decoding %q", value)
+		}
+	}
+}
+
+func TestMemoryStore(t *testing.T) {
+	var store = tokens.NewMemoryStore()
+	if _, err := store.Load(); !errors.Is(err, tokens.ErrNoToken) {
+		t.Fatalf("expected ErrNoToken from an empty store, got %v", err)
+	}
+	var token = saveToken()
+	if err := store.Save(token, 50*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	// The stored data is not changed through the saved or loaded token.
+	token.Data["user"] = "changed"
+	loaded, err := store.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	loaded.Data["id"] = float64(6)
+	if loaded, _ = store.Load(); loaded.AccessToken != "access" || loaded.Data["user"] != "admin" || loaded.Data["id"] != float64(5) {
+		t.Errorf("got %+v", loaded)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if _, err := store.Load(); !errors.Is(err, tokens.ErrNoToken) {
+		t.Errorf("expected ErrNoToken after the token expired, got %v", err)
+	}
+
+	store.Save(saveToken(), time.Hour)
+	store.Delete()
+	if _, err := store.Load(); !errors.Is(err, tokens.ErrNoToken) {
+		t.Errorf("expected ErrNoToken after delete, got %v", err)
+	}
+}
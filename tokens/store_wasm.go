@@ -0,0 +1,206 @@
+//go:build js && wasm
+// +build js,wasm
+
+package tokens
+
+import (
+	"errors"
+	"strings"
+	"syscall/js"
+	"time"
+
+	"github.com/Nigel2392/jsext"
+)
+
+func init() {
+	newDefaultStore = func() TokenStore {
+		return NewCookieStore(nil)
+	}
+}
+
+// Options for the cookie of a cookie store.
+type CookieOptions struct {
+	// Name of the cookie, defaults to JSEXT_token.
+	Name string
+	// Path of the cookie, defaults to "/".
+	Path string
+	// Domain of the cookie, defaults to the current host.
+	Domain string
+	// Only send the cookie over HTTPS.
+	Secure bool
+	// SameSite attribute of the cookie: "Strict", "Lax" or "None", defaults to "Lax".
+	// Browsers require Secure for "None".
+	SameSite string
+}
+
+func (o *CookieOptions) SetDefaults() {
+	if o.Name == "" {
+		o.Name = JSEXT_token
+	}
+	if o.Path == "" {
+		o.Path = "/"
+	}
+	if o.SameSite == "" {
+		o.SameSite = "Lax"
+	}
+}
+
+// Store which keeps the token in a cookie.
+// Cookies are limited to 4096 bytes, persisted data must fit as well.
+type CookieStore struct {
+	opts CookieOptions
+}
+
+func NewCookieStore(opts *CookieOptions) *CookieStore {
+	var o CookieOptions
+	if opts != nil {
+		o = *opts
+	}
+	o.SetDefaults()
+	return &CookieStore{opts: o}
+}
+
+func (c *CookieStore) Save(token SaveToken, expires time.Duration) error {
+	var value, err = encodeSaveToken(token)
+	if err != nil {
+		return err
+	}
+	return c.set(value, expiresAt(expires))
+}
+
+func (c *CookieStore) Load() (SaveToken, error) {
+	var cookie = jsext.GetCookie(c.opts.Name)
+	if cookie == "" {
+		return SaveToken{}, ErrNoToken
+	}
+	return decodeSaveToken(cookie)
+}
+
+func (c *CookieStore) Delete() error {
+	return c.set("", time.Unix(0, 0))
+}
+
+func (c *CookieStore) set(value string, expires time.Time) error {
+	var b strings.Builder
+	b.WriteString(c.opts.Name + "=" + value)
+	// Without an expiry, the cookie is kept until the browser closes.
+	if !expires.IsZero() {
+		b.WriteString("; expires=" + expires.UTC().Format(time.RFC1123))
+	}
+	b.WriteString("; path=" + c.opts.Path)
+	if c.opts.Domain != "" {
+		b.WriteString("; domain=" + c.opts.Domain)
+	}
+	if c.opts.Secure {
+		b.WriteString("; secure")
+	}
+	b.WriteString("; samesite=" + c.opts.SameSite)
+	if b.Len() > 4096 {
+		return errors.New("cookie length exceeds 4096 bytes")
+	}
+	js.Value(jsext.Document).Set("cookie", b.String())
+	return nil
+}
+
+// Store which keeps the token in localStorage or sessionStorage.
+// The expiry is saved with the token, expired tokens are deleted when loaded.
+type StorageStore struct {
+	storage string
+	key     string
+}
+
+// Store which keeps the token in localStorage, shared between tabs and kept after the browser closes.
+func LocalStorage(key string) *StorageStore {
+	return &StorageStore{storage: "localStorage", key: key}
+}
+
+// Store which keeps the token in sessionStorage, it is removed when the tab closes.
+func SessionStorage(key string) *StorageStore {
+	return &StorageStore{storage: "sessionStorage", key: key}
+}
+
+func (s *StorageStore) Save(token SaveToken, expires time.Duration) error {
+	token.Expires = expiresAt(expires)
+	var value, err = encodeSaveToken(token)
+	if err != nil {
+		return err
+	}
+	return s.call("setItem", s.key, value)
+}
+
+func (s *StorageStore) Load() (SaveToken, error) {
+	var item js.Value
+	var err = s.do(func(storage js.Value) {
+		item = storage.Call("getItem", s.key)
+	})
+	if err != nil {
+		return SaveToken{}, err
+	}
+	if item.IsNull() || item.IsUndefined() {
+		return SaveToken{}, ErrNoToken
+	}
+	token, err := decodeSaveToken(item.String())
+	if err != nil {
+		return token, err
+	}
+	if token.expired() {
+		s.Delete()
+		return SaveToken{}, ErrNoToken
+	}
+	return token, nil
+}
+
+func (s *StorageStore) Delete() error {
+	return s.call("removeItem", s.key)
+}
+
+func (s *StorageStore) call(method string, args ...any) error {
+	return s.do(func(storage js.Value) {
+		storage.Call(method, args...)
+	})
+}
+
+// Storage can be disabled by the browser, or full; accessing it throws.
+func (s *StorageStore) do(f func(storage js.Value)) (err error) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			if jsErr, ok := rec.(js.Error); ok {
+				err = jsErr
+				return
+			}
+			panic(rec)
+		}
+	}()
+	var storage = js.Value(jsext.Global).Get(s.storage)
+	if storage.IsUndefined() || storage.IsNull() {
+		return errors.New("tokens: " + s.storage + " is not available")
+	}
+	f(storage)
+	return nil
+}
+
+// Set the token cookie from a token.
+// Use Token.Save to save the token in the store of the token.
+func SetTokenCookie(token *Token) error {
+	return NewCookieStore(nil).Save(token.saveToken(), token.RefreshExpiredIn())
+}
+
+// Get the token cookie
+// Use Token.Load to load the token from the store of the token.
+func GetTokenCookie(tokenToSet *Token) (*Token, error) {
+	var saveToken, err = NewCookieStore(nil).Load()
+	if err == ErrNoToken {
+		//lint:ignore ST1005 Error strings should not be capitalized
+		return nil, errors.New("No token cookie")
+	}
+	if err != nil {
+		return nil, err
+	}
+	tokenToSet.loadToken(saveToken)
+	return tokenToSet, nil
+}
+
+// Delete the token cookie
+func DeleteTokenCookie() {
+	NewCookieStore(nil).Delete()
+}
@@ -0,0 +1,113 @@
+package tokens
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/Nigel2392/jsext-framework/tabs"
+)
+
+// Tabs the token is shared with.
+type sharedTabs struct {
+	tabs *tabs.Tabs
+	key  string
+}
+
+// Token sent to the other tabs, an empty access token logs them out.
+type tabsToken struct {
+	AccessToken  string                 `json:"access"`
+	RefreshToken string                 `json:"refresh"`
+	LastUpdate   time.Time              `json:"last_update"`
+	Data         map[string]interface{} `json:"data,omitempty"`
+}
+
+// Share the token with the other open tabs over the key, and only run the token manager in the leader tab.
+// With rotating refresh tokens, tabs which refresh on their own invalidate each other's refresh token,
+// and servers which detect reuse of a refresh token end the session.
+//
+// Tokens obtained or refreshed in any tab are sent to the other tabs, and resetting the token resets it in all tabs.
+// Use a key per token when several tokens are shared, such as the tokens of Sessions.
+// The returned function stops sharing the token, the manager runs in every tab again.
+func (t *Token) ShareTabs(tb *tabs.Tabs, key string) (stop func()) {
+	t.mu.Lock()
+	t.tabs = &sharedTabs{tabs: tb, key: key}
+	t.mu.Unlock()
+	var removeData = tb.OnData(func(k string, value json.RawMessage) {
+		if k != key {
+			return
+		}
+		var msg tabsToken
+		if err := json.Unmarshal(value, &msg); err != nil {
+			return
+		}
+		t.received(msg)
+	})
+	var removeLeader = tb.RunAsLeader(func(ctx context.Context) {
+		if access, _, _ := t.current(); access != "" {
+			t.RunManager()
+		}
+		<-ctx.Done()
+		t.StopManager()
+	})
+	t.StopManager()
+	return func() {
+		removeData()
+		removeLeader()
+		t.mu.Lock()
+		t.tabs = nil
+		t.mu.Unlock()
+		if access, _, _ := t.current(); access != "" {
+			t.RunManager()
+		}
+	}
+}
+
+func (t *Token) sharedTabs() *sharedTabs {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.tabs
+}
+
+// Whether the token manager may run in this tab.
+func (t *Token) manages() bool {
+	var s = t.sharedTabs()
+	return s == nil || s.tabs.IsLeader()
+}
+
+// Send the token to the other tabs, if it is shared.
+func (t *Token) share() {
+	var s = t.sharedTabs()
+	if s == nil {
+		return
+	}
+	var access, refresh, lastUpdate = t.current()
+	s.tabs.Set(s.key, tabsToken{
+		AccessToken:  access,
+		RefreshToken: refresh,
+		LastUpdate:   lastUpdate,
+		Data:         t.Data,
+	})
+}
+
+// Apply a token sent by another tab, without sending it back.
+func (t *Token) received(msg tabsToken) {
+	if access, _, _ := t.current(); access == msg.AccessToken {
+		return
+	}
+	if msg.AccessToken == "" {
+		t.reset(false)
+		return
+	}
+	t.set(msg.AccessToken, msg.RefreshToken, msg.LastUpdate)
+	if msg.Data != nil {
+		t.Data = msg.Data
+	}
+	if t.autoSave {
+		t.Save()
+	}
+	if t.onUpdate != nil {
+		t.onUpdate(t)
+	}
+	t.RunManager()
+}
@@ -0,0 +1,81 @@
+package tokens_test
+
+import (
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Nigel2392/jsext-framework/tabs"
+	"github.com/Nigel2392/jsext-framework/tokens"
+)
+
+// Wait until the condition is true, or fail after the timeout.
+func eventually(t *testing.T, timeout time.Duration, cond func() bool) {
+	t.Helper()
+	var deadline = time.Now().Add(timeout)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met in time")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestShareTabs(t *testing.T) {
+	var s = newServer(t)
+	var refreshes int32
+	s.refresh = func(w http.ResponseWriter, r *http.Request) {
+		var n = atomic.AddInt32(&refreshes, 1)
+		fmt.Fprintf(w, `{"access": "access-%d", "refresh": "refresh-%d"}`, n+1, n+1)
+	}
+	var hub = tabs.NewHub()
+	var opts = &tabs.Options{Heartbeat: 10 * time.Millisecond}
+	var leaderTab = tabs.New(hub.Transport(), opts)
+	defer leaderTab.Close()
+	eventually(t, time.Second, leaderTab.IsLeader)
+	var otherTab = tabs.New(hub.Transport(), opts)
+	defer otherTab.Close()
+
+	var leader, other = s.token(), s.token()
+	for _, token := range []*tokens.Token{leader, other} {
+		// Refreshed 1.35 seconds after logging in.
+		token.AccessTimeout = 1500 * time.Millisecond
+		token.SetStore(tokens.NewMemoryStore())
+		defer token.StopManager()
+	}
+	defer leader.ShareTabs(leaderTab, "token")()
+	defer other.ShareTabs(otherTab, "token")()
+	time.Sleep(50 * time.Millisecond)
+	if otherTab.IsLeader() {
+		t.Fatal("expected a single leader")
+	}
+
+	// Logging in in another tab logs in the leader.
+	if err := other.Login(map[string]string{"password": "secret"}); err != nil {
+		t.Fatal(err)
+	}
+	eventually(t, time.Second, func() bool {
+		var access, refresh = leader.Tokens()
+		return access == "access-1" && refresh == "refresh-1" && leader.Data["user"] == "admin"
+	})
+
+	// Only the leader refreshes, the other tab receives the rotated tokens.
+	eventually(t, 3*time.Second, func() bool {
+		var access, refresh = other.Tokens()
+		return access == "access-2" && refresh == "refresh-2"
+	})
+	if n := atomic.LoadInt32(&refreshes); n != 1 {
+		t.Errorf("refreshed %d times, expected once", n)
+	}
+
+	// Logging out in any tab logs out all tabs.
+	if err := other.Logout(); err != nil {
+		t.Fatal(err)
+	}
+	eventually(t, time.Second, func() bool {
+		var access, _ = leader.Tokens()
+		return access == ""
+	})
+}
@@ -1,15 +1,22 @@
-//go:build js && wasm
-// +build js,wasm
-
 package tokens
 
 import (
-	"reflect"
+	"context"
+	"net/http"
+	"sync"
 	"time"
+
+	"github.com/Nigel2392/jsext-framework/tokens/schema"
 )
 
 var AuthToken *Token
 
+// Token of the active session, AuthToken at the time of the call.
+// Pass it to functions which look the token up on every use, so they follow Sessions.Switch.
+func Current() *Token {
+	return AuthToken
+}
+
 // URLs for the token to request to.
 type TokenURLs struct {
 	LoginURL    string
@@ -25,23 +32,43 @@ type Token struct {
 	LastUpdate           time.Time
 	RefreshTimeout       time.Duration
 	AccessTimeout        time.Duration
+	ClockSkew            time.Duration
 	URLs                 TokenURLs
 	AccessTokenVariable  string
 	RefreshTokenVariable string
 	errorMessageName     string
+	Schema               *schema.Schema
+	Transport            http.RoundTripper
 	Data                 map[string]interface{}
 	onUpdate             func(t *Token)
 	onReset              func()
 	onInit               func(t *Token)
 	onUpdateErr          func(err error)
-	needsUpdateChan      chan bool
+	onSessionExpired     func()
+	refreshWith          func(ctx context.Context, t *Token) error
+	stopManager          chan struct{}
+	refreshing           *refreshCall
+	mu                   *sync.Mutex
+	store                TokenStore
+	persistData          bool
+	autoSave             bool
+	tabs                 *sharedTabs
 }
 
+// Default tolerance for the clocks of the client and server differing.
+// Tokens are considered expired Token.ClockSkew before their exp claim,
+// but at most a tenth of their lifetime, so short-lived tokens stay valid.
+const DefaultClockSkew = 30 * time.Second
+
 // Get a new token
+//
+// Lifetimes are read from the exp and iat claims of JWTs,
+// the timeouts are only used for opaque tokens, and JWTs without an exp claim.
 func NewToken(RefreshTimeout, AccessTimeout time.Duration, AccessVar, RefreshVar, errorMessageName string) *Token {
 	var t = &Token{
 		RefreshTimeout:       RefreshTimeout,
 		AccessTimeout:        AccessTimeout,
+		ClockSkew:            DefaultClockSkew,
 		AccessTokenVariable:  AccessVar,
 		RefreshTokenVariable: RefreshVar,
 		errorMessageName:     errorMessageName,
@@ -52,6 +79,7 @@ func NewToken(RefreshTimeout, AccessTimeout time.Duration, AccessVar, RefreshVar
 			RefreshURL:  "http://127.0.0.1:8000/api/auth/refresh/",
 		},
 		Data: make(map[string]interface{}),
+		mu:   &sync.Mutex{},
 	}
 	return t
 }
@@ -61,6 +89,76 @@ func (t *Token) SetURLs(urls TokenURLs) {
 	t.URLs = urls
 }
 
+// Schema of the authentication API, defaults to a schema with the tokens
+// and error message at the top level of the responses, named by the token variables.
+func (t *Token) schema() *schema.Schema {
+	if t.Schema != nil {
+		return t.Schema
+	}
+	return schema.New(t.AccessTokenVariable, t.RefreshTokenVariable, t.errorMessageName)
+}
+
+// Set the store the token is saved in, defaults to a cookie store in the browser,
+// and a memory store elsewhere.
+func (t *Token) SetStore(store TokenStore) {
+	t.store = store
+}
+
+// Store the token is saved in.
+func (t *Token) Store() TokenStore {
+	if t.store == nil {
+		t.store = newDefaultStore()
+	}
+	return t.store
+}
+
+// Save the data of the token along with the access and refresh tokens.
+func (t *Token) PersistData(persist bool) {
+	t.persistData = persist
+}
+
+// Save the token in its store whenever it is initialized or updated.
+func (t *Token) AutoSave(save bool) {
+	t.autoSave = save
+}
+
+// Save the token in its store, until the refresh token expires.
+// If the expiry of the refresh token is unknown, the token is kept until it is deleted.
+func (t *Token) Save() error {
+	return t.Store().Save(t.saveToken(), t.RefreshExpiredIn())
+}
+
+// Load the token from its store.
+// Returns ErrNoToken if the store does not have a token.
+func (t *Token) Load() error {
+	var saveToken, err = t.Store().Load()
+	if err != nil {
+		return err
+	}
+	t.loadToken(saveToken)
+	return nil
+}
+
+func (t *Token) saveToken() SaveToken {
+	var access, refresh, lastUpdate = t.current()
+	var saveToken = SaveToken{
+		AccessToken:  access,
+		RefreshToken: refresh,
+		LastUpdate:   lastUpdate,
+	}
+	if t.persistData {
+		saveToken.Data = t.Data
+	}
+	return saveToken
+}
+
+func (t *Token) loadToken(saveToken SaveToken) {
+	t.set(saveToken.AccessToken, saveToken.RefreshToken, saveToken.LastUpdate)
+	if t.persistData && saveToken.Data != nil {
+		t.Data = saveToken.Data
+	}
+}
+
 // Callback for when the token gets updated.
 func (t *Token) OnUpdate(f func(t *Token)) {
 	t.onUpdate = f
@@ -82,112 +180,355 @@ func (t *Token) OnUpdateError(f func(err error)) {
 }
 
 // Needs update returns a channel which will send a bool when the token needs to be updated.
+// Every call returns a new channel, which receives a single value.
 func (t *Token) NeedsUpdate() <-chan bool {
-	if t.needsUpdateChan == nil {
-		t.needsUpdateChan = make(chan bool)
-	}
+	var c = make(chan bool, 1)
 	go func() {
-		for {
-			// Check if the token needs to be updated.
-			if t.ShouldUpdate() {
-				t.needsUpdateChan <- true
-				return
-			}
-			time.Sleep(t.ExpiredIn() - time.Duration(t.ExpiredIn()/10))
+		// Check if the token needs to be updated.
+		for !t.ShouldUpdate() {
+			time.Sleep(t.updateWait())
 		}
+		c <- true
 	}()
-	return t.needsUpdateChan
+	return c
 }
 
-func (t *Token) ShouldUpdate() bool {
-	if t.AccessToken == "" ||
-		t.RefreshToken == "" ||
-		t.RefreshTimeout == 0 ||
-		t.AccessTimeout == 0 ||
+// Time to wait before checking if the token should be updated again.
+func (t *Token) updateWait() time.Duration {
+	var wait = t.UpdateIn()
+	if wait < time.Second {
+		wait = time.Second
+	}
+	return wait
+}
+
+// Lifetime of an access or refresh token.
+type lifetime struct {
+	issued    time.Time
+	notBefore time.Time
+	expires   time.Time
+	// Whether the expiry is known, from the exp claim or a timeout.
+	known bool
+}
+
+// Whether the token has expired, a token with an unknown expiry does not expire.
+func (l lifetime) expired(now time.Time) bool {
+	return l.known && now.After(l.expires)
+}
+
+// Time the token expires, the zero time if the expiry is unknown.
+func (l lifetime) expiresAt() time.Time {
+	if !l.known {
+		return time.Time{}
+	}
+	return l.expires
+}
+
+// Time until the token expires, zero if the expiry is unknown.
+func (l lifetime) expiresIn() time.Duration {
+	if !l.known {
+		return 0
+	}
+	return time.Until(l.expires)
+}
+
+// Access and refresh tokens, and the time they were last updated.
+// Read them with this method when the token may be updated concurrently, such as in an interceptor.
+func (t *Token) current() (access, refresh string, lastUpdate time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.AccessToken, t.RefreshToken, t.LastUpdate
+}
+
+// Access token and its lifetime, read under the lock.
+func (t *Token) accessLifetime() (string, lifetime) {
+	t.mu.Lock()
+	var access, lastUpdate, timeout = t.AccessToken, t.LastUpdate, t.AccessTimeout
+	t.mu.Unlock()
+	return access, t.lifetime(access, lastUpdate, timeout)
+}
+
+// Refresh token and its lifetime, read under the lock.
+func (t *Token) refreshLifetime() (string, lifetime) {
+	t.mu.Lock()
+	var refresh, lastUpdate, timeout = t.RefreshToken, t.LastUpdate, t.RefreshTimeout
+	t.mu.Unlock()
+	return refresh, t.lifetime(refresh, lastUpdate, timeout)
+}
 
-		t.IsRefreshExpired() {
+// Access and refresh tokens.
+// Use it to read the token while it may be updated, such as from a RefreshWith function.
+func (t *Token) Tokens() (access, refresh string) {
+	access, refresh, _ = t.current()
+	return access, refresh
+}
+
+// Set the access and refresh tokens, and update the time they were last updated.
+// An empty refresh token keeps the current one, for servers which do not rotate refresh tokens.
+// Use it to update the token while it may be in use, such as from a RefreshWith function.
+func (t *Token) SetTokens(access, refresh string) {
+	t.mu.Lock()
+	t.AccessToken = access
+	if refresh != "" {
+		t.RefreshToken = refresh
+	}
+	t.LastUpdate = time.Now()
+	t.mu.Unlock()
+}
+
+// Set the lifetime of opaque access tokens, and JWTs without an exp claim.
+// Use it to update the token while it may be in use, such as from a RefreshWith function.
+func (t *Token) SetAccessTimeout(timeout time.Duration) {
+	t.mu.Lock()
+	t.AccessTimeout = timeout
+	t.mu.Unlock()
+}
+
+// Set the access and refresh tokens, and the time they were last updated.
+func (t *Token) set(access, refresh string, lastUpdate time.Time) {
+	t.mu.Lock()
+	t.AccessToken = access
+	t.RefreshToken = refresh
+	t.LastUpdate = lastUpdate
+	t.mu.Unlock()
+}
+
+// Lifetime of a token issued at the last update, read from the iat, nbf and exp claims of a JWT.
+// Opaque tokens, and JWTs without an exp claim, expire timeout after they were issued.
+// Without a timeout, their expiry is unknown and they are not considered expired.
+// Expiry and not-before times are moved forward by the clock skew.
+func (t *Token) lifetime(token string, lastUpdate time.Time, timeout time.Duration) lifetime {
+	var l = lifetime{issued: lastUpdate}
+	var claims Payload
+	if jwt, err := DecodeToken(token); err == nil {
+		claims = jwt.Payload
+	}
+	if iat := claims.GetTime("iat"); !iat.IsZero() {
+		l.issued = iat
+	}
+	var exp = claims.GetTime("exp")
+	if exp.IsZero() {
+		l.expires = l.issued.Add(timeout)
+		l.known = timeout != 0
+		return l
+	}
+	// Without a known issued time, the token was issued timeout before it expires.
+	if l.issued.IsZero() || !l.issued.Before(exp) {
+		l.issued = exp.Add(-timeout)
+	}
+	var skew = t.skew(exp.Sub(l.issued))
+	l.expires = exp.Add(-skew)
+	l.known = true
+	if nbf := claims.GetTime("nbf"); !nbf.IsZero() {
+		l.notBefore = nbf.Add(-skew)
+	}
+	return l
+}
+
+// Clock skew of a token with the lifetime, at most a tenth of the lifetime.
+func (t *Token) skew(lifetime time.Duration) time.Duration {
+	if lifetime > 0 && t.ClockSkew > lifetime/10 {
+		return lifetime / 10
+	}
+	return t.ClockSkew
+}
+
+func (t *Token) ShouldUpdate() bool {
+	var accessToken, access = t.accessLifetime()
+	var refreshToken, refresh = t.refreshLifetime()
+	if accessToken == "" ||
+		refreshToken == "" ||
+		!access.known ||
+		refresh.expired(time.Now()) {
 		return false
-	} else if t.IsExpired() {
-		return true
-	} else if t.LastUpdate.Add(t.AccessTimeout - time.Duration(t.AccessTimeout/10)).Before(time.Now()) {
-		return true
 	}
-	return false
+	return !time.Now().Before(access.updateAt())
+}
+
+// Update when 90% of the lifetime has passed.
+func (l lifetime) updateAt() time.Time {
+	var d = l.expires.Sub(l.issued)
+	return l.issued.Add(d - d/10)
+}
+
+// Get when the token should be updated, 90% into the lifetime of the access token.
+func (t *Token) UpdateIn() time.Duration {
+	var _, access = t.accessLifetime()
+	return time.Until(access.updateAt())
 }
 
-// Check if access token is expired
+// Check if access token is expired, false if its expiry is unknown.
 func (t *Token) IsExpired() bool {
-	return time.Now().After(t.LastUpdate.Add(t.AccessTimeout))
+	var _, access = t.accessLifetime()
+	return access.expired(time.Now())
 }
 
-// Check if refresh token is expired
+// Check if refresh token is expired, false if its expiry is unknown.
 func (t *Token) IsRefreshExpired() bool {
-	return time.Now().After(t.LastUpdate.Add(t.RefreshTimeout))
+	var _, refresh = t.refreshLifetime()
+	return refresh.expired(time.Now())
+}
+
+// Check if the access token can be used: it has not expired, and its nbf claim has passed.
+func (t *Token) IsValid() bool {
+	var accessToken, access = t.accessLifetime()
+	var now = time.Now()
+	return accessToken != "" && !now.Before(access.notBefore) && !access.expired(now)
+}
+
+// Get when the access token expires, the zero time if its expiry is unknown.
+func (t *Token) ExpiresAt() time.Time {
+	var _, access = t.accessLifetime()
+	return access.expiresAt()
 }
 
-// Get when the access token will expire
+// Get when the refresh token expires, the zero time if its expiry is unknown.
+func (t *Token) RefreshExpiresAt() time.Time {
+	var _, refresh = t.refreshLifetime()
+	return refresh.expiresAt()
+}
+
+// Get when the access token will expire, zero if its expiry is unknown.
 func (t *Token) ExpiredIn() time.Duration {
-	timeout := t.LastUpdate.Add(t.AccessTimeout)
-	return time.Until(timeout)
+	var _, access = t.accessLifetime()
+	return access.expiresIn()
 }
 
-// Get when the refresh token will expire
+// Get when the refresh token will expire, zero if its expiry is unknown.
 func (t *Token) RefreshExpiredIn() time.Duration {
-	timeout := t.LastUpdate.Add(t.RefreshTimeout)
-	return time.Until(timeout)
+	var _, refresh = t.refreshLifetime()
+	return refresh.expiresIn()
 }
 
 // Short hand for getting the token data
 func (t *Token) setToken(access, refresh string, lastUpdate time.Time) {
-	t.AccessToken = access
-	t.RefreshToken = refresh
-	t.LastUpdate = lastUpdate
+	t.set(access, refresh, lastUpdate)
+	if t.autoSave {
+		t.Save()
+	}
+	t.share()
 	t.RunManager()
 }
 
+// Save the token if it saves automatically, send it to the other tabs and call the OnUpdate callback.
+func (t *Token) updated() {
+	if t.autoSave {
+		t.Save()
+	}
+	t.share()
+	if t.onUpdate != nil {
+		t.onUpdate(t)
+	}
+}
+
+// Initialize the token with tokens obtained elsewhere, for example through OAuth2.
+// Runs the manager and calls the OnInit callback, like logging in does.
+func (t *Token) Init(access, refresh string, data map[string]interface{}) {
+	if data == nil {
+		data = make(map[string]interface{})
+	}
+	t.Data = data
+	t.setToken(access, refresh, time.Now())
+	if t.onInit != nil {
+		t.onInit(t)
+	}
+}
+
 // Run the token update manager.
 // This will automatically update the token every AccessTimeout - 10%
 // Automatically stops the manager if an error occurs when updating the token.
+// Only one manager runs per token, running it again replaces the previous one.
+// When the token is shared with other tabs, the manager only runs in the leader tab.
 func (t *Token) RunManager() {
-	t.StopManager()
+	if !t.manages() {
+		t.StopManager()
+		return
+	}
+	var stop = make(chan struct{})
+	t.mu.Lock()
+	if t.stopManager != nil {
+		close(t.stopManager)
+	}
+	t.stopManager = stop
+	t.mu.Unlock()
+	var wait = t.updateWait()
 	go func() {
-		for <-t.NeedsUpdate() {
-			var err = t.Update()
-			if err != nil {
-				if t.onUpdateErr != nil {
-					t.onUpdateErr(err)
-					t.StopManager()
-					return
-				} else {
-					panic(err)
+		for {
+			var timer = time.NewTimer(wait)
+			select {
+			case <-stop:
+				timer.Stop()
+				return
+			case <-timer.C:
+			}
+			if t.ShouldUpdate() {
+				if err := t.Refresh(); err != nil {
+					if t.onUpdateErr != nil {
+						t.onUpdateErr(err)
+						t.StopManager()
+						return
+					} else {
+						panic(err)
+					}
 				}
 			}
+			wait = t.updateWait()
 		}
 	}()
 }
 
 // Stop the token update manager.
 func (t *Token) StopManager() {
-	if t.needsUpdateChan != nil {
-		close(t.needsUpdateChan)
-		t.needsUpdateChan = nil
+	t.mu.Lock()
+	if t.stopManager != nil {
+		close(t.stopManager)
+		t.stopManager = nil
 	}
+	t.mu.Unlock()
 }
 
 // Reset the token.
-// Essentially creates a new token, transfers all nescessaary data and overwrites the old one.
+// Clears the tokens and data, and deletes the token from its store.
+// The settings, callbacks and store of the token are kept.
+// A token shared with other tabs is reset in all tabs.
 func (t *Token) Reset() *Token {
-	var urls = t.URLs
+	return t.reset(true)
+}
+
+func (t *Token) reset(share bool) *Token {
 	if t.onReset != nil {
 		t.onReset()
 	}
 	t.StopManager()
-	DeleteTokenCookie()
+	t.Store().Delete()
+	t.mu.Lock()
+	t.AccessToken = ""
+	t.RefreshToken = ""
+	t.LastUpdate = time.Time{}
+	t.Data = make(map[string]interface{})
+	t.refreshing = nil
+	t.mu.Unlock()
+	if share {
+		t.share()
+	}
+	return t
+}
+
+// Create a new token with the settings and callbacks of the token,
+// without its tokens, data and store.
+func (t *Token) Copy() *Token {
 	var newt = NewToken(t.RefreshTimeout, t.AccessTimeout, t.AccessTokenVariable, t.RefreshTokenVariable, t.errorMessageName)
 	newt.OnInit(t.onInit)
 	newt.OnUpdate(t.onUpdate)
 	newt.OnReset(t.onReset)
-	newt.SetURLs(urls)
-	reflect.ValueOf(t).Elem().Set(reflect.ValueOf(newt).Elem())
-	return t
+	newt.OnUpdateError(t.onUpdateErr)
+	newt.OnSessionExpired(t.onSessionExpired)
+	newt.RefreshWith(t.refreshWith)
+	newt.SetURLs(t.URLs)
+	newt.Schema = t.Schema
+	newt.Transport = t.Transport
+	newt.PersistData(t.persistData)
+	newt.AutoSave(t.autoSave)
+	newt.ClockSkew = t.ClockSkew
+	return newt
 }
@@ -1,139 +1,147 @@
-//go:build !tinygo && js && wasm
-// +build !tinygo,js,wasm
+//go:build !tinygo
+// +build !tinygo
 
 package tokens
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
+	"io"
 	"net/http"
 	"time"
 
-	"github.com/Nigel2392/jsext/requester"
+	"github.com/Nigel2392/jsext-framework/tokens/schema"
 )
 
 // Make an api call to the refresh URL, update both the access and refresh tokens.
 func (t *Token) Update() error {
-	var client = requester.NewAPIClient()
-	var data = map[string]any{
-		t.RefreshTokenVariable: t.RefreshToken,
-	}
-	client = client.Post(t.URLs.RefreshURL).WithData(data, requester.JSON)
-	var datamap map[string]string
-	var errChan = make(chan error)
-	client.Do(func(resp *http.Response) {
-		var err = json.NewDecoder(resp.Body).Decode(&datamap)
-		if err != nil {
-			errChan <- err
-			return
-		}
-		t.AccessToken = datamap[t.AccessTokenVariable]
-		t.RefreshToken = datamap[t.RefreshTokenVariable]
-		t.LastUpdate = time.Now()
-		errChan <- nil
-	})
-	var err = <-errChan
+	return t.UpdateContext(context.Background())
+}
+
+// Make an api call to the refresh URL with the context, update both the access and refresh tokens.
+func (t *Token) UpdateContext(ctx context.Context) error {
+	var s = t.schema()
+	var _, refresh, _ = t.current()
+	var status, contentType, body, err = t.post(ctx, t.URLs.RefreshURL, "", s.RefreshRequest(schema.Refresh, refresh))
+	if err != nil {
+		return err
+	}
+	result, err := s.Response(status, contentType, body)
 	if err != nil {
 		return err
 	}
-	if t.onUpdate != nil {
-		t.onUpdate(t)
+	if result.Refresh != "" {
+		refresh = result.Refresh
 	}
+	t.set(result.Access, refresh, time.Now())
+	t.updated()
 	return nil
 }
 
-// Token's client, when authenticated it will automatically set the Authorization header.
-func (t *Token) Client() *requester.APIClient {
-	var client = requester.NewAPIClient()
-	client.OnError(func(err error) bool {
-		println(err.Error())
-		return true
-	})
-	if t.AccessToken == "" {
-		return client
-	}
-	client = client.WithHeaders(map[string][]string{
-		"Authorization": {"Bearer " + t.AccessToken},
-	})
-	return client
+// HTTP client for the requests of the token, using the transport of the token.
+// The transport defaults to http.DefaultTransport.
+func (t *Token) httpClient() *http.Client {
+	var transport = t.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	return &http.Client{Transport: transport}
+}
+
+// Post a JSON body to the URL, and read the response.
+// The access token is sent in the Authorization header when it is not empty.
+func (t *Token) post(ctx context.Context, url, access string, body map[string]any) (status int, contentType string, data []byte, err error) {
+	b, err := json.Marshal(body)
+	if err != nil {
+		return 0, "", nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(b))
+	if err != nil {
+		return 0, "", nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	if access != "" {
+		req.Header.Set("Authorization", "Bearer "+access)
+	}
+	resp, err := t.httpClient().Do(req)
+	if err != nil {
+		return 0, "", nil, err
+	}
+	defer resp.Body.Close()
+	data, err = io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, "", nil, err
+	}
+	return resp.StatusCode, resp.Header.Get("Content-Type"), data, nil
 }
 
 // Send data to an API endpoint, get both access and refresh tokens.
-func (t *Token) sendDataGetToken(data map[string]any, url string) error {
-	var client = requester.NewAPIClient()
-	client = client.Post(url)
-	client.OnError(func(err error) bool {
-		println(err.Error())
-		return true
-	})
-	client.WithData(data, requester.JSON)
-	var datamap map[string]interface{}
-	var errChan = make(chan error)
-	client.Do(func(resp *http.Response) {
-		var err = json.NewDecoder(resp.Body).Decode(&datamap)
-		if err != nil {
-			errChan <- err
-			return
-		}
-		if err, ok := datamap[t.errorMessageName]; ok {
-			errChan <- errors.New(err.(string))
-			return
-		}
-		var AccessToken = datamap[t.AccessTokenVariable].(string)
-		var RefreshToken = datamap[t.RefreshTokenVariable].(string)
-		var LastUpdate = time.Now()
-		delete(datamap, t.AccessTokenVariable)
-		delete(datamap, t.RefreshTokenVariable)
-		t.Data = datamap
-		t.setToken(AccessToken, RefreshToken, LastUpdate)
-		if t.onInit != nil {
-			t.onInit(t)
-		}
-		errChan <- nil
-	})
-	return <-errChan
+func (t *Token) sendDataGetToken(ctx context.Context, kind schema.Kind, data map[string]any, url string) error {
+	var s = t.schema()
+	var status, contentType, body, err = t.post(ctx, url, "", s.Request(kind, data))
+	if err != nil {
+		return err
+	}
+	result, err := s.Response(status, contentType, body)
+	if err != nil {
+		return err
+	}
+	t.Data = result.Data
+	t.setToken(result.Access, result.Refresh, time.Now())
+	if t.onInit != nil {
+		t.onInit(t)
+	}
+	return nil
 }
 
 // Login with the appropriate data, get both access and refresh tokens.
 func (t *Token) Login(loginData map[string]string) error {
+	return t.LoginContext(context.Background(), loginData)
+}
+
+// Login with the appropriate data and the context, get both access and refresh tokens.
+func (t *Token) LoginContext(ctx context.Context, loginData map[string]string) error {
 	newMap := make(map[string]any, len(loginData))
 	for k, v := range loginData {
 		newMap[k] = v
 	}
-	return t.sendDataGetToken(newMap, t.URLs.LoginURL)
+	return t.sendDataGetToken(ctx, schema.Login, newMap, t.URLs.LoginURL)
 }
 
 // Register with the appropriate data, get both access and refresh tokens.
 func (t *Token) Register(registerData map[string]string) error {
+	return t.RegisterContext(context.Background(), registerData)
+}
+
+// Register with the appropriate data and the context, get both access and refresh tokens.
+func (t *Token) RegisterContext(ctx context.Context, registerData map[string]string) error {
 	newMap := make(map[string]any, len(registerData))
 	for k, v := range registerData {
 		newMap[k] = v
 	}
-	return t.sendDataGetToken(newMap, t.URLs.RegisterURL)
+	return t.sendDataGetToken(ctx, schema.Register, newMap, t.URLs.RegisterURL)
 }
 
 // Logout with the refresh token.
 func (t *Token) Logout() error {
+	return t.LogoutContext(context.Background())
+}
+
+// Logout with the refresh token and the context.
+// The token is reset, even if the request fails.
+func (t *Token) LogoutContext(ctx context.Context) error {
 	if t.AccessToken == "" || t.RefreshToken == "" || t.URLs.LogoutURL == "" {
 		//lint:ignore ST1005 Error strings should not be capitalized
 		return errors.New("Already logged out")
 	}
-	var client = t.Client()
-	client = client.Post(t.URLs.LogoutURL)
-	client.WithData(map[string]any{
-		t.RefreshTokenVariable: t.RefreshToken,
-	}, requester.JSON)
-	var errChan = make(chan error)
-	var respMap map[string]any
-	client.DoDecodeTo(&respMap, requester.JSON, func(r *http.Response, s any) {
-		var err, ok = respMap[t.errorMessageName]
-		if ok {
-			errChan <- errors.New(err.(string))
-			return
-		}
-		errChan <- nil
-	})
-	var err = <-errChan
+	var s = t.schema()
+	var status, contentType, body, err = t.post(ctx, t.URLs.LogoutURL, t.AccessToken, s.RefreshRequest(schema.Logout, t.RefreshToken))
+	if err == nil {
+		err = s.Error(status, contentType, body)
+	}
 	t.Reset()
 	return err
 }
@@ -0,0 +1,473 @@
+package tokens_test
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Nigel2392/jsext-framework/client"
+	"github.com/Nigel2392/jsext-framework/offline"
+	"github.com/Nigel2392/jsext-framework/tokens"
+	"github.com/Nigel2392/jsext-framework/tokens/schema"
+)
+
+// Stand-in for an authentication API.
+type server struct {
+	*httptest.Server
+	// Response of the refresh endpoint, a new access token by default.
+	refresh http.HandlerFunc
+	logout  string
+}
+
+func newServer(t *testing.T) *server {
+	var s = &server{}
+	var mux = http.NewServeMux()
+	mux.HandleFunc("/login", func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		json.NewDecoder(r.Body).Decode(&body)
+		if body["password"] != "secret" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"detail": "Invalid credentials"}`))
+			return
+		}
+		w.Write([]byte(`{"access": "access-1", "refresh": "refresh-1", "user": "admin"}`))
+	})
+	mux.HandleFunc("/refresh", func(w http.ResponseWriter, r *http.Request) {
+		if s.refresh != nil {
+			s.refresh(w, r)
+			return
+		}
+		w.Write([]byte(`{"access": "access-2"}`))
+	})
+	mux.HandleFunc("/logout", func(w http.ResponseWriter, r *http.Request) {
+		s.logout = r.Header.Get("Authorization")
+	})
+	mux.HandleFunc("/api", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer access-2" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`ok`))
+	})
+	mux.HandleFunc("/slow", func(w http.ResponseWriter, r *http.Request) {
+		// The server notices the client going away once the body was read.
+		io.Copy(io.Discard, r.Body)
+		<-r.Context().Done()
+	})
+	s.Server = httptest.NewServer(mux)
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *server) token() *tokens.Token {
+	var t = tokens.NewToken(time.Hour, time.Minute, "access", "refresh", "detail")
+	t.SetURLs(tokens.TokenURLs{
+		LoginURL:    s.URL + "/login",
+		RegisterURL: s.URL + "/register",
+		RefreshURL:  s.URL + "/refresh",
+		LogoutURL:   s.URL + "/logout",
+	})
+	t.Transport = s.Client().Transport
+	return t
+}
+
+func TestLogin(t *testing.T) {
+	var s = newServer(t)
+	var token = s.token()
+	if err := token.LoginContext(context.Background(), map[string]string{"username": "admin", "password": "secret"}); err != nil {
+		t.Fatal(err)
+	}
+	// Refresh by hand, instead of in the background.
+	token.StopManager()
+	if token.AccessToken != "access-1" || token.RefreshToken != "refresh-1" || token.Data["user"] != "admin" {
+		t.Fatalf("got %q %q %v", token.AccessToken, token.RefreshToken, token.Data)
+	}
+	if err := token.Update(); err != nil {
+		t.Fatal(err)
+	}
+	if token.AccessToken != "access-2" || token.RefreshToken != "refresh-1" {
+		t.Errorf("got %q %q after update", token.AccessToken, token.RefreshToken)
+	}
+	if err := token.Logout(); err != nil {
+		t.Fatal(err)
+	}
+	if s.logout != "Bearer access-2" {
+		t.Errorf("logout sent %q", s.logout)
+	}
+	if token.AccessToken != "" {
+		t.Error("token not reset after logout")
+	}
+}
+
+func TestErrors(t *testing.T) {
+	var s = newServer(t)
+
+	var token = s.token()
+	var err = token.Login(map[string]string{"username": "admin", "password": "wrong"})
+	var e *schema.Error
+	if !errors.As(err, &e) || e.Status != http.StatusBadRequest || e.Message != "Invalid credentials" {
+		t.Errorf("got %v", err)
+	}
+
+	token = s.token()
+	token.URLs.LoginURL = s.URL + "/slow"
+	var ctx, cancel = context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := token.LoginContext(ctx, nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("got %v, expected the deadline to be exceeded", err)
+	}
+
+	var errNetwork = errors.New("network down")
+	token = s.token()
+	token.Transport = client.RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		return nil, errNetwork
+	})
+	if err := token.Login(map[string]string{"password": "secret"}); !errors.Is(err, errNetwork) {
+		t.Errorf("got %v, expected the transport error", err)
+	}
+
+	token = s.token()
+	s.Close()
+	if err := token.Login(map[string]string{"password": "secret"}); err == nil {
+		t.Error("expected an error from a closed server")
+	}
+}
+
+func TestInterceptor(t *testing.T) {
+	var tests = []struct {
+		name    string
+		refresh http.HandlerFunc
+		status  int
+		expired bool
+	}{
+		{"refreshed", nil, http.StatusOK, false},
+		{"rejected", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"detail": "Token is invalid"}`))
+		}, http.StatusUnauthorized, true},
+		{"server error", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadGateway)
+		}, http.StatusUnauthorized, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var s = newServer(t)
+			s.refresh = test.refresh
+			var token = s.token()
+			if err := token.Login(map[string]string{"password": "secret"}); err != nil {
+				t.Fatal(err)
+			}
+			token.StopManager()
+			var expired bool
+			token.OnSessionExpired(func() { expired = true })
+
+			var c = &http.Client{Transport: token.Interceptor()(s.Client().Transport)}
+			var resp, err = c.Get(s.URL + "/api")
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != test.status {
+				t.Errorf("got status %d", resp.StatusCode)
+			}
+			if expired != test.expired {
+				t.Errorf("session expired: %v", expired)
+			}
+		})
+	}
+}
+
+func TestAuthorizeOfflineReplay(t *testing.T) {
+	var s = newServer(t)
+	var token = s.token()
+	if err := token.Login(map[string]string{"password": "secret"}); err != nil {
+		t.Fatal(err)
+	}
+	token.StopManager()
+	var authToken = tokens.AuthToken
+	tokens.AuthToken = token
+	t.Cleanup(func() { tokens.AuthToken = authToken })
+
+	// Wired like Application.EnableOffline, the queue is added after the token interceptor.
+	var status = offline.NewStatus(false)
+	var replayed int
+	var q, err = offline.NewQueue(&offline.QueueOptions{
+		Status:    status,
+		Authorize: tokens.Authorize(tokens.Current),
+		OnReplayed: func(r *offline.Request, resp *http.Response) {
+			replayed = resp.StatusCode
+		},
+		OnFailed: func(r *offline.Request, resp *http.Response) {
+			t.Errorf("replay failed with status %d", resp.StatusCode)
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	q.Close()
+	var c = client.New(token.Interceptor(), q.Interceptor())
+	c.Transport = s.Client().Transport
+	if err := c.Post(context.Background(), s.URL+"/api", "body", nil); err != nil {
+		t.Fatal(err)
+	}
+	if q.Len() != 1 || q.Requests()[0].Header.Get("Authorization") != "" {
+		t.Fatal("expected the request to be queued without credentials")
+	}
+
+	// The access token expired while offline, the replay refreshes it.
+	status.Set(true)
+	if err := q.Replay(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if replayed != http.StatusOK || q.Len() != 0 {
+		t.Errorf("replayed with status %d, %d requests left", replayed, q.Len())
+	}
+}
+
+func TestConcurrentRefresh(t *testing.T) {
+	var s = newServer(t)
+	var refreshes int32
+	var release = make(chan struct{})
+	s.refresh = func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&refreshes, 1)
+		<-release
+		w.Write([]byte(`{"access": "access-2"}`))
+	}
+	var token = s.token()
+	if err := token.Login(map[string]string{"password": "secret"}); err != nil {
+		t.Fatal(err)
+	}
+	token.StopManager()
+
+	// The first caller gives up, the refresh it started is shared with the others and continues.
+	var ctx, cancel = context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := token.RefreshContext(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("got %v, expected the deadline to be exceeded", err)
+	}
+
+	var c = &http.Client{Transport: token.Interceptor()(s.Client().Transport)}
+	var wg sync.WaitGroup
+	var statuses = make(chan int, 20)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var resp, err = c.Get(s.URL + "/api")
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			resp.Body.Close()
+			statuses <- resp.StatusCode
+		}()
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	close(statuses)
+	for status := range statuses {
+		if status != http.StatusOK {
+			t.Errorf("got status %d", status)
+		}
+	}
+	if n := atomic.LoadInt32(&refreshes); n != 1 {
+		t.Errorf("refreshed %d times, expected once", n)
+	}
+}
+
+func TestOpaqueRefreshToken(t *testing.T) {
+	var s = newServer(t)
+	var token = s.token()
+	// The refresh token is opaque, and no refresh timeout is set, so its expiry is unknown.
+	token.RefreshTimeout = 0
+	token.SetStore(tokens.NewMemoryStore())
+	token.AccessToken = jwtWith(map[string]time.Duration{"iat": 0, "exp": time.Hour})
+	token.RefreshToken = "refresh-1"
+	token.LastUpdate = time.Now()
+	if token.IsRefreshExpired() || !token.RefreshExpiresAt().IsZero() || token.RefreshExpiredIn() != 0 {
+		t.Fatalf("refresh token with an unknown expiry expires at %v", token.RefreshExpiresAt())
+	}
+	if !token.IsValid() || token.IsExpired() || token.ShouldUpdate() {
+		t.Fatal("expected a valid access token which does not need an update yet")
+	}
+
+	// Saved without an expiry.
+	if err := token.Save(); err != nil {
+		t.Fatal(err)
+	}
+	var loaded = s.token()
+	loaded.SetStore(token.Store())
+	if err := loaded.Load(); err != nil || loaded.RefreshToken != "refresh-1" {
+		t.Fatalf("got %v, %q after loading", err, loaded.RefreshToken)
+	}
+
+	// The API rejects the access token, the interceptor refreshes it instead of ending the session.
+	var expired bool
+	token.OnSessionExpired(func() { expired = true })
+	var c = &http.Client{Transport: token.Interceptor()(s.Client().Transport)}
+	var resp, err = c.Get(s.URL + "/api")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || expired || token.AccessToken != "access-2" {
+		t.Errorf("got status %d, session expired: %v", resp.StatusCode, expired)
+	}
+}
+
+func TestConcurrentReset(t *testing.T) {
+	var s = newServer(t)
+	var token = s.token()
+	if err := token.Login(map[string]string{"password": "secret"}); err != nil {
+		t.Fatal(err)
+	}
+	token.StopManager()
+	var done = make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			token.Tokens()
+			token.IsValid()
+		}
+	}()
+	token.Reset()
+	<-done
+	if access, refresh := token.Tokens(); access != "" || refresh != "" || len(token.Data) != 0 {
+		t.Errorf("got %q %q %v after reset", access, refresh, token.Data)
+	}
+	if token.URLs.RefreshURL != s.URL+"/refresh" || token.Transport == nil {
+		t.Error("expected the settings to be kept")
+	}
+}
+
+type ctxKey struct{}
+
+func TestRefreshWith(t *testing.T) {
+	var s = newServer(t)
+	var token = s.token()
+	if err := token.Login(map[string]string{"password": "secret"}); err != nil {
+		t.Fatal(err)
+	}
+	token.StopManager()
+	var refreshes int32
+	token.RefreshWith(func(ctx context.Context, tok *tokens.Token) error {
+		atomic.AddInt32(&refreshes, 1)
+		if ctx.Value(ctxKey{}) != "value" {
+			t.Error("expected the context of the caller")
+		}
+		if _, refresh := tok.Tokens(); refresh != "refresh-1" {
+			t.Errorf("refreshing with %q", refresh)
+		}
+		time.Sleep(10 * time.Millisecond)
+		// Without a new refresh token, the current one is kept.
+		tok.SetAccessTimeout(2 * time.Minute)
+		tok.SetTokens("access-2", "")
+		return nil
+	})
+
+	// The token is read by requests and the manager while it is refreshed.
+	var ctx = context.WithValue(context.Background(), ctxKey{}, "value")
+	var c = &http.Client{Transport: token.Interceptor()(s.Client().Transport)}
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if err := token.RefreshContext(ctx); err != nil {
+			t.Error(err)
+		}
+	}()
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			token.ShouldUpdate()
+			token.IsValid()
+			// A refresh after a 401 uses the context of the request.
+			var req, _ = http.NewRequestWithContext(ctx, http.MethodGet, s.URL+"/api", nil)
+			var resp, err = c.Do(req)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			resp.Body.Close()
+		}()
+	}
+	wg.Wait()
+	if access, refresh := token.Tokens(); access != "access-2" || refresh != "refresh-1" {
+		t.Errorf("got %q %q", access, refresh)
+	}
+	if n := atomic.LoadInt32(&refreshes); n < 1 {
+		t.Error("expected a refresh")
+	}
+}
+
+// Unsigned JWT with the claims, as Unix times relative to now.
+func jwtWith(claims map[string]time.Duration) string {
+	var payload = make(map[string]int64, len(claims))
+	for k, d := range claims {
+		payload[k] = time.Now().Add(d).Unix()
+	}
+	var b, _ = json.Marshal(payload)
+	var enc = base64.RawURLEncoding.EncodeToString
+	return enc([]byte(`{"alg":"HS256","typ":"JWT"}`)) + "." + enc(b) + ".c2lnbmF0dXJl"
+}
+
+func TestLifetime(t *testing.T) {
+	var tests = []struct {
+		name   string
+		access string
+		// Access timeout, a minute if zero.
+		timeout time.Duration
+		// Time of the last update relative to now, zero time if -1.
+		lastUpdate time.Duration
+		// Expected time until the token expires and should be updated, within a second.
+		expiresIn time.Duration
+		updateIn  time.Duration
+		valid     bool
+	}{
+		{"opaque", "opaque", 0, 0, time.Minute, 54 * time.Second, true},
+		{"opaque expired", "opaque", 0, -2 * time.Minute, -time.Minute, -66 * time.Second, false},
+		{"jwt", jwtWith(map[string]time.Duration{"iat": 0, "exp": time.Hour}), 0, 0, time.Hour - tokens.DefaultClockSkew, 53*time.Minute + 33*time.Second, true},
+		// The skew is at most a tenth of the lifetime, a fresh short-lived token is valid.
+		{"short jwt", jwtWith(map[string]time.Duration{"iat": 0, "exp": 20 * time.Second}), 0, 0, 18 * time.Second, 16 * time.Second, true},
+		{"expired jwt", jwtWith(map[string]time.Duration{"iat": -time.Hour, "exp": -time.Minute}), 0, 0, -time.Minute - tokens.DefaultClockSkew, -7*time.Minute - 21*time.Second, false},
+		// Without an iat claim, the last update is the issued time.
+		{"jwt without iat", jwtWith(map[string]time.Duration{"exp": 20 * time.Second}), 0, 0, 18 * time.Second, 16 * time.Second, true},
+		// Without an iat claim or last update, the token was issued the access timeout before it expires.
+		{"jwt without issued time", jwtWith(map[string]time.Duration{"exp": 20 * time.Second}), 20 * time.Second, -1, 18 * time.Second, 16 * time.Second, true},
+		{"not yet valid", jwtWith(map[string]time.Duration{"iat": 0, "nbf": time.Minute, "exp": time.Hour}), 0, 0, time.Hour - tokens.DefaultClockSkew, 53*time.Minute + 33*time.Second, false},
+		// The not-before time is moved back by the skew.
+		{"valid within skew", jwtWith(map[string]time.Duration{"iat": 0, "nbf": time.Second, "exp": time.Hour}), 0, 0, time.Hour - tokens.DefaultClockSkew, 53*time.Minute + 33*time.Second, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var token = tokens.NewToken(time.Hour, time.Minute, "access", "refresh", "detail")
+			if test.timeout != 0 {
+				token.AccessTimeout = test.timeout
+			}
+			token.AccessToken = test.access
+			token.RefreshToken = "refresh"
+			if test.lastUpdate != -1 {
+				token.LastUpdate = time.Now().Add(test.lastUpdate)
+			}
+			if d := token.ExpiredIn() - test.expiresIn; d < -time.Second || d > time.Second {
+				t.Errorf("expires in %v, want %v", token.ExpiredIn(), test.expiresIn)
+			}
+			if d := token.UpdateIn() - test.updateIn; d < -time.Second || d > time.Second {
+				t.Errorf("update in %v, want %v", token.UpdateIn(), test.updateIn)
+			}
+			if token.IsValid() != test.valid {
+				t.Errorf("valid: %v", token.IsValid())
+			}
+		})
+	}
+}
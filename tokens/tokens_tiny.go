@@ -4,47 +4,78 @@
 package tokens
 
 import (
+	"context"
 	"errors"
 	"time"
 
+	"github.com/Nigel2392/jsext-framework/tokens/schema"
 	"github.com/Nigel2392/jsext/requester"
-	"github.com/Nigel2392/jsext/requester/decoder"
+	"github.com/Nigel2392/jsext/requester/fetch"
 )
 
 // Make an api call to the refresh URL, update both the access and refresh tokens.
 func (t *Token) Update() error {
-	var client = requester.NewAPIClient()
-	var data = map[string]any{
-		t.RefreshTokenVariable: t.RefreshToken,
-	}
-	client = client.Post(t.URLs.RefreshURL).WithData(data, requester.JSON)
-	var resp, err = client.Do()
+	return t.UpdateContext(context.Background())
+}
+
+// Make an api call to the refresh URL with the context, update both the access and refresh tokens.
+func (t *Token) UpdateContext(ctx context.Context) error {
+	var s = t.schema()
+	var _, refresh, _ = t.current()
+	var resp, err = withContext(ctx, func() (*fetch.Response, error) {
+		return t.post(requester.NewAPIClient(), t.URLs.RefreshURL, s.RefreshRequest(schema.Refresh, refresh))
+	})
 	if err != nil {
 		return err
 	}
-	var datamap, ok = decoder.ParseBytes(resp.Body).Value().(map[string]interface{})
-	if !ok {
-		return errors.New("could not parse response")
-	}
-	if err, ok := datamap[t.errorMessageName]; ok {
-		return errors.New(err.(string))
+	result, err := s.Response(resp.StatusCode, contentType(resp), resp.Body)
+	if err != nil {
+		return err
 	}
-	AccessToken, ok := datamap[t.AccessTokenVariable].(string)
-	if !ok {
-		return errors.New("could not parse response")
+	if result.Refresh != "" {
+		refresh = result.Refresh
 	}
-	RefreshToken, ok := datamap[t.RefreshTokenVariable].(string)
-	if !ok {
-		return errors.New("could not parse response")
+	t.set(result.Access, refresh, time.Now())
+	t.updated()
+	return nil
+}
+
+// Wait for a request of the token, returning when it is done or the context is done.
+// Fetch requests cannot be cancelled in TinyGo builds, the context only stops the wait:
+// the request still reaches the server, but its response is discarded.
+// The transport of the token is not used.
+func withContext(ctx context.Context, f func() (*fetch.Response, error)) (*fetch.Response, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	type result struct {
+		resp *fetch.Response
+		err  error
+	}
+	var done = make(chan result, 1)
+	go func() {
+		var resp, err = f()
+		done <- result{resp, err}
+	}()
+	select {
+	case r := <-done:
+		return r.resp, r.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
 	}
-	var LastUpdate = time.Now()
-	t.AccessToken = AccessToken
-	t.RefreshToken = RefreshToken
-	t.LastUpdate = LastUpdate
-	if t.onUpdate != nil {
-		t.onUpdate(t)
+}
+
+// Post a body to the URL.
+func (t *Token) post(client *requester.APIClient, url string, body map[string]any) (*fetch.Response, error) {
+	return client.Post(url).WithData(body, requester.JSON).Do()
+}
+
+// Content type of a response, header names are lower case in the fetch API.
+func contentType(resp *fetch.Response) string {
+	if ct, ok := resp.Headers["content-type"]; ok {
+		return ct
 	}
-	return nil
+	return resp.Headers["Content-Type"]
 }
 
 // Token's client, when authenticated it will automatically set the Authorization header.
@@ -60,28 +91,20 @@ func (t *Token) Client() *requester.APIClient {
 }
 
 // Send data to an API endpoint, get both access and refresh tokens.
-func (t *Token) sendDataGetToken(data map[string]any, url string) error {
-	var client = requester.NewAPIClient()
-	client = client.Post(url)
-	client.WithData(data, requester.JSON)
-	var resp, err = client.Do()
+func (t *Token) sendDataGetToken(ctx context.Context, kind schema.Kind, data map[string]any, url string) error {
+	var s = t.schema()
+	var resp, err = withContext(ctx, func() (*fetch.Response, error) {
+		return t.post(requester.NewAPIClient(), url, s.Request(kind, data))
+	})
 	if err != nil {
 		return err
 	}
-	var datamap, ok = decoder.ParseBytes(resp.Body).Value().(map[string]interface{})
-	if !ok {
-		return errors.New("could not parse response")
-	}
-	if err, ok := datamap[t.errorMessageName]; ok {
-		return errors.New(err.(string))
+	result, err := s.Response(resp.StatusCode, contentType(resp), resp.Body)
+	if err != nil {
+		return err
 	}
-	var AccessToken = datamap[t.AccessTokenVariable].(string)
-	var RefreshToken = datamap[t.RefreshTokenVariable].(string)
-	var LastUpdate = time.Now()
-	delete(datamap, t.AccessTokenVariable)
-	delete(datamap, t.RefreshTokenVariable)
-	t.Data = datamap
-	t.setToken(AccessToken, RefreshToken, LastUpdate)
+	t.Data = result.Data
+	t.setToken(result.Access, result.Refresh, time.Now())
 	if t.onInit != nil {
 		t.onInit(t)
 	}
@@ -90,44 +113,56 @@ func (t *Token) sendDataGetToken(data map[string]any, url string) error {
 
 // Login with the appropriate data, get both access and refresh tokens.
 func (t *Token) Login(loginData map[string]string) error {
+	return t.LoginContext(context.Background(), loginData)
+}
+
+// Login with the appropriate data and the context, get both access and refresh tokens.
+// The tokens are not set when the context is done before the response arrives.
+func (t *Token) LoginContext(ctx context.Context, loginData map[string]string) error {
 	newMap := make(map[string]any, len(loginData))
 	for k, v := range loginData {
 		newMap[k] = v
 	}
-	return t.sendDataGetToken(newMap, t.URLs.LoginURL)
+	return t.sendDataGetToken(ctx, schema.Login, newMap, t.URLs.LoginURL)
 }
 
 // Register with the appropriate data, get both access and refresh tokens.
 func (t *Token) Register(registerData map[string]string) error {
+	return t.RegisterContext(context.Background(), registerData)
+}
+
+// Register with the appropriate data and the context, get both access and refresh tokens.
+// The tokens are not set when the context is done before the response arrives.
+func (t *Token) RegisterContext(ctx context.Context, registerData map[string]string) error {
 	newMap := make(map[string]any, len(registerData))
 	for k, v := range registerData {
 		newMap[k] = v
 	}
-	return t.sendDataGetToken(newMap, t.URLs.RegisterURL)
+	return t.sendDataGetToken(ctx, schema.Register, newMap, t.URLs.RegisterURL)
 }
 
 // Logout with the refresh token.
+// The token is reset, even if the request fails.
 func (t *Token) Logout() error {
+	return t.LogoutContext(context.Background())
+}
+
+// Logout with the refresh token and the context.
+// The token is reset, even if the request fails or the context is done first.
+func (t *Token) LogoutContext(ctx context.Context) error {
 	if t.AccessToken == "" || t.RefreshToken == "" || t.URLs.LogoutURL == "" {
 		//lint:ignore ST1005 Error strings should not be capitalized
 		return errors.New("Already logged out")
 	}
+	var s = t.schema()
 	var client = t.Client()
-	client = client.Post(t.URLs.LogoutURL)
-	client.WithData(map[string]any{
-		t.RefreshTokenVariable: t.RefreshToken,
-	}, requester.JSON)
-	var resp, err = client.Do()
-	if err != nil {
-		return err
-	}
-	var respMap, ok = decoder.ParseBytes(resp.Body).Value().(map[string]interface{})
-	if !ok {
-		return errors.New("could not parse response")
-	}
-	if err, ok := respMap[t.errorMessageName]; ok {
-		return errors.New(err.(string))
+	var body = s.RefreshRequest(schema.Logout, t.RefreshToken)
+	var resp, err = withContext(ctx, func() (*fetch.Response, error) {
+		return t.post(client, t.URLs.LogoutURL, body)
+	})
+	if err == nil {
+		err = s.Error(resp.StatusCode, contentType(resp), resp.Body)
 	}
 	t.Reset()
-	return nil
+	return err
 }
@@ -0,0 +1,22 @@
+//go:build !tinygo && js && wasm
+// +build !tinygo,js,wasm
+
+package tokens
+
+import "github.com/Nigel2392/jsext/requester"
+
+// Token's client, when authenticated it will automatically set the Authorization header.
+func (t *Token) Client() *requester.APIClient {
+	var client = requester.NewAPIClient()
+	client.OnError(func(err error) bool {
+		println(err.Error())
+		return true
+	})
+	if t.AccessToken == "" {
+		return client
+	}
+	client = client.WithHeaders(map[string][]string{
+		"Authorization": {"Bearer " + t.AccessToken},
+	})
+	return client
+}